@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"manticore-mcp-server/config"
@@ -16,10 +20,100 @@ import (
 
 //go:generate moq -out client_mock.go . ManticoreClient
 
+// Endpoints of the Manticore HTTP API
+const (
+	sqlEndpoint    = "/sql?mode=raw"
+	searchEndpoint = "/search"
+	bulkEndpoint   = "/bulk"
+)
+
 // ManticoreClient defines the interface for Manticore Search operations
 type ManticoreClient interface {
 	ExecuteSQL(ctx context.Context, query string) ([]map[string]interface{}, error)
+	ExecuteSQLResults(ctx context.Context, query string) ([]SQLResult, error)
+	Search(ctx context.Context, query map[string]interface{}) (*SearchResponse, error)
+	Bulk(ctx context.Context, actions []map[string]interface{}) (*BulkResponse, error)
+	KillQuery(ctx context.Context, marker string) (int, error)
 	Ping(ctx context.Context) error
+	// DryRun reports whether requests are only recorded, so results are empty by design
+	DryRun() bool
+}
+
+// SQLResult represents a single result set returned by the SQL endpoint
+type SQLResult struct {
+	Columns      []SQLColumn              `json:"columns,omitempty"`
+	Rows         []map[string]interface{} `json:"rows"`
+	Total        int                      `json:"total"`
+	AffectedRows int                      `json:"affected_rows,omitempty"`
+	Error        string                   `json:"error,omitempty"`
+	Warning      string                   `json:"warning,omitempty"`
+}
+
+// SQLColumn describes a result set column
+type SQLColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// rawSQLResult mirrors a result set as returned by /sql?mode=raw
+type rawSQLResult struct {
+	Columns []map[string]struct {
+		Type string `json:"type"`
+	} `json:"columns"`
+	Data    []map[string]interface{} `json:"data"`
+	Total   int                      `json:"total"`
+	Error   string                   `json:"error"`
+	Warning string                   `json:"warning"`
+}
+
+// SearchResponse represents the response of the /search JSON endpoint
+type SearchResponse struct {
+	Took         int                          `json:"took"`
+	TimedOut     bool                         `json:"timed_out"`
+	Hits         SearchHits                   `json:"hits"`
+	Aggregations map[string]SearchAggregation `json:"aggregations,omitempty"`
+	Warning      any                          `json:"warning,omitempty"`
+}
+
+// SearchAggregation holds the buckets computed for one aggregation of a search
+type SearchAggregation struct {
+	Buckets []map[string]interface{} `json:"buckets"`
+}
+
+// SearchHits holds matched documents and totals computed by Manticore
+type SearchHits struct {
+	Total         int         `json:"total"`
+	TotalRelation string      `json:"total_relation"`
+	Hits          []SearchHit `json:"hits"`
+}
+
+// SearchHit represents a single matched document
+type SearchHit struct {
+	ID        uint64                 `json:"_id"`
+	Score     float64                `json:"_score"`
+	KNNDist   *float64               `json:"_knn_dist,omitempty"`
+	Source    map[string]interface{} `json:"_source"`
+	Highlight map[string][]string    `json:"highlight,omitempty"`
+}
+
+// BulkResponse represents the response of the /bulk NDJSON endpoint. Manticore reports one
+// item per action or per transaction of consecutive actions on the same table, and on errors
+// the line processing stopped at together with the number of lines that were not applied.
+type BulkResponse struct {
+	Items        []map[string]BulkItem `json:"items"`
+	CurrentLine  int                   `json:"current_line"`
+	SkippedLines int                   `json:"skipped_lines"`
+	Errors       bool                  `json:"errors"`
+	Error        string                `json:"error,omitempty"`
+}
+
+// BulkItem is the outcome of an action or transaction of a bulk request
+type BulkItem struct {
+	Table  string `json:"table"`
+	ID     uint64 `json:"_id"`
+	Result string `json:"result,omitempty"`
+	Status int    `json:"status"`
+	Error  any    `json:"error,omitempty"`
 }
 
 // Client provides access to Manticore Search API
@@ -29,6 +123,7 @@ type Client struct {
 	logger     *slog.Logger
 	maxRetries int
 	retryDelay time.Duration
+	readOnly   bool
 }
 
 // New creates a new Manticore client
@@ -41,45 +136,215 @@ func New(cfg *config.Config, logger *slog.Logger) ManticoreClient {
 		logger:     logger,
 		maxRetries: cfg.MaxRetries,
 		retryDelay: cfg.RetryDelay,
+		readOnly:   cfg.ReadOnly,
 	}
 }
 
-// ExecuteSQL executes a SQL query against Manticore
+// ExecuteSQL executes a SQL query against Manticore and returns rows of the first result set
 func (c *Client) ExecuteSQL(ctx context.Context, query string) ([]map[string]interface{}, error) {
-	endpoint := "/sql?mode=raw"
+	results, err := c.ExecuteSQLResults(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+
+	if results[0].Error != "" {
+		return nil, fmt.Errorf("SQL request failed: %s", results[0].Error)
+	}
 
-	respBody, err := c.doRawRequest(ctx, "POST", endpoint, query)
+	return results[0].Rows, nil
+}
+
+// ExecuteSQLResults executes one or more SQL statements and returns every result set
+// together with its columns, totals, warnings and per-statement errors
+func (c *Client) ExecuteSQLResults(ctx context.Context, query string) ([]SQLResult, error) {
+	if c.readOnly {
+		if err := CheckReadOnly(query); err != nil {
+			c.logger.Warn("Rejected mutating statement", "query", query)
+			return nil, err
+		}
+	}
+
+	return c.executeSQLResults(ctx, query)
+}
+
+// executeSQLResults sends statements to the SQL endpoint without the read-only check
+func (c *Client) executeSQLResults(ctx context.Context, query string) ([]SQLResult, error) {
+	bodyBytes, err := c.doRawRequest(ctx, "POST", sqlEndpoint, "", []byte(query))
 	if err != nil {
 		return nil, fmt.Errorf("SQL request failed: %w", err)
 	}
 
-	// Response is an array of result sets in raw mode
-	results, ok := respBody.([]interface{})
-	if !ok || len(results) == 0 {
-		return []map[string]interface{}{}, nil
+	rawResults, err := c.decodeSQLResults(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("SQL request failed: failed to decode response: %w", err)
 	}
 
-	// Get first result set
-	firstResult, ok := results[0].(map[string]interface{})
-	if !ok {
-		return []map[string]interface{}{}, nil
+	results := make([]SQLResult, 0, len(rawResults))
+	for _, raw := range rawResults {
+		result := SQLResult{
+			Columns: make([]SQLColumn, 0, len(raw.Columns)),
+			Rows:    raw.Data,
+			Total:   raw.Total,
+			Error:   raw.Error,
+			Warning: raw.Warning,
+		}
+
+		for _, column := range raw.Columns {
+			for name, info := range column {
+				result.Columns = append(result.Columns, SQLColumn{Name: name, Type: info.Type})
+			}
+		}
+
+		// Statements without result columns report affected rows in total
+		if len(raw.Columns) == 0 {
+			result.AffectedRows = raw.Total
+		}
+
+		if result.Rows == nil {
+			result.Rows = []map[string]interface{}{}
+		}
+
+		results = append(results, result)
 	}
 
-	data, ok := firstResult["data"].([]interface{})
-	if !ok {
-		return []map[string]interface{}{}, nil
+	return results, nil
+}
+
+// decodeSQLResults decodes raw mode response which is either an array of result sets or a single object
+func (c *Client) decodeSQLResults(body []byte) ([]rawSQLResult, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] == '{' {
+		var single rawSQLResult
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, err
+		}
+		return []rawSQLResult{single}, nil
+	}
+
+	var results []rawSQLResult
+	if err := json.Unmarshal(trimmed, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Search executes a JSON query against the /search endpoint
+func (c *Client) Search(ctx context.Context, query map[string]interface{}) (*SearchResponse, error) {
+	payload, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search query: %w", err)
+	}
+
+	bodyBytes, err := c.doRawRequest(ctx, "POST", searchEndpoint, "application/json", payload)
+	if err != nil {
+		return nil, fmt.Errorf("search request failed: %w", err)
+	}
+
+	var response SearchResponse
+	if err := json.Unmarshal(bodyBytes, &response); err != nil {
+		return nil, fmt.Errorf("search request failed: failed to decode response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// Bulk sends actions such as {"insert": {"table": ..., "doc": ...}} as NDJSON to the /bulk
+// endpoint. Bulk requests are not idempotent, so server errors are not retried; a response
+// describing failed lines is returned without an error.
+func (c *Client) Bulk(ctx context.Context, actions []map[string]interface{}) (*BulkResponse, error) {
+	if c.readOnly {
+		c.logger.Warn("Rejected bulk request", "actions", len(actions))
+		return nil, fmt.Errorf("%w: bulk", ErrReadOnly)
 	}
 
-	result := make([]map[string]interface{}, len(data))
-	for i, item := range data {
-		row, ok := item.(map[string]interface{})
+	payload, err := encodeNDJSON(actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bulk request: %w", err)
+	}
+
+	bodyBytes, err := c.doRequest(ctx, "POST", bulkEndpoint, "application/x-ndjson", payload, 0)
+	if err != nil {
+		// Failed lines are reported with an error status and the usual response body
+		var httpErr *types.HTTPError
+		if errors.As(err, &httpErr) {
+			var response BulkResponse
+			if json.Unmarshal([]byte(httpErr.Message), &response) == nil && (response.Errors || response.Error != "") {
+				return &response, nil
+			}
+		}
+		return nil, fmt.Errorf("bulk request failed: %w", err)
+	}
+
+	var response BulkResponse
+	if err := json.Unmarshal(bodyBytes, &response); err != nil {
+		return nil, fmt.Errorf("bulk request failed: failed to decode response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// encodeNDJSON encodes values as newline delimited JSON
+func encodeNDJSON(values []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for _, value := range values {
+		// Encode terminates every value with a newline
+		if err := encoder.Encode(value); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// KillQuery terminates running queries whose text contains marker and returns the number
+// of killed threads. The statements are generated here, so they bypass the read-only check.
+func (c *Client) KillQuery(ctx context.Context, marker string) (int, error) {
+	if marker == "" {
+		return 0, fmt.Errorf("kill query failed: marker is required")
+	}
+
+	results, err := c.executeSQLResults(ctx, "SHOW THREADS")
+	if err != nil {
+		return 0, fmt.Errorf("kill query failed: %w", err)
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	if results[0].Error != "" {
+		return 0, fmt.Errorf("kill query failed: %s", results[0].Error)
+	}
+
+	killed := 0
+	for _, row := range results[0].Rows {
+		info, _ := row["Info"].(string)
+		if !strings.Contains(info, marker) {
+			continue
+		}
+		tid, ok := row["TID"].(float64)
 		if !ok {
 			continue
 		}
-		result[i] = row
+
+		killResults, err := c.executeSQLResults(ctx, "KILL "+strconv.FormatInt(int64(tid), 10))
+		if err != nil {
+			return killed, fmt.Errorf("kill query failed: %w", err)
+		}
+		if len(killResults) > 0 && killResults[0].Error != "" {
+			return killed, fmt.Errorf("kill query failed: %s", killResults[0].Error)
+		}
+		killed++
 	}
 
-	return result, nil
+	return killed, nil
 }
 
 // Ping checks if Manticore server is reachable
@@ -91,11 +356,21 @@ func (c *Client) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) doRawRequest(ctx context.Context, method, endpoint, query string) (interface{}, error) {
+// DryRun reports false, requests are sent to Manticore
+func (c *Client) DryRun() bool {
+	return false
+}
+
+func (c *Client) doRawRequest(ctx context.Context, method, endpoint, contentType string, body []byte) ([]byte, error) {
+	return c.doRequest(ctx, method, endpoint, contentType, body, c.maxRetries)
+}
+
+// doRequest sends a request and retries network and server errors up to maxRetries times
+func (c *Client) doRequest(ctx context.Context, method, endpoint, contentType string, body []byte, maxRetries int) ([]byte, error) {
 	url := c.baseURL + endpoint
 
 	var lastErr error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			c.logger.Debug("Retrying request", "attempt", attempt, "url", url)
 			select {
@@ -105,13 +380,20 @@ func (c *Client) doRawRequest(ctx context.Context, method, endpoint, query strin
 			}
 		}
 
-		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader([]byte(query)))
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			// A canceled call must not be re-sent and a timed out query may still be running
+			if ctx.Err() != nil || isTimeout(err) {
+				return nil, err
+			}
 			lastErr = err
 			continue
 		}
@@ -119,7 +401,7 @@ func (c *Client) doRawRequest(ctx context.Context, method, endpoint, query strin
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 
-		if resp.StatusCode >= 500 && attempt < c.maxRetries {
+		if resp.StatusCode >= 500 && attempt < maxRetries {
 			lastErr = fmt.Errorf("server error: %d", resp.StatusCode)
 			continue
 		}
@@ -131,13 +413,14 @@ func (c *Client) doRawRequest(ctx context.Context, method, endpoint, query strin
 			}
 		}
 
-		var result interface{}
-		if err := json.Unmarshal(bodyBytes, &result); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
-		}
-
-		return result, nil
+		return bodyBytes, nil
 	}
 
-	return nil, fmt.Errorf("request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+	return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// isTimeout reports whether a request failed because the HTTP client timed out
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
@@ -18,12 +18,27 @@ var _ ManticoreClient = &ManticoreClientMock{}
 //
 //		// make and configure a mocked ManticoreClient
 //		mockedManticoreClient := &ManticoreClientMock{
+//			BulkFunc: func(ctx context.Context, actions []map[string]interface{}) (*BulkResponse, error) {
+//				panic("mock out the Bulk method")
+//			},
+//			DryRunFunc: func() bool {
+//				panic("mock out the DryRun method")
+//			},
 //			ExecuteSQLFunc: func(ctx context.Context, query string) ([]map[string]interface{}, error) {
 //				panic("mock out the ExecuteSQL method")
 //			},
+//			ExecuteSQLResultsFunc: func(ctx context.Context, query string) ([]SQLResult, error) {
+//				panic("mock out the ExecuteSQLResults method")
+//			},
+//			KillQueryFunc: func(ctx context.Context, marker string) (int, error) {
+//				panic("mock out the KillQuery method")
+//			},
 //			PingFunc: func(ctx context.Context) error {
 //				panic("mock out the Ping method")
 //			},
+//			SearchFunc: func(ctx context.Context, query map[string]interface{}) (*SearchResponse, error) {
+//				panic("mock out the Search method")
+//			},
 //		}
 //
 //		// use mockedManticoreClient in code that requires ManticoreClient
@@ -31,14 +46,39 @@ var _ ManticoreClient = &ManticoreClientMock{}
 //
 //	}
 type ManticoreClientMock struct {
+	// BulkFunc mocks the Bulk method.
+	BulkFunc func(ctx context.Context, actions []map[string]interface{}) (*BulkResponse, error)
+
+	// DryRunFunc mocks the DryRun method.
+	DryRunFunc func() bool
+
 	// ExecuteSQLFunc mocks the ExecuteSQL method.
 	ExecuteSQLFunc func(ctx context.Context, query string) ([]map[string]interface{}, error)
 
+	// ExecuteSQLResultsFunc mocks the ExecuteSQLResults method.
+	ExecuteSQLResultsFunc func(ctx context.Context, query string) ([]SQLResult, error)
+
+	// KillQueryFunc mocks the KillQuery method.
+	KillQueryFunc func(ctx context.Context, marker string) (int, error)
+
 	// PingFunc mocks the Ping method.
 	PingFunc func(ctx context.Context) error
 
+	// SearchFunc mocks the Search method.
+	SearchFunc func(ctx context.Context, query map[string]interface{}) (*SearchResponse, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// Bulk holds details about calls to the Bulk method.
+		Bulk []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Actions is the actions argument value.
+			Actions []map[string]interface{}
+		}
+		// DryRun holds details about calls to the DryRun method.
+		DryRun []struct {
+		}
 		// ExecuteSQL holds details about calls to the ExecuteSQL method.
 		ExecuteSQL []struct {
 			// Ctx is the ctx argument value.
@@ -46,14 +86,103 @@ type ManticoreClientMock struct {
 			// Query is the query argument value.
 			Query string
 		}
+		// ExecuteSQLResults holds details about calls to the ExecuteSQLResults method.
+		ExecuteSQLResults []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query string
+		}
+		// KillQuery holds details about calls to the KillQuery method.
+		KillQuery []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Marker is the marker argument value.
+			Marker string
+		}
 		// Ping holds details about calls to the Ping method.
 		Ping []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// Search holds details about calls to the Search method.
+		Search []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query map[string]interface{}
+		}
+	}
+	lockBulk              sync.RWMutex
+	lockDryRun            sync.RWMutex
+	lockExecuteSQL        sync.RWMutex
+	lockExecuteSQLResults sync.RWMutex
+	lockKillQuery         sync.RWMutex
+	lockPing              sync.RWMutex
+	lockSearch            sync.RWMutex
+}
+
+// Bulk calls BulkFunc.
+func (mock *ManticoreClientMock) Bulk(ctx context.Context, actions []map[string]interface{}) (*BulkResponse, error) {
+	if mock.BulkFunc == nil {
+		panic("ManticoreClientMock.BulkFunc: method is nil but ManticoreClient.Bulk was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Actions []map[string]interface{}
+	}{
+		Ctx:     ctx,
+		Actions: actions,
+	}
+	mock.lockBulk.Lock()
+	mock.calls.Bulk = append(mock.calls.Bulk, callInfo)
+	mock.lockBulk.Unlock()
+	return mock.BulkFunc(ctx, actions)
+}
+
+// BulkCalls gets all the calls that were made to Bulk.
+// Check the length with:
+//
+//	len(mockedManticoreClient.BulkCalls())
+func (mock *ManticoreClientMock) BulkCalls() []struct {
+	Ctx     context.Context
+	Actions []map[string]interface{}
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Actions []map[string]interface{}
 	}
-	lockExecuteSQL sync.RWMutex
-	lockPing       sync.RWMutex
+	mock.lockBulk.RLock()
+	calls = mock.calls.Bulk
+	mock.lockBulk.RUnlock()
+	return calls
+}
+
+// DryRun calls DryRunFunc.
+func (mock *ManticoreClientMock) DryRun() bool {
+	if mock.DryRunFunc == nil {
+		panic("ManticoreClientMock.DryRunFunc: method is nil but ManticoreClient.DryRun was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockDryRun.Lock()
+	mock.calls.DryRun = append(mock.calls.DryRun, callInfo)
+	mock.lockDryRun.Unlock()
+	return mock.DryRunFunc()
+}
+
+// DryRunCalls gets all the calls that were made to DryRun.
+// Check the length with:
+//
+//	len(mockedManticoreClient.DryRunCalls())
+func (mock *ManticoreClientMock) DryRunCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockDryRun.RLock()
+	calls = mock.calls.DryRun
+	mock.lockDryRun.RUnlock()
+	return calls
 }
 
 // ExecuteSQL calls ExecuteSQLFunc.
@@ -92,6 +221,78 @@ func (mock *ManticoreClientMock) ExecuteSQLCalls() []struct {
 	return calls
 }
 
+// ExecuteSQLResults calls ExecuteSQLResultsFunc.
+func (mock *ManticoreClientMock) ExecuteSQLResults(ctx context.Context, query string) ([]SQLResult, error) {
+	if mock.ExecuteSQLResultsFunc == nil {
+		panic("ManticoreClientMock.ExecuteSQLResultsFunc: method is nil but ManticoreClient.ExecuteSQLResults was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query string
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockExecuteSQLResults.Lock()
+	mock.calls.ExecuteSQLResults = append(mock.calls.ExecuteSQLResults, callInfo)
+	mock.lockExecuteSQLResults.Unlock()
+	return mock.ExecuteSQLResultsFunc(ctx, query)
+}
+
+// ExecuteSQLResultsCalls gets all the calls that were made to ExecuteSQLResults.
+// Check the length with:
+//
+//	len(mockedManticoreClient.ExecuteSQLResultsCalls())
+func (mock *ManticoreClientMock) ExecuteSQLResultsCalls() []struct {
+	Ctx   context.Context
+	Query string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query string
+	}
+	mock.lockExecuteSQLResults.RLock()
+	calls = mock.calls.ExecuteSQLResults
+	mock.lockExecuteSQLResults.RUnlock()
+	return calls
+}
+
+// KillQuery calls KillQueryFunc.
+func (mock *ManticoreClientMock) KillQuery(ctx context.Context, marker string) (int, error) {
+	if mock.KillQueryFunc == nil {
+		panic("ManticoreClientMock.KillQueryFunc: method is nil but ManticoreClient.KillQuery was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Marker string
+	}{
+		Ctx:    ctx,
+		Marker: marker,
+	}
+	mock.lockKillQuery.Lock()
+	mock.calls.KillQuery = append(mock.calls.KillQuery, callInfo)
+	mock.lockKillQuery.Unlock()
+	return mock.KillQueryFunc(ctx, marker)
+}
+
+// KillQueryCalls gets all the calls that were made to KillQuery.
+// Check the length with:
+//
+//	len(mockedManticoreClient.KillQueryCalls())
+func (mock *ManticoreClientMock) KillQueryCalls() []struct {
+	Ctx    context.Context
+	Marker string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Marker string
+	}
+	mock.lockKillQuery.RLock()
+	calls = mock.calls.KillQuery
+	mock.lockKillQuery.RUnlock()
+	return calls
+}
+
 // Ping calls PingFunc.
 func (mock *ManticoreClientMock) Ping(ctx context.Context) error {
 	if mock.PingFunc == nil {
@@ -123,3 +324,39 @@ func (mock *ManticoreClientMock) PingCalls() []struct {
 	mock.lockPing.RUnlock()
 	return calls
 }
+
+// Search calls SearchFunc.
+func (mock *ManticoreClientMock) Search(ctx context.Context, query map[string]interface{}) (*SearchResponse, error) {
+	if mock.SearchFunc == nil {
+		panic("ManticoreClientMock.SearchFunc: method is nil but ManticoreClient.Search was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query map[string]interface{}
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockSearch.Lock()
+	mock.calls.Search = append(mock.calls.Search, callInfo)
+	mock.lockSearch.Unlock()
+	return mock.SearchFunc(ctx, query)
+}
+
+// SearchCalls gets all the calls that were made to Search.
+// Check the length with:
+//
+//	len(mockedManticoreClient.SearchCalls())
+func (mock *ManticoreClientMock) SearchCalls() []struct {
+	Ctx   context.Context
+	Query map[string]interface{}
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query map[string]interface{}
+	}
+	mock.lockSearch.RLock()
+	calls = mock.calls.Search
+	mock.lockSearch.RUnlock()
+	return calls
+}
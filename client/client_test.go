@@ -2,12 +2,18 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"manticore-mcp-server/config"
@@ -148,6 +154,16 @@ func (s *ClientTestSuite) TestExecuteSQL_ContextCancellation() {
 	}
 }
 
+func (s *ClientTestSuite) TestExecuteSQLResults_MultiStatement() {
+	ctx := context.Background()
+
+	results, err := s.client.ExecuteSQLResults(ctx, "SHOW TABLES; SHOW META")
+	s.Require().NoError(err)
+	s.Require().Len(results, 2)
+	s.NotEmpty(results[0].Columns)
+	s.NotEmpty(results[1].Columns)
+}
+
 func TestClientIntegration(t *testing.T) {
 	suite.Run(t, new(ClientTestSuite))
 }
@@ -171,3 +187,318 @@ func TestClient_New(t *testing.T) {
 	// Test that client implements interface
 	_ = client
 }
+
+func TestClient_Search(t *testing.T) {
+	var receivedQuery map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &receivedQuery))
+
+		_, _ = w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":42,"total_relation":"gte","hits":[` +
+			`{"_id":7,"_score":1500,"_source":{"title":"Go guide"},"highlight":{"title":["<b>Go</b> guide"]}}]}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	query := map[string]interface{}{
+		"table": "articles",
+		"query": map[string]interface{}{"match": map[string]interface{}{"*": "go"}},
+	}
+	response, err := client.Search(context.Background(), query)
+	require.NoError(t, err)
+
+	assert.Equal(t, "articles", receivedQuery["table"])
+	assert.Equal(t, 42, response.Hits.Total)
+	assert.Equal(t, "gte", response.Hits.TotalRelation)
+	require.Len(t, response.Hits.Hits, 1)
+
+	hit := response.Hits.Hits[0]
+	assert.Equal(t, uint64(7), hit.ID)
+	assert.InDelta(t, 1500.0, hit.Score, 0.01)
+	assert.Equal(t, "Go guide", hit.Source["title"])
+	assert.Equal(t, []string{"<b>Go</b> guide"}, hit.Highlight["title"])
+}
+
+func TestClient_SearchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"unknown table"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	_, err := client.Search(context.Background(), map[string]interface{}{"table": "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search request failed")
+	assert.Contains(t, err.Error(), "unknown table")
+}
+
+func TestClient_ExecuteSQLResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sql", r.URL.Path)
+		assert.Equal(t, "raw", r.URL.Query().Get("mode"))
+
+		_, _ = w.Write([]byte(`[` +
+			`{"columns":[{"id":{"type":"long long"}},{"title":{"type":"string"}}],` +
+			`"data":[{"id":1,"title":"first"}],"total":1,"error":"","warning":""},` +
+			`{"columns":[{"Variable_name":{"type":"string"}},{"Value":{"type":"string"}}],` +
+			`"data":[{"Variable_name":"total_found","Value":"1500"}],"total":1,"error":"","warning":"slow"},` +
+			`{"total":3,"error":"","warning":""}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	results, err := client.ExecuteSQLResults(context.Background(), "SELECT id, title FROM t; SHOW META; DELETE FROM t WHERE id > 1")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, []SQLColumn{{Name: "id", Type: "long long"}, {Name: "title", Type: "string"}}, results[0].Columns)
+	assert.Equal(t, "first", results[0].Rows[0]["title"])
+	assert.Equal(t, 1, results[0].Total)
+	assert.Zero(t, results[0].AffectedRows)
+
+	assert.Equal(t, "1500", results[1].Rows[0]["Value"])
+	assert.Equal(t, "slow", results[1].Warning)
+
+	assert.Empty(t, results[2].Columns)
+	assert.NotNil(t, results[2].Rows)
+	assert.Equal(t, 3, results[2].AffectedRows)
+}
+
+func TestClient_ExecuteSQLStatementError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"total":0,"error":"unknown column: 'foo'","warning":""}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	_, err := client.ExecuteSQL(context.Background(), "SELECT foo FROM t")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown column")
+}
+
+func TestClient_ExecuteSQLReadOnly(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		_, _ = w.Write([]byte(`[{"columns":[{"id":{"type":"long long"}}],"data":[{"id":1}],"total":1,"error":"","warning":""}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+		ReadOnly:       true,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	rows, err := client.ExecuteSQL(context.Background(), "SELECT id FROM t")
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+
+	_, err = client.ExecuteSQL(context.Background(), "SELECT id FROM t WHERE id=1; DELETE FROM t WHERE id=1")
+	require.ErrorIs(t, err, ErrReadOnly)
+	assert.Contains(t, err.Error(), "DELETE FROM")
+
+	_, err = client.ExecuteSQLResults(context.Background(), "INSERT INTO t (id) VALUES (2)")
+	require.ErrorIs(t, err, ErrReadOnly)
+
+	assert.Equal(t, []string{"SELECT id FROM t"}, requests)
+}
+
+func TestClient_Bulk(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_, _ = w.Write([]byte(`{"items":[{"bulk":{"table":"products","_id":2,"created":2,"deleted":0,"updated":0,"result":"created","status":201}}],` +
+			`"current_line":3,"skipped_lines":0,"errors":false,"error":""}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	response, err := client.Bulk(context.Background(), []map[string]interface{}{
+		{"insert": map[string]interface{}{"table": "products", "id": 1, "doc": map[string]interface{}{"title": "<b>"}}},
+		{"insert": map[string]interface{}{"table": "products", "id": 2, "doc": map[string]interface{}{"title": "b"}}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"insert":{"doc":{"title":"<b>"},"id":1,"table":"products"}}`+"\n"+
+		`{"insert":{"doc":{"title":"b"},"id":2,"table":"products"}}`+"\n", body)
+	assert.False(t, response.Errors)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, uint64(2), response.Items[0]["bulk"].ID)
+	assert.Equal(t, 201, response.Items[0]["bulk"].Status)
+}
+
+func TestClient_BulkErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"items":[{"insert":{"table":"products","_id":1,"status":409,"error":"duplicate id '1'"}}],` +
+			`"current_line":1,"skipped_lines":1,"errors":true,"error":"duplicate id '1'"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+		MaxRetries:     3,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	response, err := client.Bulk(context.Background(), []map[string]interface{}{
+		{"insert": map[string]interface{}{"table": "products", "id": 1, "doc": map[string]interface{}{"title": "a"}}},
+	})
+	require.NoError(t, err)
+	assert.True(t, response.Errors)
+	assert.Equal(t, "duplicate id '1'", response.Error)
+	assert.Equal(t, 1, response.SkippedLines)
+	// Bulk requests are not idempotent and never retried
+	assert.Equal(t, 1, attempts)
+
+	readOnly := New(&config.Config{ManticoreURL: server.URL, ReadOnly: true}, logger)
+	_, err = readOnly.Bulk(context.Background(), nil)
+	require.ErrorIs(t, err, ErrReadOnly)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_KillQuery(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+
+		if string(body) == "SHOW THREADS" {
+			_, _ = w.Write([]byte(`[{"columns":[{"TID":{"type":"long"}},{"Info":{"type":"string"}}],` +
+				`"data":[{"TID":11,"Info":"SELECT * FROM t WHERE MATCH('x') OPTION comment='mcp-ABC'"},` +
+				`{"TID":12,"Info":"SELECT * FROM t"},{"TID":13,"Info":"SHOW THREADS"}],"total":3,"error":"","warning":""}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"total":0,"error":"","warning":""}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+		ReadOnly:       true,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	killed, err := client.KillQuery(context.Background(), "mcp-ABC")
+	require.NoError(t, err)
+	assert.Equal(t, 1, killed)
+	assert.Equal(t, []string{"SHOW THREADS", "KILL 11"}, requests, "read-only mode must not block killing own queries")
+}
+
+func TestClient_NoRetryAfterCancel(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		_, _ = io.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 5 * time.Second,
+		MaxRetries:     3,
+		RetryDelay:     time.Millisecond,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ExecuteSQL(ctx, "SELECT * FROM t")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), attempts.Load(), "canceled request must not be retried")
+}
+
+func TestClient_NoRetryAfterTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		_, _ = io.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ManticoreURL:   server.URL,
+		RequestTimeout: 50 * time.Millisecond,
+		MaxRetries:     3,
+		RetryDelay:     time.Millisecond,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	client := New(cfg, logger)
+
+	_, err := client.ExecuteSQL(context.Background(), "SELECT * FROM t")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load(), "timed out query may still run and must not be re-sent")
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Request describes a request to Manticore recorded by a dry run instead of being sent
+type Request struct {
+	Method     string                   `json:"method"`
+	Endpoint   string                   `json:"endpoint"`
+	URL        string                   `json:"url"`
+	SQL        string                   `json:"sql,omitempty"`
+	Body       map[string]interface{}   `json:"body,omitempty"`
+	Lines      []map[string]interface{} `json:"lines,omitempty"`
+	Class      string                   `json:"class"`
+	Statements []Statement              `json:"statements,omitempty"`
+}
+
+// Statement is a single SQL statement of a request with its classification
+type Statement struct {
+	SQL   string `json:"sql"`
+	Class string `json:"class"`
+}
+
+// DryRunClient records the requests a tool would send without contacting Manticore.
+// SQL requests return one empty result set per statement and searches return no hits.
+type DryRunClient struct {
+	baseURL  string
+	readOnly bool
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// Ensure, that DryRunClient does implement ManticoreClient.
+var _ ManticoreClient = &DryRunClient{}
+
+// NewDryRun creates a client that records requests to the Manticore server at baseURL.
+// In read-only mode mutating statements are rejected like by the real client.
+func NewDryRun(baseURL string, readOnly bool) *DryRunClient {
+	return &DryRunClient{
+		baseURL:  baseURL,
+		readOnly: readOnly,
+	}
+}
+
+// Requests returns the recorded requests in the order they were made
+func (c *DryRunClient) Requests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Request(nil), c.requests...)
+}
+
+// ExecuteSQL records a SQL request and returns no rows
+func (c *DryRunClient) ExecuteSQL(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	if _, err := c.ExecuteSQLResults(ctx, query); err != nil {
+		return nil, err
+	}
+	return []map[string]interface{}{}, nil
+}
+
+// ExecuteSQLResults records a SQL request and returns an empty result set per statement
+func (c *DryRunClient) ExecuteSQLResults(_ context.Context, query string) ([]SQLResult, error) {
+	if c.readOnly {
+		if err := CheckReadOnly(query); err != nil {
+			return nil, err
+		}
+	}
+
+	statements := SplitStatements(query)
+	request := Request{
+		Method:     "POST",
+		Endpoint:   sqlEndpoint,
+		URL:        c.baseURL + sqlEndpoint,
+		SQL:        query,
+		Class:      StatementRead.String(),
+		Statements: make([]Statement, 0, len(statements)),
+	}
+	results := make([]SQLResult, 0, len(statements))
+	for _, statement := range statements {
+		class := ClassifyStatement(statement)
+		if class == StatementWrite {
+			request.Class = class.String()
+		}
+		request.Statements = append(request.Statements, Statement{SQL: statement, Class: class.String()})
+		results = append(results, SQLResult{Rows: []map[string]interface{}{}})
+	}
+
+	c.record(request)
+	return results, nil
+}
+
+// Search records a JSON search request and returns no hits
+func (c *DryRunClient) Search(_ context.Context, query map[string]interface{}) (*SearchResponse, error) {
+	c.record(Request{
+		Method:   "POST",
+		Endpoint: searchEndpoint,
+		URL:      c.baseURL + searchEndpoint,
+		Body:     query,
+		Class:    StatementRead.String(),
+	})
+	return &SearchResponse{}, nil
+}
+
+// Bulk records an NDJSON bulk request and reports every action as applied
+func (c *DryRunClient) Bulk(_ context.Context, actions []map[string]interface{}) (*BulkResponse, error) {
+	if c.readOnly {
+		return nil, fmt.Errorf("%w: bulk", ErrReadOnly)
+	}
+
+	c.record(Request{
+		Method:   "POST",
+		Endpoint: bulkEndpoint,
+		URL:      c.baseURL + bulkEndpoint,
+		Lines:    actions,
+		Class:    StatementWrite.String(),
+	})
+	return &BulkResponse{CurrentLine: len(actions) + 1}, nil
+}
+
+// KillQuery does nothing, a dry run never leaves queries running
+func (c *DryRunClient) KillQuery(_ context.Context, _ string) (int, error) {
+	return 0, nil
+}
+
+// Ping always succeeds without contacting Manticore
+func (c *DryRunClient) Ping(_ context.Context) error {
+	return nil
+}
+
+// DryRun reports true, requests are only recorded
+func (c *DryRunClient) DryRun() bool {
+	return true
+}
+
+// record appends a request to the recorded requests
+func (c *DryRunClient) record(request Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, request)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunClient_ExecuteSQLResults(t *testing.T) {
+	c := NewDryRun("http://localhost:9308", false)
+
+	results, err := c.ExecuteSQLResults(context.Background(), "SELECT * FROM t WHERE MATCH('a;b'); SHOW META")
+	require.NoError(t, err)
+	assert.Len(t, results, 2, "one empty result set per statement")
+
+	_, err = c.ExecuteSQL(context.Background(), "INSERT INTO t (id) VALUES (1)")
+	require.NoError(t, err)
+
+	requests := c.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, Request{
+		Method:   "POST",
+		Endpoint: "/sql?mode=raw",
+		URL:      "http://localhost:9308/sql?mode=raw",
+		SQL:      "SELECT * FROM t WHERE MATCH('a;b'); SHOW META",
+		Class:    "read",
+		Statements: []Statement{
+			{SQL: "SELECT * FROM t WHERE MATCH('a;b')", Class: "read"},
+			{SQL: "SHOW META", Class: "read"},
+		},
+	}, requests[0])
+	assert.Equal(t, "write", requests[1].Class)
+	assert.True(t, c.DryRun())
+}
+
+func TestDryRunClient_Search(t *testing.T) {
+	c := NewDryRun("http://localhost:9308", false)
+
+	response, err := c.Search(context.Background(), map[string]interface{}{"table": "t"})
+	require.NoError(t, err)
+	assert.Empty(t, response.Hits.Hits)
+
+	requests := c.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/search", requests[0].Endpoint)
+	assert.Equal(t, map[string]interface{}{"table": "t"}, requests[0].Body)
+}
+
+func TestDryRunClient_ReadOnly(t *testing.T) {
+	c := NewDryRun("http://localhost:9308", true)
+
+	_, err := c.ExecuteSQL(context.Background(), "DELETE FROM t WHERE id=1")
+	require.ErrorIs(t, err, ErrReadOnly)
+	assert.Empty(t, c.Requests())
+}
+
+func TestDryRunClient_Bulk(t *testing.T) {
+	c := NewDryRun("http://localhost:9308", false)
+
+	actions := []map[string]interface{}{{"delete": map[string]interface{}{"table": "t", "id": 1}}}
+	response, err := c.Bulk(context.Background(), actions)
+	require.NoError(t, err)
+	assert.False(t, response.Errors)
+
+	requests := c.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/bulk", requests[0].Endpoint)
+	assert.Equal(t, "write", requests[0].Class)
+	assert.Equal(t, actions, requests[0].Lines)
+
+	_, err = NewDryRun("http://localhost:9308", true).Bulk(context.Background(), actions)
+	require.ErrorIs(t, err, ErrReadOnly)
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrReadOnly is returned when a mutating statement is executed in read-only mode
+	ErrReadOnly = errors.New("statement is not allowed in read-only mode")
+	// ErrAmbiguousComments is returned when comments hide or reveal statement separators, so the
+	// server might split the query differently than the classifier
+	ErrAmbiguousComments = errors.New("comments change how the query splits into statements")
+)
+
+// StatementClass describes whether a SQL statement can modify data
+type StatementClass int
+
+const (
+	// StatementRead marks statements that only read data or session state
+	StatementRead StatementClass = iota
+	// StatementWrite marks statements that may modify tables, documents, clusters or server state
+	StatementWrite
+)
+
+// String returns the class name
+func (c StatementClass) String() string {
+	if c == StatementRead {
+		return "read"
+	}
+	return "write"
+}
+
+// readStatements lists leading keywords of statements that never mutate data.
+// Anything not listed here is treated as a write so that unknown statements fail closed.
+var readStatements = map[string]bool{
+	"SELECT":   true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"EXPLAIN":  true,
+	"CALL":     true,
+	"SET":      true,
+}
+
+// writeSetTargets lists second keywords that make a SET statement change state beyond the session
+var writeSetTargets = map[string]bool{
+	"CLUSTER": true,
+	"GLOBAL":  true,
+	"INDEX":   true,
+	"TABLE":   true,
+}
+
+// ClassifyStatement classifies a single SQL statement by its leading keywords
+func ClassifyStatement(statement string) StatementClass {
+	words := leadingKeywords(statement, 2)
+	if len(words) == 0 {
+		return StatementRead
+	}
+
+	if !readStatements[words[0]] {
+		return StatementWrite
+	}
+
+	// SET CLUSTER, SET GLOBAL and SET INDEX/TABLE change cluster, server or table wide state
+	if words[0] == "SET" && len(words) > 1 && writeSetTargets[words[1]] {
+		return StatementWrite
+	}
+
+	return StatementRead
+}
+
+// StatementType returns the upper-cased leading keyword of a statement, DESC is reported as DESCRIBE
+func StatementType(statement string) string {
+	words := leadingKeywords(statement, 1)
+	if len(words) == 0 {
+		return ""
+	}
+	if words[0] == "DESC" {
+		return "DESCRIBE"
+	}
+	return words[0]
+}
+
+// CheckReadOnly returns ErrReadOnly if any statement of a (possibly multi-statement) query is
+// mutating, and ErrAmbiguousComments if its comments make the statements uncertain
+func CheckReadOnly(query string) error {
+	if err := CheckComments(query); err != nil {
+		return err
+	}
+	for _, statement := range SplitStatements(query) {
+		if ClassifyStatement(statement) == StatementWrite {
+			return fmt.Errorf("%w: %s", ErrReadOnly, strings.Join(leadingKeywords(statement, 2), " "))
+		}
+	}
+	return nil
+}
+
+// CheckComments returns ErrAmbiguousComments if a query splits into a different number of
+// statements when its comments are read as code. Checks relying on SplitStatements fail closed
+// this way should the server not treat a comment like the splitter does.
+func CheckComments(query string) error {
+	withComments, withoutComments := len(splitStatements(query, true)), len(splitStatements(query, false))
+	if withComments != withoutComments {
+		return fmt.Errorf("%w: %d statements, %d when comments are read as code", ErrAmbiguousComments, withComments, withoutComments)
+	}
+	return nil
+}
+
+// SplitStatements splits a query on semicolons that are outside of quotes and comments
+func SplitStatements(query string) []string {
+	return splitStatements(query, true)
+}
+
+// splitStatements splits a query on semicolons outside of quotes and, when comments is set,
+// outside of comments
+func splitStatements(query string, comments bool) []string {
+	var statements []string
+
+	appendStatement := func(statement string) {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(query); i++ {
+		switch ch := query[i]; {
+		case ch == '\'' || ch == '"' || ch == '`':
+			i = skipQuoted(query, i)
+		case comments && ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(query)
+			}
+		case comments && isLineComment(query[i:]):
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(query)
+			}
+		case ch == ';':
+			appendStatement(query[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(query) {
+		appendStatement(query[start:])
+	}
+
+	return statements
+}
+
+// isLineComment reports whether text starts with a # comment or a -- comment, which needs
+// whitespace or the end of the query after the dashes so that e.g. 5--1 stays an expression
+func isLineComment(text string) bool {
+	if strings.HasPrefix(text, "#") {
+		return true
+	}
+	if !strings.HasPrefix(text, "--") {
+		return false
+	}
+	return len(text) == 2 || unicode.IsSpace(rune(text[2]))
+}
+
+// skipQuoted returns the index of the quote closing the literal that starts at i
+func skipQuoted(query string, i int) int {
+	quote := query[i]
+	for i++; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(query)
+}
+
+// leadingKeywords returns up to n upper-cased leading words of a statement, skipping comments
+func leadingKeywords(statement string, n int) []string {
+	statement = stripLeadingComments(statement)
+
+	words := strings.FieldsFunc(statement, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '_'
+	})
+	if len(words) > n {
+		words = words[:n]
+	}
+	for i := range words {
+		words[i] = strings.ToUpper(words[i])
+	}
+	return words
+}
+
+// stripLeadingComments removes whitespace and comments preceding the first keyword
+func stripLeadingComments(statement string) string {
+	for {
+		statement = strings.TrimSpace(statement)
+		switch {
+		case strings.HasPrefix(statement, "/*"):
+			end := strings.Index(statement, "*/")
+			if end < 0 {
+				return ""
+			}
+			statement = statement[end+2:]
+		case isLineComment(statement):
+			end := strings.IndexByte(statement, '\n')
+			if end < 0 {
+				return ""
+			}
+			statement = statement[end+1:]
+		default:
+			return statement
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  StatementClass
+	}{
+		{name: "select", statement: "SELECT * FROM products WHERE MATCH('phone')", expected: StatementRead},
+		{name: "lowercase select", statement: "  select id from t", expected: StatementRead},
+		{name: "show tables", statement: "SHOW TABLES", expected: StatementRead},
+		{name: "show meta", statement: "SHOW META", expected: StatementRead},
+		{name: "describe", statement: "DESCRIBE products", expected: StatementRead},
+		{name: "desc", statement: "DESC products", expected: StatementRead},
+		{name: "explain", statement: "EXPLAIN QUERY products 'phone'", expected: StatementRead},
+		{name: "call snippets", statement: "CALL SNIPPETS('text', 'products', 'phone')", expected: StatementRead},
+		{name: "session set", statement: "SET profiling=1", expected: StatementRead},
+		{name: "leading comment", statement: "/* hint */ SELECT 1", expected: StatementRead},
+		{name: "line comment", statement: "-- note\nSHOW STATUS", expected: StatementRead},
+		{name: "hash comment", statement: "# note\nSHOW STATUS", expected: StatementRead},
+		{name: "dashes without space", statement: "--1\nDROP TABLE t", expected: StatementWrite},
+		{name: "empty", statement: "   ", expected: StatementRead},
+		{name: "insert", statement: "INSERT INTO t (id) VALUES (1)", expected: StatementWrite},
+		{name: "replace", statement: "REPLACE INTO t (id) VALUES (1)", expected: StatementWrite},
+		{name: "update", statement: "UPDATE t SET a=1 WHERE id=1", expected: StatementWrite},
+		{name: "delete", statement: "delete from t where id=1", expected: StatementWrite},
+		{name: "truncate", statement: "TRUNCATE TABLE t", expected: StatementWrite},
+		{name: "drop", statement: "DROP TABLE t", expected: StatementWrite},
+		{name: "alter", statement: "ALTER TABLE t ADD COLUMN a int", expected: StatementWrite},
+		{name: "create", statement: "CREATE TABLE t (title text)", expected: StatementWrite},
+		{name: "attach", statement: "ATTACH TABLE a TO TABLE b", expected: StatementWrite},
+		{name: "flush", statement: "FLUSH RAMCHUNK t", expected: StatementWrite},
+		{name: "optimize", statement: "OPTIMIZE TABLE t", expected: StatementWrite},
+		{name: "create cluster", statement: "CREATE CLUSTER posts", expected: StatementWrite},
+		{name: "join cluster", statement: "JOIN CLUSTER posts AT '10.0.0.1:9312'", expected: StatementWrite},
+		{name: "delete cluster", statement: "DELETE CLUSTER posts", expected: StatementWrite},
+		{name: "set cluster", statement: "SET CLUSTER posts GLOBAL 'pc.bootstrap' = 1", expected: StatementWrite},
+		{name: "set global", statement: "SET GLOBAL query_log_min_msec=10", expected: StatementWrite},
+		{name: "set index global", statement: "SET INDEX products GLOBAL @uservar = (1, 2, 3)", expected: StatementWrite},
+		{name: "set table global", statement: "set table products global @uservar = (1)", expected: StatementWrite},
+		{name: "commented write", statement: "/* SELECT */ DROP TABLE t", expected: StatementWrite},
+		{name: "unknown statement", statement: "IMPORT TABLE t FROM '/tmp/t'", expected: StatementWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyStatement(tt.statement))
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "single", query: "SELECT 1", expected: []string{"SELECT 1"}},
+		{name: "trailing semicolon", query: "SELECT 1;", expected: []string{"SELECT 1"}},
+		{name: "multiple", query: "SELECT 1; SHOW META", expected: []string{"SELECT 1", "SHOW META"}},
+		{name: "quoted semicolon", query: "SELECT * FROM t WHERE MATCH('a;b'); SHOW META", expected: []string{"SELECT * FROM t WHERE MATCH('a;b')", "SHOW META"}},
+		{name: "escaped quote", query: `SELECT 'it\'s;'; DELETE FROM t`, expected: []string{`SELECT 'it\'s;'`, "DELETE FROM t"}},
+		{name: "comment semicolon", query: "SELECT 1 /* ; */; DROP TABLE t", expected: []string{"SELECT 1 /* ; */", "DROP TABLE t"}},
+		{name: "double minus", query: "SELECT 5--1; DROP TABLE t", expected: []string{"SELECT 5--1", "DROP TABLE t"}},
+		{name: "line comment", query: "SELECT 5 -- note; DROP TABLE t\nSHOW META", expected: []string{"SELECT 5 -- note; DROP TABLE t\nSHOW META"}},
+		{name: "hash mid line", query: "SELECT 5 # note; DROP TABLE t", expected: []string{"SELECT 5 # note; DROP TABLE t"}},
+		{name: "empty", query: " ; ", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SplitStatements(tt.query))
+		})
+	}
+}
+
+func TestCheckReadOnly(t *testing.T) {
+	require.NoError(t, CheckReadOnly("SELECT * FROM t; SHOW META"))
+	require.NoError(t, CheckReadOnly("SELECT * FROM t WHERE MATCH('; DROP TABLE t')"))
+
+	err := CheckReadOnly("SELECT * FROM t WHERE id > 0; DROP TABLE t")
+	require.ErrorIs(t, err, ErrReadOnly)
+	assert.Contains(t, err.Error(), "DROP TABLE")
+
+	// 5--1 is an expression, not a comment hiding the separator
+	require.ErrorIs(t, CheckReadOnly("SELECT 5--1; DROP TABLE t"), ErrReadOnly)
+
+	// Comments hiding a separator fail closed, whatever the server makes of them
+	require.NoError(t, CheckReadOnly("SELECT 1 -- note\n"))
+	require.ErrorIs(t, CheckReadOnly("SELECT 5 # note; DROP TABLE t"), ErrAmbiguousComments)
+	require.ErrorIs(t, CheckReadOnly("SELECT 5 -- note; DROP TABLE t"), ErrAmbiguousComments)
+	require.ErrorIs(t, CheckReadOnly("SELECT 5 /* ; */"), ErrAmbiguousComments)
+}
+
+func TestStatementType(t *testing.T) {
+	assert.Equal(t, "SELECT", StatementType("/* hint */ select * from t"))
+	assert.Equal(t, "DESCRIBE", StatementType("DESC products"))
+	assert.Equal(t, "DESCRIBE", StatementType("describe products"))
+	assert.Equal(t, "SHOW", StatementType("-- note\nSHOW TABLES"))
+	assert.Equal(t, "", StatementType("   "))
+}
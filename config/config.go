@@ -14,13 +14,39 @@ import (
 
 // Config holds application configuration
 type Config struct {
-	ManticoreURL       string        `long:"manticore-url" env:"MANTICORE_URL" default:"http://localhost:9308" description:"Manticore Search server URL"`
-	RequestTimeout     time.Duration `long:"request-timeout" env:"REQUEST_TIMEOUT" default:"30s" description:"HTTP request timeout"`
-	MaxRetries         int           `long:"max-retries" env:"MAX_RETRIES" default:"3" description:"Maximum number of retry attempts"`
-	RetryDelay         time.Duration `long:"retry-delay" env:"RETRY_DELAY" default:"1s" description:"Delay between retry attempts"`
-	MaxResultsPerQuery int           `long:"max-results" env:"MAX_RESULTS_PER_QUERY" default:"100" description:"Maximum results per query for MCP responses"`
-	EnvFile            string        `long:"env-file" description:"Path to .env file for local development"`
-	Debug              bool          `long:"debug" env:"DEBUG" description:"Enable debug logging"`
+	ManticoreURL            string        `long:"manticore-url" env:"MANTICORE_URL" default:"http://localhost:9308" description:"Manticore Search server URL"`
+	RequestTimeout          time.Duration `long:"request-timeout" env:"REQUEST_TIMEOUT" default:"30s" description:"HTTP request timeout"`
+	MaxRetries              int           `long:"max-retries" env:"MAX_RETRIES" default:"3" description:"Maximum number of retry attempts"`
+	RetryDelay              time.Duration `long:"retry-delay" env:"RETRY_DELAY" default:"1s" description:"Delay between retry attempts"`
+	MaxResultsPerQuery      int           `long:"max-results" env:"MAX_RESULTS_PER_QUERY" default:"100" description:"Maximum results per query for MCP responses"`
+	EnableWrites            bool          `long:"enable-writes" env:"ENABLE_WRITES" description:"Expose tools that update/delete documents and administer clusters"`
+	ReadOnly                bool          `long:"read-only" env:"READ_ONLY" description:"Reject mutating SQL statements and hide tools that modify data"`
+	AllowRawWhere           bool          `long:"allow-raw-where" env:"ALLOW_RAW_WHERE" description:"Accept raw SQL WHERE fragments in search in addition to structured filters"`
+	SQLAllowedStatements    []string      `long:"sql-allow" env:"SQL_ALLOWED_STATEMENTS" env-delim:"," default:"SELECT" default:"SHOW" default:"DESCRIBE" default:"CALL" description:"Statement type accepted by execute_sql (repeatable), write types need --enable-writes"`
+	SQLAllowMultiStatements bool          `long:"sql-allow-multi" env:"SQL_ALLOW_MULTI_STATEMENTS" description:"Accept several statements in one execute_sql call"`
+	DeleteConfirmThreshold  int           `long:"delete-confirm-threshold" env:"DELETE_CONFIRM_THRESHOLD" default:"100" description:"Number of matching documents above which delete_by_query requires confirm_count"`
+	BulkBatchSize           int           `long:"bulk-batch-size" env:"BULK_BATCH_SIZE" default:"1000" description:"Default number of actions per /bulk request of bulk_documents"`
+	BulkFileDir             string        `long:"bulk-file-dir" env:"BULK_FILE_DIR" description:"Directory bulk_documents may read JSONL and CSV files from (empty = file input disabled)"`
+	MigrationsDir           string        `long:"migrations-dir" env:"MIGRATIONS_DIR" description:"Directory of YAML/JSON table specs for the migrate command and migrate_schema tool"`
+	MigrationsTable         string        `long:"migrations-table" env:"MIGRATIONS_TABLE" default:"mcp_migrations" description:"Table keeping the history of applied migrations"`
+	DryRun                  bool          `long:"dry-run" env:"DRY_RUN" description:"Return the requests every tool would send instead of contacting Manticore"`
+	Transport               string        `long:"transport" env:"MCP_TRANSPORT" default:"stdio" choice:"stdio" choice:"http" choice:"sse" description:"MCP transport: stdio, streamable http or legacy sse"`
+	ListenAddr              string        `long:"listen-addr" env:"MCP_LISTEN_ADDR" default:"127.0.0.1:8080" description:"Listen address for the http and sse transports"`
+	AuthToken               string        `long:"auth-token" env:"MCP_AUTH_TOKEN" description:"Bearer token required from clients of the http and sse transports"`
+	AllowedOrigins          []string      `long:"allowed-origin" env:"MCP_ALLOWED_ORIGINS" env-delim:"," description:"Browser origin allowed to call the http and sse transports besides localhost (repeatable)"`
+	SessionIdleTimeout      time.Duration `long:"session-idle-timeout" env:"MCP_SESSION_IDLE_TIMEOUT" default:"30m" description:"Close HTTP sessions that were idle for longer than this"`
+	ShutdownTimeout         time.Duration `long:"shutdown-timeout" env:"SHUTDOWN_TIMEOUT" default:"10s" description:"Time to wait for in-flight requests on shutdown"`
+	EnvFile                 string        `long:"env-file" description:"Path to .env file for local development"`
+	Debug                   bool          `long:"debug" env:"DEBUG" description:"Enable debug logging"`
+
+	Migrate MigrateCommand `command:"migrate" description:"Print the plan reaching the table specs of --migrations-dir, apply it with --apply, and exit"`
+	// Command is the name of the subcommand given on the command line, empty when serving MCP
+	Command string `no-flag:"true"`
+}
+
+// MigrateCommand holds the options of the migrate subcommand
+type MigrateCommand struct {
+	Apply bool `long:"apply" description:"Apply the plan instead of only printing it"`
 }
 
 // Load reads configuration from CLI flags and environment variables
@@ -28,6 +54,7 @@ func Load() (*Config, error) {
 	var cfg Config
 
 	parser := flags.NewParser(&cfg, flags.Default)
+	parser.SubcommandsOptional = true
 	if _, err := parser.Parse(); err != nil {
 		var flagsErr *flags.Error
 		if errors.As(err, &flagsErr) && flagsErr.Type == flags.ErrHelp {
@@ -48,5 +75,9 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config after loading env: %w", err)
 	}
 
+	if parser.Active != nil {
+		cfg.Command = parser.Active.Name
+	}
+
 	return &cfg, nil
 }
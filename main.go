@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"manticore-mcp-server/client"
 	"manticore-mcp-server/config"
 	"manticore-mcp-server/server"
 	"manticore-mcp-server/tools"
+	"manticore-mcp-server/tools/migrations"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -28,6 +33,14 @@ func main() {
 	manticoreClient := client.New(cfg, logger)
 	toolHandler := tools.NewHandler(manticoreClient, logger)
 
+	if cfg.Command == "migrate" {
+		if err := runMigrate(cfg, toolHandler); err != nil {
+			logger.Error("Migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	mcpServer := server.New(toolHandler, cfg, logger)
 
 	if err := mcpServer.Run(); err != nil {
@@ -35,3 +48,20 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runMigrate prints the plan reaching the table specs of the migrations directory and applies
+// it when --apply is given
+func runMigrate(cfg *config.Config, toolHandler *tools.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	plan, err := toolHandler.Migrations.Migrate(ctx, migrations.MigrateArgs{
+		Apply:        cfg.Migrate.Apply,
+		Dir:          cfg.MigrationsDir,
+		HistoryTable: cfg.MigrationsTable,
+	})
+	if plan != nil {
+		fmt.Print(migrations.FormatPlan(plan))
+	}
+	return err
+}
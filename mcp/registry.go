@@ -5,11 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"manticore-mcp-server/client"
 	"manticore-mcp-server/config"
 	"manticore-mcp-server/tools"
+	"manticore-mcp-server/tools/aggregate"
 	"manticore-mcp-server/tools/clusters"
 	"manticore-mcp-server/tools/documents"
+	"manticore-mcp-server/tools/migrations"
+	"manticore-mcp-server/tools/rawsql"
 	"manticore-mcp-server/tools/search"
 	"manticore-mcp-server/tools/tables"
 
@@ -26,15 +34,27 @@ type Response struct {
 
 // Meta contains metadata about the response
 type Meta struct {
-	Total     int    `json:"total"`
-	Count     int    `json:"count"`
-	Limit     int    `json:"limit,omitempty"`
-	Offset    int    `json:"offset,omitempty"`
-	Table     string `json:"table,omitempty"`
-	Cluster   string `json:"cluster,omitempty"`
-	Operation string `json:"operation,omitempty"`
+	Total         int                  `json:"total"`
+	TotalRelation string               `json:"total_relation,omitempty"`
+	Count         int                  `json:"count"`
+	Limit         int                  `json:"limit,omitempty"`
+	Offset        int                  `json:"offset,omitempty"`
+	HasMore       bool                 `json:"has_more,omitempty"`
+	QueryTimeMs   float64              `json:"query_time_ms,omitempty"`
+	Keywords      []search.KeywordStat `json:"keywords,omitempty"`
+	Facets        []search.Facet       `json:"facets,omitempty"`
+	Table         string               `json:"table,omitempty"`
+	Cluster       string               `json:"cluster,omitempty"`
+	Operation     string               `json:"operation,omitempty"`
+	DryRun        bool                 `json:"dry_run,omitempty"`
 }
 
+// rawConditionDisabled is reported when a raw SQL condition is passed without --allow-raw-where
+const rawConditionDisabled = "Raw conditions are disabled, use filter instead (or start the server with --allow-raw-where)"
+
+// replaceDisabled is reported when an insert asks to replace documents without --enable-writes
+const replaceDisabled = "Replace is disabled, start the server with --enable-writes to overwrite documents"
+
 // Registry handles MCP tool registration
 type Registry struct {
 	tools  *tools.Handler
@@ -53,7 +73,7 @@ func NewRegistry(toolsHandler *tools.Handler, cfg *config.Config, logger *slog.L
 
 // RegisterAll registers all Manticore tools with MCP server
 func (r *Registry) RegisterAll(server *mcp_golang.Server) error {
-	r.logger.Info("Registering all Manticore tools with MCP...")
+	r.logger.Info("Registering all Manticore tools with MCP...", "read_only", r.config.ReadOnly)
 
 	// Register search tools
 	if err := r.registerSearchTools(server); err != nil {
@@ -75,16 +95,96 @@ func (r *Registry) RegisterAll(server *mcp_golang.Server) error {
 		return fmt.Errorf("failed to register cluster tools: %w", err)
 	}
 
+	// Register raw SQL tool
+	if err := r.registerSQLTools(server); err != nil {
+		return fmt.Errorf("failed to register SQL tools: %w", err)
+	}
+
 	r.logger.Info("All Manticore tools registered successfully")
 	return nil
 }
 
+// callContext derives the context of a tool call from the MCP request context, which is
+// canceled when the client cancels the call, and applies the optional timeout_ms argument
+func (r *Registry) callContext(ctx context.Context, args map[string]interface{}) (context.Context, context.CancelFunc) {
+	if timeout := r.getIntArg(args, "timeout_ms"); timeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	}
+	return context.WithCancel(ctx)
+}
+
+// dryRunKey carries the tool handler bound to a dry-run client in the context of a call
+type dryRunKey struct{}
+
+// withDryRun runs a tool handler. With the dry_run argument or the global --dry-run switch the
+// handler talks to a client that records requests instead of sending them, and the recorded
+// requests are returned in place of the tool result. Calls that fail, e.g. for invalid
+// arguments, return the handler response unchanged.
+func (r *Registry) withDryRun(ctx context.Context, operation string, args map[string]interface{},
+	handler func(context.Context, map[string]interface{}) (*mcp_golang.ToolResponse, error),
+) (*mcp_golang.ToolResponse, error) {
+	if !r.config.DryRun && !r.getBoolArg(args, "dry_run") {
+		return handler(ctx, args)
+	}
+
+	dryRun := client.NewDryRun(r.config.ManticoreURL, r.config.ReadOnly)
+	response, err := handler(context.WithValue(ctx, dryRunKey{}, tools.NewHandler(dryRun, r.logger)), args)
+
+	requests := dryRun.Requests()
+	if err != nil || len(requests) == 0 || r.responseFailed(response) {
+		return response, err
+	}
+
+	return r.successResponse(&Response{
+		Success: true,
+		Data:    requests,
+		Meta: &Meta{
+			Count:     len(requests),
+			Table:     r.getStringArg(args, "table"),
+			Cluster:   r.getStringArg(args, "cluster"),
+			Operation: operation,
+			DryRun:    true,
+		},
+	})
+}
+
+// toolsFor returns the tool handler of a call, bound to a dry-run client for dry runs
+func (r *Registry) toolsFor(ctx context.Context) *tools.Handler {
+	if handler, ok := ctx.Value(dryRunKey{}).(*tools.Handler); ok {
+		return handler
+	}
+	return r.tools
+}
+
+// writesEnabled reports whether tools that update/delete data or administer clusters are exposed
+func (r *Registry) writesEnabled() bool {
+	return r.config.EnableWrites && !r.config.ReadOnly
+}
+
 // registerSearchTools registers search-related tools
 func (r *Registry) registerSearchTools(server *mcp_golang.Server) error {
 	// Search tool
 	err := server.RegisterTool("search", "Perform full-text search in Manticore index with advanced options",
-		func(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-			return r.handleSearchTool(args)
+		func(ctx context.Context, args searchToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "search", args, r.handleSearchTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Explain search tool
+	err = server.RegisterTool("explain_search", "Profile a search: run it with profiling and return SHOW PROFILE, SHOW PLAN, EXPLAIN QUERY and SHOW META as structured data",
+		func(ctx context.Context, args explainSearchToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "explain_search", args, r.handleExplainSearchTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Aggregate tool
+	err = server.RegisterTool("aggregate", "Group matched documents by dimensions and compute count, count distinct, sum, avg, min and max per bucket",
+		func(ctx context.Context, args aggregateToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "aggregate", args, r.handleAggregateTool)
 		})
 	if err != nil {
 		return err
@@ -98,209 +198,948 @@ func (r *Registry) registerSearchTools(server *mcp_golang.Server) error {
 func (r *Registry) registerTableTools(server *mcp_golang.Server) error {
 	// Show tables tool
 	err := server.RegisterTool("show_tables", "List all tables/indexes in Manticore",
-		func(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-			return r.handleShowTablesTool(args)
+		func(ctx context.Context, args showTablesToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "show_tables", args, r.handleShowTablesTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Describe table tool
+	err = server.RegisterTool("describe_table", "Get detailed information about table schema",
+		func(ctx context.Context, args describeTableToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "describe_table", args, r.handleDescribeTableTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	if !r.writesEnabled() {
+		r.logger.Debug("Table management tools registered (schema changes disabled)")
+		return nil
+	}
+
+	// Create table tool
+	err = server.RegisterTool("create_table", "Create a table from typed columns and settings, optionally adding it to a replication cluster",
+		func(ctx context.Context, args createTableToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "create_table", args, r.handleCreateTableTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Alter table tool
+	err = server.RegisterTool("alter_table", "Add or drop columns, change settings or rebuild secondary indexes of a table, or diff a desired schema into the minimal ALTER statements",
+		func(ctx context.Context, args alterTableToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "alter_table", args, r.handleAlterTableTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Migrate schema tool
+	err = server.RegisterTool("migrate_schema", "Plan or apply the CREATE/ALTER statements that bring tables in line with the YAML/JSON specs of the migrations directory",
+		func(ctx context.Context, args migrateSchemaToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "migrate_schema", args, r.handleMigrateSchemaTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug("Table management tools registered")
+	return nil
+}
+
+// registerDocumentTools registers document operation tools
+func (r *Registry) registerDocumentTools(server *mcp_golang.Server) error {
+	if r.config.ReadOnly {
+		r.logger.Debug("Document operation tools disabled in read-only mode")
+		return nil
+	}
+
+	// Insert document tool
+	err := server.RegisterTool("insert_document", "Insert a new document into Manticore index",
+		func(ctx context.Context, args insertDocumentToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "insert_document", args, r.handleInsertDocumentTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Insert documents tool
+	err = server.RegisterTool("insert_documents", "Insert many documents with multi-row INSERT statements and return the assigned IDs",
+		func(ctx context.Context, args insertDocumentsToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "insert_documents", args, r.handleInsertDocumentsTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	if !r.writesEnabled() {
+		r.logger.Debug("Document operation tools registered (update/delete disabled)")
+		return nil
+	}
+
+	// Update document tool
+	err = server.RegisterTool("update_document", "Update attributes of a document in Manticore index by ID",
+		func(ctx context.Context, args updateDocumentToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "update_document", args, r.handleUpdateDocumentTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Update by query tool
+	err = server.RegisterTool("update_by_query", "Update attributes, JSON paths and multi values of all documents matching a full-text query and/or filter, or preview how many would change",
+		func(ctx context.Context, args updateByQueryToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "update_by_query", args, r.handleUpdateByQueryTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Delete document tool
+	err = server.RegisterTool("delete_document", "Delete a document from Manticore index by ID, optionally narrowed by a filter; use delete_by_query to delete by filter",
+		func(ctx context.Context, args deleteDocumentToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "delete_document", args, r.handleDeleteDocumentTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Delete by query tool
+	err = server.RegisterTool("delete_by_query", "Delete documents matching a full-text query, filter or ID list after counting them; large deletes require confirm_count",
+		func(ctx context.Context, args deleteByQueryToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "delete_by_query", args, r.handleDeleteByQueryTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Bulk documents tool
+	err = server.RegisterTool("bulk_documents", "Insert, replace, update or delete many documents through the /bulk endpoint, from items or a JSONL/CSV file",
+		func(ctx context.Context, args bulkDocumentsToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "bulk_documents", args, r.handleBulkDocumentsTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug("Document operation tools registered")
+	return nil
+}
+
+// registerClusterTools registers cluster management tools
+func (r *Registry) registerClusterTools(server *mcp_golang.Server) error {
+	// Show cluster status tool
+	err := server.RegisterTool("show_cluster_status", "Show status of cluster nodes",
+		func(ctx context.Context, args showClusterStatusToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "show_cluster_status", args, r.handleClusterStatusTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	if !r.writesEnabled() {
+		r.logger.Debug("Cluster tools registered (administration disabled)")
+		return nil
+	}
+
+	if err := r.registerClusterAdminTools(server); err != nil {
+		return err
+	}
+
+	r.logger.Debug("Cluster tools registered")
+	return nil
+}
+
+// registerClusterAdminTools registers tools that modify replication clusters
+func (r *Registry) registerClusterAdminTools(server *mcp_golang.Server) error {
+	// Create cluster tool
+	err := server.RegisterTool("create_cluster", "Create a new replication cluster",
+		func(ctx context.Context, args createClusterToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "create_cluster", args, r.handleCreateClusterTool)
 		})
 	if err != nil {
 		return err
 	}
 
-	// Describe table tool
-	err = server.RegisterTool("describe_table", "Get detailed information about table schema",
-		func(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-			return r.handleDescribeTableTool(args)
-		})
+	// Join cluster tool
+	err = server.RegisterTool("join_cluster", "Join an existing replication cluster",
+		func(ctx context.Context, args joinClusterToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "join_cluster", args, r.handleJoinClusterTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Alter cluster tool
+	err = server.RegisterTool("alter_cluster", "Add or drop tables in a cluster, or update its nodes list",
+		func(ctx context.Context, args alterClusterToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "alter_cluster", args, r.handleAlterClusterTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Delete cluster tool
+	err = server.RegisterTool("delete_cluster", "Delete a replication cluster",
+		func(ctx context.Context, args deleteClusterToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "delete_cluster", args, r.handleDeleteClusterTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Set cluster variable tool
+	return server.RegisterTool("set_cluster", "Set a replication cluster variable",
+		func(ctx context.Context, args setClusterToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "set_cluster", args, r.handleSetClusterTool)
+		})
+}
+
+// registerSQLTools registers the raw SQL tool, restricted to the configured statement types
+func (r *Registry) registerSQLTools(server *mcp_golang.Server) error {
+	err := server.RegisterTool("execute_sql", "Execute a raw SQL statement of an allowed type (by default SELECT, SHOW, DESCRIBE and CALL) and return all result sets",
+		func(ctx context.Context, args executeSQLToolArgs) (*mcp_golang.ToolResponse, error) {
+			return r.withDryRun(ctx, "execute_sql", args, r.handleExecuteSQLTool)
+		})
+	if err != nil {
+		return err
+	}
+
+	policy := r.sqlPolicy()
+	if len(policy.AllowedStatements) < len(r.config.SQLAllowedStatements) {
+		r.logger.Warn("Write statement types of execute_sql are disabled without --enable-writes",
+			"configured", r.config.SQLAllowedStatements, "allowed_statements", policy.AllowedStatements)
+	}
+
+	r.logger.Debug("SQL tools registered", "allowed_statements", policy.AllowedStatements)
+	return nil
+}
+
+// sqlPolicy returns the statement policy of execute_sql from the configuration. Unless writes
+// are enabled, write statement types are dropped from the allowlist and write statements of
+// allowed types, such as SET GLOBAL, are rejected.
+func (r *Registry) sqlPolicy() rawsql.Policy {
+	allowed := r.config.SQLAllowedStatements
+	if !r.writesEnabled() {
+		allowed = rawsql.ReadStatements(allowed)
+	}
+	if len(allowed) == 0 {
+		allowed = rawsql.DefaultAllowedStatements
+	}
+	return rawsql.Policy{
+		AllowedStatements: allowed,
+		AllowMultiple:     r.config.SQLAllowMultiStatements,
+		DenyWrites:        !r.writesEnabled(),
+		MaxRows:           r.config.MaxResultsPerQuery,
+	}
+}
+
+// handleSearchTool processes search requests
+func (r *Registry) handleSearchTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	// Convert map to search args struct
+	searchArgs, err := r.mapToSearchArgs(args)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid search arguments: %v", err))
+	}
+
+	if len(searchArgs.Where) > 0 && !r.config.AllowRawWhere {
+		return r.errorResponse("Raw where conditions are disabled, use filter instead (or start the server with --allow-raw-where)")
+	}
+
+	// Apply default limit from config
+	if searchArgs.Limit <= 0 {
+		searchArgs.Limit = r.config.MaxResultsPerQuery
+	}
+
+	// Execute search
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Search.Execute(ctx, *searchArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Search failed: %v", err))
+	}
+
+	// Create response
+	response := &Response{
+		Success: true,
+		Data:    result.Hits,
+		Meta: &Meta{
+			Total:         result.Total,
+			TotalRelation: result.TotalRelation,
+			Count:         len(result.Hits),
+			Limit:         searchArgs.Limit,
+			Offset:        searchArgs.Offset,
+			HasMore:       result.HasMore,
+			QueryTimeMs:   result.QueryTimeMs,
+			Keywords:      result.Keywords,
+			Facets:        result.Facets,
+			Table:         searchArgs.Table,
+			Cluster:       searchArgs.Cluster,
+			Operation:     "search",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleExplainSearchTool processes search explain requests
+func (r *Registry) handleExplainSearchTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	searchArgs, err := r.mapToSearchArgs(args)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid search arguments: %v", err))
+	}
+
+	if len(searchArgs.Where) > 0 && !r.config.AllowRawWhere {
+		return r.errorResponse("Raw where conditions are disabled, use filter instead (or start the server with --allow-raw-where)")
+	}
+
+	// Apply default limit from config, like search
+	if searchArgs.Limit <= 0 {
+		searchArgs.Limit = r.config.MaxResultsPerQuery
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Search.Explain(ctx, *searchArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Explain failed: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Total:       result.Total,
+			Count:       result.Hits,
+			Limit:       searchArgs.Limit,
+			Offset:      searchArgs.Offset,
+			QueryTimeMs: result.QueryTimeMs,
+			Keywords:    result.Keywords,
+			Table:       searchArgs.Table,
+			Cluster:     searchArgs.Cluster,
+			Operation:   "explain_search",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleAggregateTool processes aggregation requests
+func (r *Registry) handleAggregateTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var aggregateArgs aggregate.Args
+	if err := r.decodeObjectArg(args, "arguments", &aggregateArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid aggregate arguments: %v", err))
+	}
+	if aggregateArgs.Table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+
+	// Apply default limit from config
+	if aggregateArgs.Limit <= 0 {
+		aggregateArgs.Limit = r.config.MaxResultsPerQuery
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Aggregate.Execute(ctx, aggregateArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Aggregation failed: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Total:       result.TotalGroups,
+			Count:       len(result.Buckets),
+			Limit:       aggregateArgs.Limit,
+			Offset:      aggregateArgs.Offset,
+			HasMore:     result.HasMore,
+			QueryTimeMs: result.QueryTimeMs,
+			Table:       aggregateArgs.Table,
+			Cluster:     aggregateArgs.Cluster,
+			Operation:   "aggregate",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleExecuteSQLTool processes raw SQL requests
+func (r *Registry) handleExecuteSQLTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	sqlArgs := rawsql.Args{
+		Query: r.getStringArg(args, "query"),
+	}
+	if sqlArgs.Query == "" {
+		return r.errorResponse("Query parameter is required")
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).RawSQL.Execute(ctx, sqlArgs, r.sqlPolicy())
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to execute SQL: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Count:     len(result.ResultSets),
+			Operation: "execute_sql",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleShowTablesTool processes show tables requests
+func (r *Registry) handleShowTablesTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	tablesArgs := tables.ShowTablesArgs{
+		Pattern: r.getStringArg(args, "pattern"),
+		Cluster: r.getStringArg(args, "cluster"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	tablesList, err := r.toolsFor(ctx).Tables.ShowTables(ctx, tablesArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to show tables: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    tablesList,
+		Meta: &Meta{
+			Total:     len(tablesList),
+			Count:     len(tablesList),
+			Cluster:   tablesArgs.Cluster,
+			Operation: "show_tables",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleDescribeTableTool processes describe table requests
+func (r *Registry) handleDescribeTableTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	table := r.getStringArg(args, "table")
+	if table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+
+	describeArgs := tables.DescribeTableArgs{
+		Table:   table,
+		Cluster: r.getStringArg(args, "cluster"),
+		Details: r.getBoolArg(args, "details"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	var (
+		schema interface{}
+		err    error
+	)
+	if describeArgs.Details {
+		schema, err = r.toolsFor(ctx).Tables.DescribeTableDetails(ctx, describeArgs)
+	} else {
+		schema, err = r.toolsFor(ctx).Tables.DescribeTable(ctx, describeArgs)
+	}
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to describe table: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    schema,
+		Meta: &Meta{
+			Table:     describeArgs.Table,
+			Cluster:   describeArgs.Cluster,
+			Operation: "describe_table",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleCreateTableTool processes table creation requests
+func (r *Registry) handleCreateTableTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var createArgs tables.CreateTableArgs
+	if err := r.decodeObjectArg(args, "arguments", &createArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid table definition: %v", err))
+	}
+	if createArgs.Table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Tables.CreateTable(ctx, createArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to create table: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Table:     createArgs.Table,
+			Cluster:   createArgs.Cluster,
+			Operation: "create_table",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleAlterTableTool processes table alteration requests
+func (r *Registry) handleAlterTableTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var alterArgs tables.AlterTableArgs
+	if err := r.decodeObjectArg(args, "arguments", &alterArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid alter arguments: %v", err))
+	}
+	if alterArgs.Table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Tables.AlterTable(ctx, alterArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to alter table: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Count:     len(result.Statements),
+			Table:     alterArgs.Table,
+			Cluster:   alterArgs.Cluster,
+			Operation: "alter_table",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleMigrateSchemaTool processes schema migration requests
+func (r *Registry) handleMigrateSchemaTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	if r.config.MigrationsDir == "" {
+		return r.errorResponse("Migrations are disabled: the server has no migrations directory")
+	}
+
+	migrateArgs := migrations.MigrateArgs{
+		Apply:        r.getBoolArg(args, "apply"),
+		Dir:          r.config.MigrationsDir,
+		HistoryTable: r.config.MigrationsTable,
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	plan, err := r.toolsFor(ctx).Migrations.Migrate(ctx, migrateArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to migrate schema: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    plan,
+		Meta: &Meta{
+			Count:     plan.Statements,
+			Operation: "migrate_schema",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleInsertDocumentTool processes document insertion requests
+func (r *Registry) handleInsertDocumentTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	table := r.getStringArg(args, "table")
+	if table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+
+	document, exists := args["document"]
+	if !exists {
+		return r.errorResponse("Document parameter is required")
+	}
+
+	documentMap, ok := document.(map[string]interface{})
+	if !ok {
+		return r.errorResponse("Document must be a valid object")
+	}
+
+	insertArgs := documents.InsertDocumentArgs{
+		Table:    table,
+		Cluster:  r.getStringArg(args, "cluster"),
+		Document: documentMap,
+		Replace:  r.getBoolArg(args, "replace"),
+	}
+
+	// Replacing overwrites an existing document, like insert_documents with replace
+	if insertArgs.Replace && !r.writesEnabled() {
+		return r.errorResponse(replaceDisabled)
+	}
+
+	// Handle optional ID
+	if idVal := r.getIntArg(args, "id"); idVal != 0 {
+		id := int64(idVal)
+		insertArgs.ID = &id
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Documents.InsertDocument(ctx, insertArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to insert document: %v", err))
+	}
+
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Table:     insertArgs.Table,
+			Cluster:   insertArgs.Cluster,
+			Operation: "insert_document",
+		},
+	}
+
+	return r.successResponse(response)
+}
+
+// handleInsertDocumentsTool processes multi-document insertion requests
+func (r *Registry) handleInsertDocumentsTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var insertArgs documents.InsertDocumentsArgs
+	if err := r.decodeObjectArg(args, "arguments", &insertArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid insert arguments: %v", err))
+	}
+	if insertArgs.Table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+	if len(insertArgs.Documents) == 0 {
+		return r.errorResponse("Documents parameter is required")
+	}
+	// Replacing overwrites existing documents in bulk, like bulk_documents
+	if insertArgs.Replace && !r.writesEnabled() {
+		return r.errorResponse(replaceDisabled)
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Documents.InsertDocuments(ctx, insertArgs)
 	if err != nil {
-		return err
+		// Batches inserted before the failure stay committed, report what was written
+		if result != nil {
+			return r.errorResponseWithData(fmt.Sprintf("Failed to insert documents: %v", err), result)
+		}
+		return r.errorResponse(fmt.Sprintf("Failed to insert documents: %v", err))
 	}
 
-	r.logger.Debug("Table management tools registered")
-	return nil
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Count:     result.Inserted,
+			Table:     insertArgs.Table,
+			Cluster:   insertArgs.Cluster,
+			Operation: "insert_documents",
+		},
+	}
+
+	return r.successResponse(response)
 }
 
-// registerDocumentTools registers document operation tools
-func (r *Registry) registerDocumentTools(server *mcp_golang.Server) error {
-	// Insert document tool
-	err := server.RegisterTool("insert_document", "Insert a new document into Manticore index",
-		func(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-			return r.handleInsertDocumentTool(args)
-		})
-	if err != nil {
-		return err
+// handleUpdateDocumentTool processes document update requests
+func (r *Registry) handleUpdateDocumentTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	table := r.getStringArg(args, "table")
+	if table == "" {
+		return r.errorResponse("Table parameter is required")
 	}
 
-	r.logger.Debug("Document operation tools registered")
-	return nil
-}
+	if _, exists := args["id"]; !exists {
+		return r.errorResponse("ID parameter is required")
+	}
 
-// registerClusterTools registers cluster management tools
-func (r *Registry) registerClusterTools(server *mcp_golang.Server) error {
-	// Show cluster status tool
-	err := server.RegisterTool("show_cluster_status", "Show status of cluster nodes",
-		func(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-			return r.handleClusterStatusTool(args)
-		})
-	if err != nil {
-		return err
+	document, exists := args["document"]
+	if !exists {
+		return r.errorResponse("Document parameter is required")
 	}
 
-	r.logger.Debug("Cluster tools registered")
-	return nil
-}
+	documentMap, ok := document.(map[string]interface{})
+	if !ok {
+		return r.errorResponse("Document must be a valid object")
+	}
 
-// handleSearchTool processes search requests
-func (r *Registry) handleSearchTool(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-	// Convert map to search args struct
-	searchArgs, err := r.mapToSearchArgs(args)
-	if err != nil {
-		return r.errorResponse(fmt.Sprintf("Invalid search arguments: %v", err))
+	updateArgs := documents.UpdateDocumentArgs{
+		Table:     table,
+		Cluster:   r.getStringArg(args, "cluster"),
+		ID:        int64(r.getIntArg(args, "id")),
+		Document:  documentMap,
+		Condition: r.getStringArg(args, "condition"),
 	}
 
-	// Apply default limit from config
-	if searchArgs.Limit <= 0 {
-		searchArgs.Limit = r.config.MaxResultsPerQuery
+	if updateArgs.Condition != "" && !r.config.AllowRawWhere {
+		return r.errorResponse(rawConditionDisabled)
 	}
 
-	// Execute search
-	ctx := context.Background()
-	results, err := r.tools.Search.Execute(ctx, *searchArgs)
+	if filterData, exists := args["filter"]; exists && filterData != nil {
+		filter, err := r.mapToFilter(filterData)
+		if err != nil {
+			return r.errorResponse(fmt.Sprintf("Invalid filter: %v", err))
+		}
+		updateArgs.Filter = filter
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Documents.UpdateDocument(ctx, updateArgs)
 	if err != nil {
-		return r.errorResponse(fmt.Sprintf("Search failed: %v", err))
+		return r.errorResponse(fmt.Sprintf("Failed to update document: %v", err))
 	}
 
-	// Create response
 	response := &Response{
 		Success: true,
-		Data:    results,
+		Data:    result,
 		Meta: &Meta{
-			Total:     len(results),
-			Count:     len(results),
-			Limit:     searchArgs.Limit,
-			Offset:    searchArgs.Offset,
-			Table:     searchArgs.Table,
-			Cluster:   searchArgs.Cluster,
-			Operation: "search",
+			Table:     updateArgs.Table,
+			Cluster:   updateArgs.Cluster,
+			Operation: "update_document",
 		},
 	}
 
 	return r.successResponse(response)
 }
 
-// handleShowTablesTool processes show tables requests
-func (r *Registry) handleShowTablesTool(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-	tablesArgs := tables.ShowTablesArgs{
-		Pattern: r.getStringArg(args, "pattern"),
-		Cluster: r.getStringArg(args, "cluster"),
+// handleUpdateByQueryTool processes update by query requests
+func (r *Registry) handleUpdateByQueryTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var updateArgs documents.UpdateByQueryArgs
+	if err := r.decodeObjectArg(args, "arguments", &updateArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid update arguments: %v", err))
 	}
+	if updateArgs.Table == "" {
+		return r.errorResponse("Table parameter is required")
+	}
+	if len(updateArgs.Set) == 0 {
+		return r.errorResponse("Set parameter is required")
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
 
-	ctx := context.Background()
-	tablesList, err := r.tools.Tables.ShowTables(ctx, tablesArgs)
+	result, err := r.toolsFor(ctx).Documents.UpdateByQuery(ctx, updateArgs)
 	if err != nil {
-		return r.errorResponse(fmt.Sprintf("Failed to show tables: %v", err))
+		return r.errorResponse(fmt.Sprintf("Failed to update documents: %v", err))
+	}
+
+	count := result.Affected
+	if result.Preview {
+		count = result.Matched
 	}
 
 	response := &Response{
 		Success: true,
-		Data:    tablesList,
+		Data:    result,
 		Meta: &Meta{
-			Total:     len(tablesList),
-			Count:     len(tablesList),
-			Cluster:   tablesArgs.Cluster,
-			Operation: "show_tables",
+			Count:     count,
+			Table:     updateArgs.Table,
+			Cluster:   updateArgs.Cluster,
+			Operation: "update_by_query",
 		},
 	}
 
 	return r.successResponse(response)
 }
 
-// handleDescribeTableTool processes describe table requests
-func (r *Registry) handleDescribeTableTool(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+// handleDeleteDocumentTool processes document deletion requests
+func (r *Registry) handleDeleteDocumentTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
 	table := r.getStringArg(args, "table")
 	if table == "" {
 		return r.errorResponse("Table parameter is required")
 	}
 
-	describeArgs := tables.DescribeTableArgs{
-		Table:   table,
-		Cluster: r.getStringArg(args, "cluster"),
+	deleteArgs := documents.DeleteDocumentArgs{
+		Table:     table,
+		Cluster:   r.getStringArg(args, "cluster"),
+		Condition: r.getStringArg(args, "condition"),
+	}
+
+	if deleteArgs.Condition != "" && !r.config.AllowRawWhere {
+		return r.errorResponse(rawConditionDisabled)
+	}
+
+	if filterData, exists := args["filter"]; exists && filterData != nil {
+		filter, err := r.mapToFilter(filterData)
+		if err != nil {
+			return r.errorResponse(fmt.Sprintf("Invalid filter: %v", err))
+		}
+		deleteArgs.Filter = filter
+	}
+
+	// Handle optional ID
+	if _, exists := args["id"]; exists {
+		id := int64(r.getIntArg(args, "id"))
+		deleteArgs.ID = &id
 	}
 
-	ctx := context.Background()
-	schema, err := r.tools.Tables.DescribeTable(ctx, describeArgs)
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Documents.DeleteDocument(ctx, deleteArgs)
 	if err != nil {
-		return r.errorResponse(fmt.Sprintf("Failed to describe table: %v", err))
+		return r.errorResponse(fmt.Sprintf("Failed to delete document: %v", err))
 	}
 
 	response := &Response{
 		Success: true,
-		Data:    schema,
+		Data:    result,
 		Meta: &Meta{
-			Table:     describeArgs.Table,
-			Cluster:   describeArgs.Cluster,
-			Operation: "describe_table",
+			Table:     deleteArgs.Table,
+			Cluster:   deleteArgs.Cluster,
+			Operation: "delete_document",
 		},
 	}
 
 	return r.successResponse(response)
 }
 
-// handleInsertDocumentTool processes document insertion requests
-func (r *Registry) handleInsertDocumentTool(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
-	table := r.getStringArg(args, "table")
-	if table == "" {
+// handleDeleteByQueryTool processes delete by query requests
+func (r *Registry) handleDeleteByQueryTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var deleteArgs documents.DeleteByQueryArgs
+	if err := r.decodeObjectArg(args, "arguments", &deleteArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid delete arguments: %v", err))
+	}
+	if deleteArgs.Table == "" {
 		return r.errorResponse("Table parameter is required")
 	}
+	deleteArgs.Threshold = r.config.DeleteConfirmThreshold
 
-	document, exists := args["document"]
-	if !exists {
-		return r.errorResponse("Document parameter is required")
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Documents.DeleteByQuery(ctx, deleteArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to delete documents: %v", err))
 	}
 
-	documentMap, ok := document.(map[string]interface{})
-	if !ok {
-		return r.errorResponse("Document must be a valid object")
+	count := result.Deleted
+	if result.Preview {
+		count = result.Matched
 	}
 
-	insertArgs := documents.InsertDocumentArgs{
-		Table:    table,
-		Cluster:  r.getStringArg(args, "cluster"),
-		Document: documentMap,
-		Replace:  r.getBoolArg(args, "replace"),
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Count:     count,
+			Table:     deleteArgs.Table,
+			Cluster:   deleteArgs.Cluster,
+			Operation: "delete_by_query",
+		},
 	}
 
-	// Handle optional ID
-	if idVal := r.getIntArg(args, "id"); idVal != 0 {
-		id := int64(idVal)
-		insertArgs.ID = &id
+	return r.successResponse(response)
+}
+
+// handleBulkDocumentsTool processes bulk document requests
+func (r *Registry) handleBulkDocumentsTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	var bulkArgs documents.BulkArgs
+	if err := r.decodeObjectArg(args, "arguments", &bulkArgs); err != nil {
+		return r.errorResponse(fmt.Sprintf("Invalid bulk arguments: %v", err))
+	}
+
+	if bulkArgs.File != "" {
+		path, err := r.resolveBulkFile(bulkArgs.File)
+		if err != nil {
+			return r.errorResponse(fmt.Sprintf("Invalid bulk file: %v", err))
+		}
+		bulkArgs.File = path
 	}
 
-	ctx := context.Background()
-	result, err := r.tools.Documents.InsertDocument(ctx, insertArgs)
+	// Apply default batch size from config
+	if bulkArgs.BatchSize <= 0 {
+		bulkArgs.BatchSize = r.config.BulkBatchSize
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Documents.Bulk(ctx, bulkArgs)
 	if err != nil {
-		return r.errorResponse(fmt.Sprintf("Failed to insert document: %v", err))
+		return r.errorResponse(fmt.Sprintf("Bulk operation failed: %v", err))
 	}
 
 	response := &Response{
 		Success: true,
 		Data:    result,
 		Meta: &Meta{
-			Table:     insertArgs.Table,
-			Cluster:   insertArgs.Cluster,
-			Operation: "insert_document",
+			Total:     len(result.Items),
+			Count:     result.Succeeded,
+			Table:     bulkArgs.Table,
+			Cluster:   bulkArgs.Cluster,
+			Operation: "bulk_documents",
 		},
 	}
 
 	return r.successResponse(response)
 }
 
+// resolveBulkFile resolves the path of a bulk file relative to --bulk-file-dir and rejects
+// files outside of it, including through symbolic links
+func (r *Registry) resolveBulkFile(path string) (string, error) {
+	if r.config.BulkFileDir == "" {
+		return "", fmt.Errorf("file input is disabled, start the server with --bulk-file-dir")
+	}
+
+	root, err := filepath.Abs(r.config.BulkFileDir)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid bulk file directory: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("bulk file not found: %w", err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside of the bulk file directory", path)
+	}
+	return resolved, nil
+}
+
 // handleClusterStatusTool processes cluster status requests
-func (r *Registry) handleClusterStatusTool(args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+func (r *Registry) handleClusterStatusTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
 	statusArgs := clusters.ShowClusterStatusArgs{
 		Pattern: r.getStringArg(args, "pattern"),
 	}
 
-	ctx := context.Background()
-	status, err := r.tools.Clusters.ShowClusterStatus(ctx, statusArgs)
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	status, err := r.toolsFor(ctx).Clusters.ShowClusterStatus(ctx, statusArgs)
 	if err != nil {
 		return r.errorResponse(fmt.Sprintf("Failed to get cluster status: %v", err))
 	}
@@ -316,8 +1155,117 @@ func (r *Registry) handleClusterStatusTool(args map[string]interface{}) (*mcp_go
 	return r.successResponse(response)
 }
 
+// handleCreateClusterTool processes cluster creation requests
+func (r *Registry) handleCreateClusterTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	createArgs := clusters.CreateClusterArgs{
+		Name:  r.getStringArg(args, "name"),
+		Path:  r.getStringArg(args, "path"),
+		Nodes: r.getStringSliceArg(args, "nodes"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Clusters.CreateCluster(ctx, createArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to create cluster: %v", err))
+	}
+
+	return r.clusterResponse(result, createArgs.Name, "create_cluster")
+}
+
+// handleJoinClusterTool processes cluster join requests
+func (r *Registry) handleJoinClusterTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	joinArgs := clusters.JoinClusterArgs{
+		Name:  r.getStringArg(args, "name"),
+		At:    r.getStringArg(args, "at"),
+		Nodes: r.getStringSliceArg(args, "nodes"),
+		Path:  r.getStringArg(args, "path"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Clusters.JoinCluster(ctx, joinArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to join cluster: %v", err))
+	}
+
+	return r.clusterResponse(result, joinArgs.Name, "join_cluster")
+}
+
+// handleAlterClusterTool processes cluster modification requests
+func (r *Registry) handleAlterClusterTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	alterArgs := clusters.AlterClusterArgs{
+		Name:      r.getStringArg(args, "name"),
+		Operation: r.getStringArg(args, "operation"),
+		Table:     r.getStringArg(args, "table"),
+		Nodes:     r.getStringSliceArg(args, "nodes"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Clusters.AlterCluster(ctx, alterArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to alter cluster: %v", err))
+	}
+
+	return r.clusterResponse(result, alterArgs.Name, "alter_cluster")
+}
+
+// handleDeleteClusterTool processes cluster deletion requests
+func (r *Registry) handleDeleteClusterTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	deleteArgs := clusters.DeleteClusterArgs{
+		Name: r.getStringArg(args, "name"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Clusters.DeleteCluster(ctx, deleteArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to delete cluster: %v", err))
+	}
+
+	return r.clusterResponse(result, deleteArgs.Name, "delete_cluster")
+}
+
+// handleSetClusterTool processes cluster variable requests
+func (r *Registry) handleSetClusterTool(ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	setArgs := clusters.SetClusterArgs{
+		Name:     r.getStringArg(args, "name"),
+		Variable: r.getStringArg(args, "variable"),
+		Value:    r.getScalarStringArg(args, "value"),
+		Global:   r.getBoolArg(args, "global"),
+	}
+
+	ctx, cancel := r.callContext(ctx, args)
+	defer cancel()
+
+	result, err := r.toolsFor(ctx).Clusters.SetCluster(ctx, setArgs)
+	if err != nil {
+		return r.errorResponse(fmt.Sprintf("Failed to set cluster variable: %v", err))
+	}
+
+	return r.clusterResponse(result, setArgs.Name, "set_cluster")
+}
+
 // Helper methods
 
+func (r *Registry) clusterResponse(result []map[string]interface{}, cluster, operation string) (*mcp_golang.ToolResponse, error) {
+	response := &Response{
+		Success: true,
+		Data:    result,
+		Meta: &Meta{
+			Cluster:   cluster,
+			Operation: operation,
+		},
+	}
+
+	return r.successResponse(response)
+}
+
 func (r *Registry) successResponse(response *Response) (*mcp_golang.ToolResponse, error) {
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -329,10 +1277,28 @@ func (r *Registry) successResponse(response *Response) (*mcp_golang.ToolResponse
 	), nil
 }
 
+// responseFailed reports whether a tool response carries an error
+func (r *Registry) responseFailed(response *mcp_golang.ToolResponse) bool {
+	if response == nil || len(response.Content) == 0 || response.Content[0].TextContent == nil {
+		return true
+	}
+	var decoded Response
+	if err := json.Unmarshal([]byte(response.Content[0].TextContent.Text), &decoded); err != nil {
+		return true
+	}
+	return !decoded.Success
+}
+
 func (r *Registry) errorResponse(message string) (*mcp_golang.ToolResponse, error) {
+	return r.errorResponseWithData(message, nil)
+}
+
+// errorResponseWithData reports an error together with the partial result of the call
+func (r *Registry) errorResponseWithData(message string, data interface{}) (*mcp_golang.ToolResponse, error) {
 	response := &Response{
 		Success: false,
 		Error:   message,
+		Data:    data,
 	}
 
 	jsonData, _ := json.MarshalIndent(response, "", "  ")
@@ -350,6 +1316,23 @@ func (r *Registry) getStringArg(args map[string]interface{}, key string) string
 	return ""
 }
 
+// getScalarStringArg returns string, number or bool argument formatted as string
+func (r *Registry) getScalarStringArg(args map[string]interface{}, key string) string {
+	if val, exists := args[key]; exists {
+		switch v := val.(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			return strconv.Itoa(v)
+		case bool:
+			return strconv.FormatBool(v)
+		}
+	}
+	return ""
+}
+
 func (r *Registry) getIntArg(args map[string]interface{}, key string) int {
 	if val, exists := args[key]; exists {
 		switch v := val.(type) {
@@ -395,6 +1378,7 @@ func (r *Registry) mapToSearchArgs(args map[string]interface{}) (*search.Args, e
 		Morphology:          r.getStringArg(args, "morphology"),
 		TokenFilter:         r.getStringArg(args, "token_filter"),
 		MaxPredictedTime:    r.getIntArg(args, "max_predicted_time"),
+		TimeoutMs:           r.getIntArg(args, "timeout_ms"),
 
 		// Ordering
 		OrderBy:   r.getStringSliceArg(args, "order_by"),
@@ -437,6 +1421,42 @@ func (r *Registry) mapToSearchArgs(args map[string]interface{}) (*search.Args, e
 		}
 	}
 
+	// Handle structured filter
+	if filterData, exists := args["filter"]; exists && filterData != nil {
+		filter, err := r.mapToFilter(filterData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		searchArgs.Filter = filter
+	}
+
+	// Handle vector search
+	if knnData, exists := args["knn"]; exists && knnData != nil {
+		knn, err := r.mapToKNN(knnData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid knn: %w", err)
+		}
+		searchArgs.KNN = knn
+	}
+
+	// Handle facets
+	if facetsData, exists := args["facets"]; exists && facetsData != nil {
+		facets, err := r.mapToFacets(facetsData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid facets: %w", err)
+		}
+		searchArgs.Facets = facets
+	}
+
+	// Handle hybrid search
+	if hybridData, exists := args["hybrid"]; exists && hybridData != nil {
+		var hybrid search.HybridOptions
+		if err := r.decodeObjectArg(hybridData, "hybrid", &hybrid); err != nil {
+			return nil, fmt.Errorf("invalid hybrid: %w", err)
+		}
+		searchArgs.Hybrid = &hybrid
+	}
+
 	// Handle boolean query
 	if boolQueryData, exists := args["bool_query"]; exists {
 		if boolQueryMap, ok := boolQueryData.(map[string]interface{}); ok {
@@ -456,6 +1476,55 @@ func (r *Registry) mapToSearchArgs(args map[string]interface{}) (*search.Args, e
 	return searchArgs, nil
 }
 
+// mapToFilter converts filter argument into search Filter struct
+func (r *Registry) mapToFilter(data interface{}) (*search.Filter, error) {
+	var filter search.Filter
+	if err := r.decodeObjectArg(data, "filter", &filter); err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// mapToKNN converts a knn argument into vector search options
+func (r *Registry) mapToKNN(data interface{}) (*search.KNNOptions, error) {
+	var knn search.KNNOptions
+	if err := r.decodeObjectArg(data, "knn", &knn); err != nil {
+		return nil, err
+	}
+	return &knn, nil
+}
+
+// mapToFacets converts a facets argument into facet options
+func (r *Registry) mapToFacets(data interface{}) ([]search.FacetOptions, error) {
+	if _, ok := data.([]interface{}); !ok {
+		return nil, fmt.Errorf("facets must be an array")
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var facets []search.FacetOptions
+	if err := json.Unmarshal(raw, &facets); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// decodeObjectArg decodes an object argument into a typed struct through its JSON form
+func (r *Registry) decodeObjectArg(data interface{}, name string, target interface{}) error {
+	if _, ok := data.(map[string]interface{}); !ok {
+		return fmt.Errorf("%s must be an object", name)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
 func (r *Registry) getBoolArg(args map[string]interface{}, key string) bool {
 	if val, exists := args[key]; exists {
 		if b, ok := val.(bool); ok {
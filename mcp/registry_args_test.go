@@ -1,9 +1,13 @@
 package mcp
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"manticore-mcp-server/client"
 	"manticore-mcp-server/config"
+	"manticore-mcp-server/tools/rawsql"
 	"manticore-mcp-server/tools/search"
 
 	"github.com/stretchr/testify/assert"
@@ -228,6 +232,103 @@ func TestRegistry_mapToSearchArgs_ComplexOptions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "with structured filter",
+			args: map[string]interface{}{
+				"table": "items",
+				"filter": map[string]interface{}{
+					"or": []interface{}{
+						map[string]interface{}{"field": "price", "operator": "gt", "value": float64(100)},
+						map[string]interface{}{"field": "tags", "operator": "in", "values": []interface{}{float64(1), float64(2)}, "quantifier": "any"},
+					},
+				},
+			},
+			expected: &search.Args{
+				Table: "items",
+				Filter: &search.Filter{Or: []search.Filter{
+					{Field: "price", Operator: "gt", Value: float64(100)},
+					{Field: "tags", Operator: "in", Values: []interface{}{float64(1), float64(2)}, Quantifier: "any"},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with invalid filter",
+			args: map[string]interface{}{
+				"table":  "items",
+				"filter": "price > 100",
+			},
+			wantErr: true,
+		},
+		{
+			name: "with knn",
+			args: map[string]interface{}{
+				"table": "images",
+				"knn": map[string]interface{}{
+					"field":  "image_vector",
+					"k":      float64(5),
+					"vector": []interface{}{0.1, float64(-1)},
+					"ef":     float64(200),
+				},
+			},
+			expected: &search.Args{
+				Table: "images",
+				KNN:   &search.KNNOptions{Field: "image_vector", K: 5, Vector: []float64{0.1, -1}, EF: 200},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with invalid knn",
+			args: map[string]interface{}{
+				"table": "images",
+				"knn":   []interface{}{0.1, 0.2},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with hybrid",
+			args: map[string]interface{}{
+				"table":  "docs",
+				"query":  "vector database",
+				"knn":    map[string]interface{}{"field": "embedding", "k": float64(20), "vector": []interface{}{0.1, 0.2}},
+				"hybrid": map[string]interface{}{"fusion": "weighted", "text_weight": 0.3, "vector_weight": 0.7},
+			},
+			expected: &search.Args{
+				Table:  "docs",
+				Query:  "vector database",
+				KNN:    &search.KNNOptions{Field: "embedding", K: 20, Vector: []float64{0.1, 0.2}},
+				Hybrid: &search.HybridOptions{Fusion: "weighted", TextWeight: 0.3, VectorWeight: 0.7},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with facets",
+			args: map[string]interface{}{
+				"table": "products",
+				"query": "laptop",
+				"facets": []interface{}{
+					map[string]interface{}{"field": "brand_id", "limit": float64(5)},
+					map[string]interface{}{"field": "price", "name": "price_band", "ranges": []interface{}{float64(100), float64(500)}},
+				},
+			},
+			expected: &search.Args{
+				Table: "products",
+				Query: "laptop",
+				Facets: []search.FacetOptions{
+					{Field: "brand_id", Limit: 5},
+					{Field: "price", Name: "price_band", Ranges: []float64{100, 500}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with invalid facets",
+			args: map[string]interface{}{
+				"table":  "products",
+				"facets": map[string]interface{}{"field": "brand_id"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,3 +344,210 @@ func TestRegistry_mapToSearchArgs_ComplexOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistry_handleSearchTool_RawWhere(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10})
+
+	response, err := registry.handleSearchTool(context.Background(), map[string]interface{}{
+		"table": "items",
+		"query": "laptop",
+		"where": []interface{}{"1=1) OR (1=1"},
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"].(string), "Raw where conditions are disabled")
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+
+	registry, mockClient = newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10, AllowRawWhere: true})
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, _ string) ([]client.SQLResult, error) {
+		return []client.SQLResult{{Rows: []map[string]interface{}{}}}, nil
+	}
+
+	response, err = registry.handleSearchTool(context.Background(), map[string]interface{}{
+		"table": "items",
+		"query": "laptop",
+		"where": []interface{}{"price > 100"},
+	})
+	require.NoError(t, err)
+
+	result = parseToolResponse(t, response)
+	assert.True(t, result["success"].(bool))
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].Query, "AND (price > 100)")
+}
+
+func TestRegistry_handleSearchTool_Timeout(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10})
+	mockClient.ExecuteSQLResultsFunc = func(ctx context.Context, query string) ([]client.SQLResult, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok, "timeout_ms must set a deadline")
+		assert.WithinDuration(t, time.Now().Add(200*time.Millisecond), deadline, 200*time.Millisecond)
+		assert.Contains(t, query, "max_query_time=200")
+		return []client.SQLResult{{Rows: []map[string]interface{}{}}}, nil
+	}
+
+	response, err := registry.handleSearchTool(context.Background(), map[string]interface{}{
+		"table":      "items",
+		"query":      "laptop",
+		"timeout_ms": float64(200),
+	})
+	require.NoError(t, err)
+	assert.True(t, parseToolResponse(t, response)["success"].(bool))
+	require.Len(t, mockClient.ExecuteSQLResultsCalls(), 1)
+}
+
+func TestRegistry_handleSearchTool_Canceled(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10})
+	mockClient.ExecuteSQLResultsFunc = func(ctx context.Context, _ string) ([]client.SQLResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	mockClient.KillQueryFunc = func(_ context.Context, _ string) (int, error) {
+		return 1, nil
+	}
+
+	// The MCP request context is canceled on notifications/cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	response, err := registry.handleSearchTool(ctx, map[string]interface{}{
+		"table": "items",
+		"query": "laptop",
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"].(string), "context canceled")
+	assert.Len(t, mockClient.KillQueryCalls(), 1)
+}
+
+func TestRegistry_handleAggregateTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10})
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, _ string) ([]client.SQLResult, error) {
+		return []client.SQLResult{
+			{Rows: []map[string]interface{}{{"brand_id": float64(3), "count": float64(42)}}},
+			{Rows: []map[string]interface{}{{"Variable_name": "total_found", "Value": "1"}}},
+		}, nil
+	}
+
+	response, err := registry.handleAggregateTool(context.Background(), map[string]interface{}{
+		"table":      "products",
+		"dimensions": []interface{}{"brand_id"},
+		"metrics":    []interface{}{map[string]interface{}{"function": "count"}},
+		"filter":     map[string]interface{}{"field": "price", "operator": "lt", "value": float64(1000)},
+		"having":     map[string]interface{}{"field": "count", "operator": "gte", "value": float64(10)},
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	data := result["data"].(map[string]interface{})
+	assert.InDelta(t, 1, data["total_groups"], 0)
+	assert.Len(t, data["buckets"], 1)
+	assert.Equal(t, "aggregate", result["meta"].(map[string]interface{})["operation"])
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].Query, "WHERE (price < 1000) GROUP BY brand_id HAVING count >= 10 ORDER BY count DESC LIMIT 10")
+
+	response, err = registry.handleAggregateTool(context.Background(), map[string]interface{}{
+		"table":   "products",
+		"metrics": []interface{}{map[string]interface{}{"function": "avg", "field": "price) FROM secrets --"}},
+	})
+	require.NoError(t, err)
+	assert.False(t, parseToolResponse(t, response)["success"].(bool))
+	assert.Len(t, mockClient.ExecuteSQLResultsCalls(), 1)
+}
+
+func TestRegistry_handleExecuteSQLTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10})
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, _ string) ([]client.SQLResult, error) {
+		return []client.SQLResult{{
+			Columns: []client.SQLColumn{{Name: "id", Type: "long long"}},
+			Rows:    []map[string]interface{}{{"id": float64(1)}},
+			Total:   1,
+		}}, nil
+	}
+
+	response, err := registry.handleExecuteSQLTool(context.Background(), map[string]interface{}{"query": "SELECT id FROM products"})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	data := result["data"].(map[string]interface{})
+	resultSets := data["result_sets"].([]interface{})
+	require.Len(t, resultSets, 1)
+	assert.Len(t, resultSets[0].(map[string]interface{})["columns"], 1)
+	assert.Equal(t, "execute_sql", result["meta"].(map[string]interface{})["operation"])
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "SELECT id FROM products LIMIT 10", calls[0].Query)
+
+	for _, query := range []string{"", "DELETE FROM products WHERE id=1", "SELECT 1; SHOW META"} {
+		response, err = registry.handleExecuteSQLTool(context.Background(), map[string]interface{}{"query": query})
+		require.NoError(t, err)
+		assert.False(t, parseToolResponse(t, response)["success"].(bool), query)
+	}
+	assert.Len(t, mockClient.ExecuteSQLResultsCalls(), 1)
+}
+
+func TestRegistry_sqlPolicy_Writes(t *testing.T) {
+	allowed := []string{"SELECT", "INSERT", "DELETE", "SET"}
+
+	registry, mockClient := newMockRegistry(t, &config.Config{SQLAllowedStatements: allowed})
+	policy := registry.sqlPolicy()
+	assert.Equal(t, []string{"SELECT", "SET"}, policy.AllowedStatements)
+	assert.True(t, policy.DenyWrites)
+
+	for _, query := range []string{"INSERT INTO products (title) VALUES ('a')", "DELETE FROM products WHERE id=1", "SET GLOBAL query_log_format=sphinxql"} {
+		response, err := registry.handleExecuteSQLTool(context.Background(), map[string]interface{}{"query": query})
+		require.NoError(t, err)
+		assert.False(t, parseToolResponse(t, response)["success"].(bool), query)
+	}
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+
+	// Only write types configured falls back to the read-only defaults
+	registry, _ = newMockRegistry(t, &config.Config{SQLAllowedStatements: []string{"INSERT"}})
+	assert.Equal(t, rawsql.DefaultAllowedStatements, registry.sqlPolicy().AllowedStatements)
+
+	registry, _ = newMockRegistry(t, &config.Config{SQLAllowedStatements: allowed, EnableWrites: true})
+	policy = registry.sqlPolicy()
+	assert.Equal(t, allowed, policy.AllowedStatements)
+	assert.False(t, policy.DenyWrites)
+}
+
+func TestRegistry_handleDescribeTableTool_Details(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{})
+	mockClient.ExecuteSQLFunc = func(_ context.Context, query string) ([]map[string]interface{}, error) {
+		switch query {
+		case "DESCRIBE products":
+			return []map[string]interface{}{{"Field": "id", "Type": "bigint", "Properties": ""}, {"Field": "title", "Type": "text", "Properties": "indexed stored"}}, nil
+		case "SHOW CREATE TABLE products":
+			return []map[string]interface{}{{"Create Table": "CREATE TABLE products (\nid bigint,\ntitle text\n) morphology='stem_en'"}}, nil
+		case "SHOW TABLE products STATUS":
+			return []map[string]interface{}{{"Variable_name": "indexed_documents", "Value": "42"}}, nil
+		}
+		return []map[string]interface{}{}, nil
+	}
+
+	response, err := registry.handleDescribeTableTool(context.Background(), map[string]interface{}{"table": "products", "details": true})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	data := result["data"].(map[string]interface{})
+	assert.Len(t, data["columns"], 2)
+	assert.Equal(t, map[string]interface{}{"morphology": "stem_en"}, data["settings"])
+	assert.InDelta(t, 42, data["status"].(map[string]interface{})["documents"], 0)
+
+	var queries []string
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		queries = append(queries, call.Query)
+	}
+	assert.Equal(t, []string{"DESCRIBE products", "SHOW CREATE TABLE products", "SHOW TABLE products SETTINGS", "SHOW TABLE products STATUS"}, queries)
+}
@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"manticore-mcp-server/config"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_withDryRun(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{
+		ManticoreURL:       "http://manticore:9308",
+		MaxResultsPerQuery: 10,
+		EnableWrites:       true,
+	})
+
+	tests := []struct {
+		name      string
+		operation string
+		handler   func(context.Context, map[string]interface{}) (*mcp_golang.ToolResponse, error)
+		args      map[string]interface{}
+		endpoint  string
+		class     string
+		sql       string
+		describe  bool
+	}{
+		{
+			name:      "sql search",
+			operation: "search",
+			handler:   registry.handleSearchTool,
+			args:      map[string]interface{}{"table": "products", "query": "laptop", "dry_run": true},
+			endpoint:  "/sql?mode=raw",
+			class:     "read",
+			sql:       "SELECT * FROM products WHERE MATCH('laptop') LIMIT 10 OPTION",
+		},
+		{
+			name:      "json search",
+			operation: "search",
+			handler:   registry.handleSearchTool,
+			args:      map[string]interface{}{"table": "products", "query": "laptop", "use_http": true, "dry_run": true},
+			endpoint:  "/search",
+			class:     "read",
+		},
+		{
+			name:      "insert",
+			operation: "insert_document",
+			handler:   registry.handleInsertDocumentTool,
+			args: map[string]interface{}{
+				"table":    "products",
+				"id":       float64(7),
+				"document": map[string]interface{}{"title": "Laptop"},
+				"dry_run":  true,
+			},
+			endpoint: "/sql?mode=raw",
+			class:    "write",
+			sql:      "INSERT INTO products (id, title) VALUES (7, 'Laptop')",
+			// Column types are read to format the values
+			describe: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := registry.withDryRun(context.Background(), tt.operation, tt.args, tt.handler)
+			require.NoError(t, err)
+
+			result := parseToolResponse(t, response)
+			require.True(t, result["success"].(bool), result["error"])
+			meta := result["meta"].(map[string]interface{})
+			assert.Equal(t, true, meta["dry_run"])
+			assert.Equal(t, tt.operation, meta["operation"])
+
+			requests := result["data"].([]interface{})
+			if tt.describe {
+				require.Len(t, requests, 2)
+				assert.Equal(t, "DESCRIBE products", requests[0].(map[string]interface{})["sql"])
+				requests = requests[1:]
+			}
+			require.Len(t, requests, 1)
+			request := requests[0].(map[string]interface{})
+			assert.Equal(t, "POST", request["method"])
+			assert.Equal(t, tt.endpoint, request["endpoint"])
+			assert.Equal(t, "http://manticore:9308"+tt.endpoint, request["url"])
+			assert.Equal(t, tt.class, request["class"])
+			if tt.sql != "" {
+				assert.Contains(t, request["sql"], tt.sql)
+			} else {
+				assert.Equal(t, "products", request["body"].(map[string]interface{})["table"])
+			}
+		})
+	}
+
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+	assert.Empty(t, mockClient.SearchCalls())
+}
+
+func TestRegistry_withDryRun_Global(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10, DryRun: true})
+
+	response, err := registry.withDryRun(context.Background(), "describe_table", map[string]interface{}{"table": "products"}, registry.handleDescribeTableTool)
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool))
+	requests := result["data"].([]interface{})
+	require.Len(t, requests, 1)
+	assert.Equal(t, "DESCRIBE products", requests[0].(map[string]interface{})["sql"])
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+
+	// Invalid arguments are reported as usual since nothing would be sent
+	response, err = registry.withDryRun(context.Background(), "search", map[string]interface{}{"table": "products; DROP TABLE x", "query": "q"}, registry.handleSearchTool)
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "invalid table")
+}
+
+func TestRegistry_withDryRun_Disabled(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{MaxResultsPerQuery: 10})
+
+	response, err := registry.withDryRun(context.Background(), "describe_table", map[string]interface{}{"table": "products"}, registry.handleDescribeTableTool)
+	require.NoError(t, err)
+	assert.True(t, parseToolResponse(t, response)["success"].(bool))
+	assert.Len(t, mockClient.ExecuteSQLCalls(), 1)
+}
+
+func TestRegistry_withDryRun_Writes(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{
+		ManticoreURL:       "http://manticore:9308",
+		MaxResultsPerQuery: 10,
+		EnableWrites:       true,
+	})
+
+	tests := []struct {
+		name      string
+		operation string
+		handler   func(context.Context, map[string]interface{}) (*mcp_golang.ToolResponse, error)
+		args      map[string]interface{}
+		sql       string
+	}{
+		{
+			name:      "multi value without schema",
+			operation: "insert_document",
+			handler:   registry.handleInsertDocumentTool,
+			args: map[string]interface{}{
+				"table":    "products",
+				"id":       float64(7),
+				"document": map[string]interface{}{"tags": []interface{}{float64(1), float64(2)}},
+				"dry_run":  true,
+			},
+			sql: "INSERT INTO products (id, tags) VALUES (7, (1,2))",
+		},
+		{
+			name:      "json value without schema",
+			operation: "insert_document",
+			handler:   registry.handleInsertDocumentTool,
+			args: map[string]interface{}{
+				"table":    "products",
+				"id":       float64(7),
+				"document": map[string]interface{}{"meta": map[string]interface{}{"color": "red"}},
+				"dry_run":  true,
+			},
+			sql: `INSERT INTO products (id, meta) VALUES (7, '{"color":"red"}')`,
+		},
+		{
+			name:      "insert documents",
+			operation: "insert_documents",
+			handler:   registry.handleInsertDocumentsTool,
+			args: map[string]interface{}{
+				"table":     "products",
+				"documents": []interface{}{map[string]interface{}{"title": "a"}, map[string]interface{}{"title": "b"}},
+				"dry_run":   true,
+			},
+			sql: "INSERT INTO products (title) VALUES ('a'), ('b'); SELECT last_insert_id()",
+		},
+		{
+			name:      "delete by query",
+			operation: "delete_by_query",
+			handler:   registry.handleDeleteByQueryTool,
+			args: map[string]interface{}{
+				"table":   "products",
+				"query":   "laptop",
+				"dry_run": true,
+			},
+			sql: "DELETE FROM products WHERE MATCH('laptop')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := registry.withDryRun(context.Background(), tt.operation, tt.args, tt.handler)
+			require.NoError(t, err)
+
+			result := parseToolResponse(t, response)
+			require.True(t, result["success"].(bool), result["error"])
+
+			requests := result["data"].([]interface{})
+			last := requests[len(requests)-1].(map[string]interface{})
+			assert.Equal(t, tt.sql, last["sql"])
+			assert.Equal(t, "write", last["class"])
+		})
+	}
+
+	// A handler failing after it made requests reports its error, not the requests
+	response, err := registry.withDryRun(context.Background(), "insert_documents", map[string]interface{}{
+		"table":     "products",
+		"documents": []interface{}{map[string]interface{}{"title": "a"}, map[string]interface{}{"price": float64(1)}},
+		"dry_run":   true,
+	}, registry.handleInsertDocumentsTool)
+	require.NoError(t, err)
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "schema is unknown")
+
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+}
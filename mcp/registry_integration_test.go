@@ -153,6 +153,9 @@ func (s *RegistryIntegrationTestSuite) TestHandleSearchTool() {
 				require.Equal(t, "search", meta["operation"])
 				require.Equal(t, "test_articles", meta["table"])
 				require.InDelta(t, 2.0, meta["count"], 0.1)
+				require.InDelta(t, 2.0, meta["total"], 0.1)
+				require.NotContains(t, meta, "has_more", "All matches fit into a single page")
+				require.Contains(t, meta, "keywords")
 			},
 		},
 		{
@@ -225,8 +228,12 @@ func (s *RegistryIntegrationTestSuite) TestHandleSearchTool() {
 			name: "search with category filter",
 			args: map[string]interface{}{
 				"table": "test_articles",
-				"query": "", // Empty query to match all for WHERE filtering
-				"where": []interface{}{"category_id = 1"},
+				"query": "", // Empty query to match all for filtering
+				"filter": map[string]interface{}{
+					"field":    "category_id",
+					"operator": "eq",
+					"value":    1,
+				},
 				"limit": 10,
 			},
 			wantErr:         false,
@@ -262,7 +269,7 @@ func (s *RegistryIntegrationTestSuite) TestHandleSearchTool() {
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			response, err := s.registry.handleSearchTool(tt.args)
+			response, err := s.registry.handleSearchTool(context.Background(), tt.args)
 
 			// Both success and error cases should return valid response without Go error
 			s.Require().NoError(err)
@@ -336,7 +343,7 @@ func (s *RegistryIntegrationTestSuite) TestHandleShowTablesTool() {
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			response, err := s.registry.handleShowTablesTool(tt.args)
+			response, err := s.registry.handleShowTablesTool(context.Background(), tt.args)
 			s.Require().NoError(err)
 			s.Require().NotNil(response)
 
@@ -416,7 +423,7 @@ func (s *RegistryIntegrationTestSuite) TestHandleDescribeTableTool() {
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			response, err := s.registry.handleDescribeTableTool(tt.args)
+			response, err := s.registry.handleDescribeTableTool(context.Background(), tt.args)
 			s.Require().NoError(err)
 			s.Require().NotNil(response)
 
@@ -464,7 +471,7 @@ func (s *RegistryIntegrationTestSuite) TestHandleInsertDocumentTool() {
 					"query": "Integration Test Article",
 					"limit": 1,
 				}
-				searchResponse, err := s.registry.handleSearchTool(searchArgs)
+				searchResponse, err := s.registry.handleSearchTool(context.Background(), searchArgs)
 				require.NoError(t, err)
 
 				searchContent := searchResponse.Content[0].TextContent.Text
@@ -492,12 +499,12 @@ func (s *RegistryIntegrationTestSuite) TestHandleInsertDocumentTool() {
 				},
 				"replace": true,
 			},
-			wantErr: false,
+			wantErr: true,
 			validate: func(t *testing.T, result map[string]interface{}) {
 				t.Helper()
-				require.True(t, result["success"].(bool))
-				meta := result["meta"].(map[string]interface{})
-				require.Equal(t, "insert_document", meta["operation"])
+				// Overwriting documents needs --enable-writes
+				require.False(t, result["success"].(bool))
+				require.Contains(t, result["error"].(string), "--enable-writes")
 			},
 		},
 		{
@@ -543,7 +550,7 @@ func (s *RegistryIntegrationTestSuite) TestHandleInsertDocumentTool() {
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			response, err := s.registry.handleInsertDocumentTool(tt.args)
+			response, err := s.registry.handleInsertDocumentTool(context.Background(), tt.args)
 			s.Require().NoError(err)
 			s.Require().NotNil(response)
 
@@ -559,7 +566,7 @@ func (s *RegistryIntegrationTestSuite) TestHandleInsertDocumentTool() {
 
 func (s *RegistryIntegrationTestSuite) TestHandleClusterStatusTool() {
 	// Basic cluster status test
-	response, err := s.registry.handleClusterStatusTool(map[string]interface{}{})
+	response, err := s.registry.handleClusterStatusTool(context.Background(), map[string]interface{}{})
 	s.Require().NoError(err)
 	s.Require().NotNil(response)
 
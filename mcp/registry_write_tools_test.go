@@ -0,0 +1,670 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport/stdio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/config"
+	"manticore-mcp-server/tools"
+)
+
+func newMockRegistry(t *testing.T, cfg *config.Config) (*Registry, *client.ManticoreClientMock) {
+	t.Helper()
+
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	return NewRegistry(tools.NewHandler(mockClient, logger), cfg, logger), mockClient
+}
+
+func parseToolResponse(t *testing.T, response *mcp_golang.ToolResponse) map[string]interface{} {
+	t.Helper()
+
+	require.NotNil(t, response)
+	require.Len(t, response.Content, 1)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(response.Content[0].TextContent.Text), &result))
+	return result
+}
+
+func TestRegistry_RegisterAll_WriteTools(t *testing.T) {
+	writeTools := []string{
+		"create_table",
+		"alter_table",
+		"migrate_schema",
+		"update_document",
+		"update_by_query",
+		"delete_document",
+		"delete_by_query",
+		"bulk_documents",
+		"create_cluster",
+		"join_cluster",
+		"alter_cluster",
+		"delete_cluster",
+		"set_cluster",
+	}
+
+	tests := []struct {
+		name         string
+		enableWrites bool
+	}{
+		{name: "writes disabled", enableWrites: false},
+		{name: "writes enabled", enableWrites: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, _ := newMockRegistry(t, &config.Config{EnableWrites: tt.enableWrites})
+			server := mcp_golang.NewServer(stdio.NewStdioServerTransport())
+
+			require.NoError(t, registry.RegisterAll(server))
+
+			for _, name := range []string{"search", "explain_search", "aggregate", "show_tables", "describe_table", "insert_document", "insert_documents", "show_cluster_status", "execute_sql"} {
+				assert.True(t, server.CheckToolRegistered(name), "tool %s should be registered", name)
+			}
+			for _, name := range writeTools {
+				assert.Equal(t, tt.enableWrites, server.CheckToolRegistered(name), "tool %s", name)
+			}
+		})
+	}
+}
+
+func TestRegistry_RegisterAll_ReadOnly(t *testing.T) {
+	registry, _ := newMockRegistry(t, &config.Config{EnableWrites: true, ReadOnly: true})
+	server := mcp_golang.NewServer(stdio.NewStdioServerTransport())
+
+	require.NoError(t, registry.RegisterAll(server))
+
+	for _, name := range []string{"search", "explain_search", "aggregate", "show_tables", "describe_table", "show_cluster_status", "execute_sql"} {
+		assert.True(t, server.CheckToolRegistered(name), "tool %s should be registered", name)
+	}
+	for _, name := range []string{"create_table", "alter_table", "migrate_schema", "insert_document", "insert_documents", "update_document", "update_by_query", "delete_document", "delete_by_query", "bulk_documents", "create_cluster", "set_cluster"} {
+		assert.False(t, server.CheckToolRegistered(name), "tool %s should be hidden in read-only mode", name)
+	}
+}
+
+func TestRegistry_handleCreateTableTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+	response, err := registry.handleCreateTableTool(context.Background(), map[string]interface{}{
+		"table": "products",
+		"columns": []interface{}{
+			map[string]interface{}{"name": "title", "type": "text", "stored": false},
+			map[string]interface{}{"name": "embedding", "type": "float_vector", "knn_type": "hnsw", "knn_dims": float64(3), "hnsw_similarity": "L2"},
+		},
+		"settings":      map[string]interface{}{"morphology": "stem_en", "min_infix_len": float64(2)},
+		"if_not_exists": true,
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	assert.Equal(t, "create_table", result["meta"].(map[string]interface{})["operation"])
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS products (title text indexed, embedding float_vector knn_type='hnsw' knn_dims='3' hnsw_similarity='L2') morphology='stem_en' min_infix_len='2'", calls[0].Query)
+
+	response, err = registry.handleCreateTableTool(context.Background(), map[string]interface{}{
+		"table":   "products",
+		"columns": []interface{}{map[string]interface{}{"name": "title", "type": "varchar"}},
+	})
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "unsupported type")
+	assert.Len(t, mockClient.ExecuteSQLCalls(), 1)
+}
+
+func TestRegistry_handleAlterTableTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+	mockClient.ExecuteSQLFunc = func(_ context.Context, query string) ([]map[string]interface{}, error) {
+		if query == "DESCRIBE products" {
+			return []map[string]interface{}{
+				{"Field": "id", "Type": "bigint", "Properties": ""},
+				{"Field": "title", "Type": "text", "Properties": "indexed stored"},
+				{"Field": "old", "Type": "uint", "Properties": ""},
+			}, nil
+		}
+		return []map[string]interface{}{}, nil
+	}
+
+	response, err := registry.handleAlterTableTool(context.Background(), map[string]interface{}{
+		"table": "products",
+		"desired": map[string]interface{}{
+			"columns": []interface{}{
+				map[string]interface{}{"name": "title", "type": "text"},
+				map[string]interface{}{"name": "price", "type": "float"},
+			},
+		},
+		"preview": true,
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	assert.Equal(t, []interface{}{
+		"ALTER TABLE products ADD COLUMN price float",
+		"ALTER TABLE products DROP COLUMN old",
+	}, result["data"].(map[string]interface{})["statements"])
+	meta := result["meta"].(map[string]interface{})
+	assert.Equal(t, "alter_table", meta["operation"])
+	assert.InDelta(t, 2, meta["count"], 0)
+
+	// Preview only reads the schema
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		assert.NotContains(t, call.Query, "ALTER")
+	}
+}
+
+func TestRegistry_handleMigrateSchemaTool(t *testing.T) {
+	dir := t.TempDir()
+	spec := "table: products\ncolumns:\n  - name: title\n    type: text\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "products.yaml"), []byte(spec), 0o600))
+
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true, MigrationsDir: dir, MigrationsTable: "mcp_migrations"})
+
+	response, err := registry.handleMigrateSchemaTool(context.Background(), map[string]interface{}{"apply": true})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	data := result["data"].(map[string]interface{})
+	assert.Equal(t, true, data["applied"])
+	assert.InDelta(t, 1, result["meta"].(map[string]interface{})["count"], 0)
+
+	var queries []string
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		queries = append(queries, call.Query)
+	}
+	require.Len(t, queries, 4)
+	assert.Equal(t, "SHOW TABLES", queries[0])
+	assert.Equal(t, "CREATE TABLE products (title text)", queries[2])
+	assert.True(t, strings.HasPrefix(queries[3], "INSERT INTO mcp_migrations "), queries[3])
+
+	// Without a migrations directory the tool is disabled
+	registry, _ = newMockRegistry(t, &config.Config{EnableWrites: true})
+	response, err = registry.handleMigrateSchemaTool(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "no migrations directory")
+}
+
+func TestRegistry_handleUpdateDocumentTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+	response, err := registry.handleUpdateDocumentTool(context.Background(), map[string]interface{}{
+		"table":    "products",
+		"id":       float64(42),
+		"document": map[string]interface{}{"price": float64(10)},
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	assert.True(t, result["success"].(bool))
+	meta := result["meta"].(map[string]interface{})
+	assert.Equal(t, "update_document", meta["operation"])
+	assert.Equal(t, "products", meta["table"])
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "DESCRIBE products", calls[0].Query)
+	assert.Equal(t, "UPDATE products SET price=10 WHERE id=42", calls[1].Query)
+}
+
+func TestRegistry_handleUpdateDocumentTool_Validation(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "missing table",
+			args:     map[string]interface{}{"id": float64(1), "document": map[string]interface{}{"a": 1}},
+			expected: "Table parameter is required",
+		},
+		{
+			name:     "missing id",
+			args:     map[string]interface{}{"table": "t", "document": map[string]interface{}{"a": 1}},
+			expected: "ID parameter is required",
+		},
+		{
+			name:     "invalid document",
+			args:     map[string]interface{}{"table": "t", "id": float64(1), "document": "a=1"},
+			expected: "Document must be a valid object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := registry.handleUpdateDocumentTool(context.Background(), tt.args)
+			require.NoError(t, err)
+
+			result := parseToolResponse(t, response)
+			assert.False(t, result["success"].(bool))
+			assert.Contains(t, result["error"].(string), tt.expected)
+		})
+	}
+
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+}
+
+func TestRegistry_handleUpdateByQueryTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, _ string) ([]client.SQLResult, error) {
+		return []client.SQLResult{{Rows: []map[string]interface{}{}, Total: 4, AffectedRows: 4}}, nil
+	}
+
+	response, err := registry.handleUpdateByQueryTool(context.Background(), map[string]interface{}{
+		"table":  "products",
+		"query":  "laptop",
+		"filter": map[string]interface{}{"field": "price", "operator": "gt", "value": float64(100)},
+		"set":    map[string]interface{}{"discount": float64(10)},
+		"strict": true,
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	assert.InDelta(t, 4, result["data"].(map[string]interface{})["affected"], 0)
+	meta := result["meta"].(map[string]interface{})
+	assert.Equal(t, "update_by_query", meta["operation"])
+	assert.InDelta(t, 4, meta["count"], 0)
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "UPDATE products SET discount=10 WHERE MATCH('laptop') AND (price > 100) OPTION strict=1", calls[0].Query)
+
+	// Neither query nor filter
+	response, err = registry.handleUpdateByQueryTool(context.Background(), map[string]interface{}{
+		"table": "products",
+		"set":   map[string]interface{}{"discount": float64(10)},
+	})
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "either query or filter parameter is required")
+	assert.Len(t, mockClient.ExecuteSQLResultsCalls(), 1)
+}
+
+func TestRegistry_handleDeleteDocumentTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+	response, err := registry.handleDeleteDocumentTool(context.Background(), map[string]interface{}{
+		"table": "products",
+		"id":    float64(7),
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	assert.True(t, result["success"].(bool))
+	assert.Equal(t, "delete_document", result["meta"].(map[string]interface{})["operation"])
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "DELETE FROM products WHERE id=7", calls[0].Query)
+
+	// A filter alone would delete every matching document without a count
+	for _, args := range []map[string]interface{}{
+		{"table": "products"},
+		{"table": "products", "filter": map[string]interface{}{"field": "id", "operator": "gt", "value": float64(0)}},
+	} {
+		response, err = registry.handleDeleteDocumentTool(context.Background(), args)
+		require.NoError(t, err)
+
+		result = parseToolResponse(t, response)
+		assert.False(t, result["success"].(bool))
+		assert.Contains(t, result["error"].(string), "id parameter is required")
+	}
+	assert.Len(t, mockClient.ExecuteSQLCalls(), 1)
+}
+
+func TestRegistry_handleDeleteByQueryTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true, DeleteConfirmThreshold: 2})
+	mockClient.ExecuteSQLFunc = func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+		return []map[string]interface{}{{"matched": float64(3)}}, nil
+	}
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, _ string) ([]client.SQLResult, error) {
+		return []client.SQLResult{{Rows: []map[string]interface{}{}, AffectedRows: 3}}, nil
+	}
+	args := map[string]interface{}{
+		"table":  "products",
+		"filter": map[string]interface{}{"field": "price", "operator": "eq", "value": float64(0)},
+	}
+
+	// Above the threshold the delete needs confirm_count
+	response, err := registry.handleDeleteByQueryTool(context.Background(), args)
+	require.NoError(t, err)
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "repeat with confirm_count 3")
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+
+	args["confirm_count"] = float64(3)
+	response, err = registry.handleDeleteByQueryTool(context.Background(), args)
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	meta := result["meta"].(map[string]interface{})
+	assert.Equal(t, "delete_by_query", meta["operation"])
+	assert.InDelta(t, 3, meta["count"], 0)
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "DELETE FROM products WHERE (price = 0)", calls[0].Query)
+}
+
+func TestRegistry_handleInsertDocumentsTool_PartialFailure(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{})
+	mockClient.ExecuteSQLFunc = func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+		return []map[string]interface{}{{"Field": "id", "Type": "bigint"}, {"Field": "title", "Type": "text"}}, nil
+	}
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, query string) ([]client.SQLResult, error) {
+		if strings.Contains(query, "'b'") {
+			return []client.SQLResult{{Error: "duplicate id '2'"}}, nil
+		}
+		return []client.SQLResult{{}, {Rows: []map[string]interface{}{{"last_insert_id()": "1"}}}}, nil
+	}
+
+	response, err := registry.handleInsertDocumentsTool(context.Background(), map[string]interface{}{
+		"table":      "products",
+		"documents":  []interface{}{map[string]interface{}{"id": float64(1), "title": "a"}, map[string]interface{}{"id": float64(2), "title": "b"}},
+		"batch_size": float64(1),
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "duplicate id '2'")
+
+	// The committed first batch is reported with the error
+	data := result["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["inserted"])
+	assert.Equal(t, []interface{}{float64(1)}, data["ids"])
+	assert.Len(t, mockClient.ExecuteSQLResultsCalls(), 2)
+}
+
+func TestRegistry_handleInsertDocumentTool_Replace(t *testing.T) {
+	args := map[string]interface{}{
+		"table":    "products",
+		"id":       float64(1),
+		"document": map[string]interface{}{"title": "a"},
+		"replace":  true,
+	}
+
+	// Replace overwrites a document and needs --enable-writes
+	registry, mockClient := newMockRegistry(t, &config.Config{})
+	response, err := registry.handleInsertDocumentTool(context.Background(), args)
+	require.NoError(t, err)
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "--enable-writes")
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+
+	registry, mockClient = newMockRegistry(t, &config.Config{EnableWrites: true})
+	response, err = registry.handleInsertDocumentTool(context.Background(), args)
+	require.NoError(t, err)
+	require.True(t, parseToolResponse(t, response)["success"].(bool))
+	calls := mockClient.ExecuteSQLCalls()
+	require.NotEmpty(t, calls)
+	assert.Equal(t, "REPLACE INTO products (id, title) VALUES (1, 'a')", calls[len(calls)-1].Query)
+}
+
+func TestRegistry_handleInsertDocumentsTool(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{})
+	mockClient.ExecuteSQLFunc = func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+		return []map[string]interface{}{{"Field": "id", "Type": "bigint"}, {"Field": "title", "Type": "text"}}, nil
+	}
+	mockClient.ExecuteSQLResultsFunc = func(_ context.Context, _ string) ([]client.SQLResult, error) {
+		return []client.SQLResult{{}, {Rows: []map[string]interface{}{{"last_insert_id()": "11,12"}}}}, nil
+	}
+
+	response, err := registry.handleInsertDocumentsTool(context.Background(), map[string]interface{}{
+		"table":     "products",
+		"documents": []interface{}{map[string]interface{}{"title": "a"}, map[string]interface{}{"title": "b"}},
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	assert.Equal(t, []interface{}{float64(11), float64(12)}, result["data"].(map[string]interface{})["ids"])
+	assert.Equal(t, "insert_documents", result["meta"].(map[string]interface{})["operation"])
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "INSERT INTO products (title) VALUES ('a'), ('b'); SELECT last_insert_id()", calls[0].Query)
+
+	response, err = registry.handleInsertDocumentsTool(context.Background(), map[string]interface{}{"table": "products"})
+	require.NoError(t, err)
+	assert.False(t, parseToolResponse(t, response)["success"].(bool))
+
+	// Replace overwrites documents and needs --enable-writes
+	response, err = registry.handleInsertDocumentsTool(context.Background(), map[string]interface{}{
+		"table":     "products",
+		"documents": []interface{}{map[string]interface{}{"id": float64(1), "title": "a"}},
+		"replace":   true,
+	})
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"], "--enable-writes")
+	assert.Len(t, mockClient.ExecuteSQLResultsCalls(), 1)
+}
+
+func TestRegistry_handleBulkDocumentsTool(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs.jsonl"), []byte("{\"id\": 1, \"title\": \"a\"}\n{\"id\": 2, \"title\": \"b\"}\n"), 0o600))
+	outside := filepath.Join(t.TempDir(), "secret.jsonl")
+	require.NoError(t, os.WriteFile(outside, []byte("{\"title\": \"x\"}\n"), 0o600))
+
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true, BulkBatchSize: 1, BulkFileDir: dir})
+	mockClient.BulkFunc = func(_ context.Context, actions []map[string]interface{}) (*client.BulkResponse, error) {
+		return &client.BulkResponse{CurrentLine: len(actions) + 1}, nil
+	}
+
+	response, err := registry.handleBulkDocumentsTool(context.Background(), map[string]interface{}{
+		"table": "products",
+		"items": []interface{}{
+			map[string]interface{}{"id": float64(1), "document": map[string]interface{}{"title": "a"}},
+			map[string]interface{}{"action": "delete", "id": float64(2)},
+		},
+	})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	data := result["data"].(map[string]interface{})
+	assert.InDelta(t, 2, data["succeeded"], 0)
+	assert.InDelta(t, 2, data["batches"], 0)
+	assert.Equal(t, "bulk_documents", result["meta"].(map[string]interface{})["operation"])
+	assert.Len(t, mockClient.BulkCalls(), 2)
+
+	response, err = registry.handleBulkDocumentsTool(context.Background(), map[string]interface{}{
+		"table":      "products",
+		"file":       "docs.jsonl",
+		"batch_size": float64(10),
+	})
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	require.True(t, result["success"].(bool), result["error"])
+	calls := mockClient.BulkCalls()
+	require.Len(t, calls, 3)
+	assert.Len(t, calls[2].Actions, 2)
+
+	for _, file := range []string{outside, "../" + filepath.Base(filepath.Dir(outside)) + "/secret.jsonl", "missing.jsonl"} {
+		response, err = registry.handleBulkDocumentsTool(context.Background(), map[string]interface{}{"table": "products", "file": file})
+		require.NoError(t, err)
+		result = parseToolResponse(t, response)
+		assert.False(t, result["success"].(bool), file)
+		assert.Contains(t, result["error"], "Invalid bulk file")
+	}
+
+	// File input is disabled without a bulk file directory
+	registry, _ = newMockRegistry(t, &config.Config{EnableWrites: true})
+	response, err = registry.handleBulkDocumentsTool(context.Background(), map[string]interface{}{"table": "products", "file": "docs.jsonl"})
+	require.NoError(t, err)
+	assert.Contains(t, parseToolResponse(t, response)["error"], "--bulk-file-dir")
+	assert.Len(t, mockClient.BulkCalls(), 3)
+}
+
+func TestRegistry_handleDocumentTools_Filter(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+	filter := map[string]interface{}{"field": "category", "operator": "eq", "value": "x') OR 1=1 --"}
+
+	response, err := registry.handleUpdateDocumentTool(context.Background(), map[string]interface{}{
+		"table":    "products",
+		"id":       float64(42),
+		"document": map[string]interface{}{"price": float64(10)},
+		"filter":   filter,
+	})
+	require.NoError(t, err)
+	assert.True(t, parseToolResponse(t, response)["success"].(bool))
+
+	response, err = registry.handleDeleteDocumentTool(context.Background(), map[string]interface{}{
+		"table":  "products",
+		"id":     float64(42),
+		"filter": filter,
+	})
+	require.NoError(t, err)
+	assert.True(t, parseToolResponse(t, response)["success"].(bool))
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, `UPDATE products SET price=10 WHERE id=42 AND (category = 'x\') OR 1=1 --')`, calls[1].Query)
+	assert.Equal(t, `DELETE FROM products WHERE id=42 AND (category = 'x\') OR 1=1 --')`, calls[2].Query)
+}
+
+func TestRegistry_handleDocumentTools_RawCondition(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+	response, err := registry.handleDeleteDocumentTool(context.Background(), map[string]interface{}{
+		"table":     "products",
+		"condition": "1=1",
+	})
+	require.NoError(t, err)
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"].(string), "--allow-raw-where")
+
+	response, err = registry.handleUpdateDocumentTool(context.Background(), map[string]interface{}{
+		"table":     "products",
+		"id":        float64(1),
+		"document":  map[string]interface{}{"price": float64(10)},
+		"condition": "1=1",
+	})
+	require.NoError(t, err)
+	assert.False(t, parseToolResponse(t, response)["success"].(bool))
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+
+	// Allowed conditions still may not stack statements
+	registry, mockClient = newMockRegistry(t, &config.Config{EnableWrites: true, AllowRawWhere: true})
+	response, err = registry.handleDeleteDocumentTool(context.Background(), map[string]interface{}{
+		"table":     "products",
+		"id":        float64(1),
+		"condition": "1=1); DROP TABLE products; SELECT (1",
+	})
+	require.NoError(t, err)
+	result = parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"].(string), "single statement")
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+}
+
+func TestRegistry_handleClusterAdminTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		handle    func(r *Registry, ctx context.Context, args map[string]interface{}) (*mcp_golang.ToolResponse, error)
+		args      map[string]interface{}
+		operation string
+		sql       string
+	}{
+		{
+			name:      "create cluster",
+			handle:    (*Registry).handleCreateClusterTool,
+			args:      map[string]interface{}{"name": "posts", "nodes": []interface{}{"10.0.0.1:9312", "10.0.0.2:9312"}},
+			operation: "create_cluster",
+			sql:       "CREATE CLUSTER posts '10.0.0.1:9312,10.0.0.2:9312' AS nodes",
+		},
+		{
+			name:      "join cluster",
+			handle:    (*Registry).handleJoinClusterTool,
+			args:      map[string]interface{}{"name": "posts", "at": "10.0.0.1:9312"},
+			operation: "join_cluster",
+			sql:       "JOIN CLUSTER posts AT '10.0.0.1:9312'",
+		},
+		{
+			name:      "alter cluster",
+			handle:    (*Registry).handleAlterClusterTool,
+			args:      map[string]interface{}{"name": "posts", "operation": "add", "table": "articles"},
+			operation: "alter_cluster",
+			sql:       "ALTER CLUSTER posts ADD articles",
+		},
+		{
+			name:      "delete cluster",
+			handle:    (*Registry).handleDeleteClusterTool,
+			args:      map[string]interface{}{"name": "posts"},
+			operation: "delete_cluster",
+			sql:       "DELETE CLUSTER posts",
+		},
+		{
+			name:      "set cluster with numeric value",
+			handle:    (*Registry).handleSetClusterTool,
+			args:      map[string]interface{}{"name": "posts", "variable": "pc.bootstrap", "value": float64(1), "global": true},
+			operation: "set_cluster",
+			sql:       "SET CLUSTER posts GLOBAL 'pc.bootstrap' = 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+			response, err := tt.handle(registry, context.Background(), tt.args)
+			require.NoError(t, err)
+
+			result := parseToolResponse(t, response)
+			assert.True(t, result["success"].(bool))
+
+			meta := result["meta"].(map[string]interface{})
+			assert.Equal(t, tt.operation, meta["operation"])
+			assert.Equal(t, "posts", meta["cluster"])
+
+			calls := mockClient.ExecuteSQLCalls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, tt.sql, calls[0].Query)
+		})
+	}
+}
+
+func TestRegistry_handleClusterAdminTools_MissingName(t *testing.T) {
+	registry, mockClient := newMockRegistry(t, &config.Config{EnableWrites: true})
+
+	response, err := registry.handleCreateClusterTool(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+
+	result := parseToolResponse(t, response)
+	assert.False(t, result["success"].(bool))
+	assert.Contains(t, result["error"].(string), "cluster name is required")
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+}
@@ -0,0 +1,283 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+
+	"manticore-mcp-server/tools/aggregate"
+	"manticore-mcp-server/tools/clusters"
+	"manticore-mcp-server/tools/documents"
+	"manticore-mcp-server/tools/migrations"
+	"manticore-mcp-server/tools/rawsql"
+	"manticore-mcp-server/tools/search"
+	"manticore-mcp-server/tools/tables"
+
+	"github.com/invopop/jsonschema"
+)
+
+// Tool argument types. Arguments are decoded into plain maps so the handlers keep
+// their lenient parsing, while the advertised input schema is generated from the
+// typed argument structs of the tools packages.
+type (
+	searchToolArgs            map[string]interface{}
+	explainSearchToolArgs     map[string]interface{}
+	aggregateToolArgs         map[string]interface{}
+	showTablesToolArgs        map[string]interface{}
+	describeTableToolArgs     map[string]interface{}
+	createTableToolArgs       map[string]interface{}
+	alterTableToolArgs        map[string]interface{}
+	migrateSchemaToolArgs     map[string]interface{}
+	insertDocumentToolArgs    map[string]interface{}
+	insertDocumentsToolArgs   map[string]interface{}
+	updateDocumentToolArgs    map[string]interface{}
+	updateByQueryToolArgs     map[string]interface{}
+	deleteDocumentToolArgs    map[string]interface{}
+	deleteByQueryToolArgs     map[string]interface{}
+	bulkDocumentsToolArgs     map[string]interface{}
+	showClusterStatusToolArgs map[string]interface{}
+	createClusterToolArgs     map[string]interface{}
+	joinClusterToolArgs       map[string]interface{}
+	alterClusterToolArgs      map[string]interface{}
+	deleteClusterToolArgs     map[string]interface{}
+	setClusterToolArgs        map[string]interface{}
+	executeSQLToolArgs        map[string]interface{}
+)
+
+// JSONSchema methods are picked up by the jsonschema reflector used by mcp-golang
+func (searchToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(search.Args{})
+}
+
+func (explainSearchToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(search.Args{})
+}
+
+func (aggregateToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(aggregate.Args{})
+}
+
+func (showTablesToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(tables.ShowTablesArgs{})
+}
+
+func (describeTableToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(tables.DescribeTableArgs{})
+}
+
+func (createTableToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(tables.CreateTableArgs{})
+}
+
+func (alterTableToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(tables.AlterTableArgs{})
+}
+
+func (migrateSchemaToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(migrations.MigrateArgs{})
+}
+
+func (insertDocumentToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.InsertDocumentArgs{})
+}
+
+func (insertDocumentsToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.InsertDocumentsArgs{})
+}
+
+func (updateDocumentToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.UpdateDocumentArgs{})
+}
+
+func (updateByQueryToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.UpdateByQueryArgs{})
+}
+
+func (deleteDocumentToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.DeleteDocumentArgs{})
+}
+
+func (deleteByQueryToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.DeleteByQueryArgs{})
+}
+
+func (bulkDocumentsToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(documents.BulkArgs{})
+}
+
+func (showClusterStatusToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(clusters.ShowClusterStatusArgs{})
+}
+
+func (createClusterToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(clusters.CreateClusterArgs{})
+}
+
+func (joinClusterToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(clusters.JoinClusterArgs{})
+}
+
+func (alterClusterToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(clusters.AlterClusterArgs{})
+}
+
+func (deleteClusterToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(clusters.DeleteClusterArgs{})
+}
+
+func (setClusterToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(clusters.SetClusterArgs{})
+}
+
+func (executeSQLToolArgs) JSONSchema() *jsonschema.Schema {
+	return toolSchema(rawsql.Args{})
+}
+
+// clauseDataTypes maps bool_query clause types to the structs describing their data
+var clauseDataTypes = []struct {
+	clauseType string
+	data       any
+}{
+	{"match", search.MatchClause{}},
+	{"range", search.RangeClause{}},
+	{"equals", search.EqualsClause{}},
+	{"in", search.InClause{}},
+	{"geo_distance", search.GeoDistanceClause{}},
+	{"query_string", search.QueryStringClause{}},
+}
+
+var (
+	queryClauseType = reflect.TypeOf(search.QueryClause{})
+	filterType      = reflect.TypeOf(search.Filter{})
+)
+
+// toolSchema generates an input schema for a tool from its typed argument struct
+func toolSchema(args any) *jsonschema.Schema {
+	reflector := newSchemaReflector()
+	reflector.Mapper = func(t reflect.Type) *jsonschema.Schema {
+		switch t {
+		case queryClauseType:
+			return queryClauseSchema()
+		case filterType:
+			return filterSchema()
+		}
+		return nil
+	}
+
+	schema := reflectSchema(reflector, reflect.TypeOf(args))
+
+	// Every tool accepts a per-call timeout, search maps it to max_query_time as well
+	if _, ok := schema.Properties.Get("timeout_ms"); !ok {
+		schema.Properties.Set("timeout_ms", &jsonschema.Schema{
+			Type:        "integer",
+			Description: "Abort the call after this many milliseconds (0 = server default)",
+		})
+	}
+
+	// Every tool can return the requests it would send instead of sending them
+	schema.Properties.Set("dry_run", &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Return the SQL or JSON requests with endpoint and statement classification without contacting Manticore",
+	})
+
+	return schema
+}
+
+// queryClauseSchema describes a bool_query clause, listing the data shape of every clause type
+func queryClauseSchema() *jsonschema.Schema {
+	schema := reflectSchema(newSchemaReflector(), queryClauseType)
+	schema.Version = ""
+
+	data, _ := schema.Properties.Get("data")
+	for _, clause := range clauseDataTypes {
+		variant := reflectSchema(newSchemaReflector(), reflect.TypeOf(clause.data))
+		variant.Title = clause.clauseType
+		variant.Version = ""
+		data.AnyOf = append(data.AnyOf, variant)
+	}
+
+	// Nested bool clauses are not expanded to avoid infinite recursion
+	data.AnyOf = append(data.AnyOf,
+		&jsonschema.Schema{Title: "match_all", Type: "object"},
+		&jsonschema.Schema{
+			Title:       "bool",
+			Type:        "object",
+			Description: "Nested bool query with must, should and must_not clause lists",
+		},
+	)
+
+	return schema
+}
+
+// filterSchema describes a structured filter. Filters nested in and/or/not are not expanded
+// to avoid infinite recursion.
+func filterSchema() *jsonschema.Schema {
+	nested := 0
+	reflector := newSchemaReflector()
+	reflector.Mapper = func(t reflect.Type) *jsonschema.Schema {
+		if t != filterType {
+			return nil
+		}
+		if nested++; nested == 1 {
+			return nil
+		}
+		return &jsonschema.Schema{
+			Type:        "object",
+			Description: "Nested filter with the same structure",
+		}
+	}
+
+	schema := reflectSchema(reflector, filterType)
+	schema.Version = ""
+	return schema
+}
+
+// newSchemaReflector returns a reflector configured like the one used by mcp-golang
+func newSchemaReflector() *jsonschema.Reflector {
+	return &jsonschema.Reflector{
+		Anonymous:                  true,
+		AllowAdditionalProperties:  true,
+		RequiredFromJSONSchemaTags: true,
+		DoNotReference:             true,
+		ExpandedStruct:             true,
+	}
+}
+
+// reflectSchema reflects a struct type and fills property descriptions from description tags
+func reflectSchema(reflector *jsonschema.Reflector, t reflect.Type) *jsonschema.Schema {
+	schema := reflector.ReflectFromType(t)
+	applyDescriptions(schema, t)
+	return schema
+}
+
+// applyDescriptions copies description tags of struct fields into matching schema properties
+func applyDescriptions(schema *jsonschema.Schema, t reflect.Type) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		if t.Kind() == reflect.Slice {
+			if schema.Items == nil {
+				return
+			}
+			schema = schema.Items
+		}
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || schema.Properties == nil {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property, ok := schema.Properties.Get(name)
+		if !ok {
+			continue
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			property.Description = description
+		}
+		applyDescriptions(property, field.Type)
+	}
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/invopop/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reflectToolArgs reflects a tool argument type the same way mcp-golang does on registration
+func reflectToolArgs(t *testing.T, args any) *jsonschema.Schema {
+	t.Helper()
+
+	schema := newSchemaReflector().ReflectFromType(reflect.TypeOf(args))
+	require.NotNil(t, schema)
+	require.NotNil(t, schema.Properties)
+	return schema
+}
+
+func getProperty(t *testing.T, schema *jsonschema.Schema, name string) *jsonschema.Schema {
+	t.Helper()
+
+	property, ok := schema.Properties.Get(name)
+	require.True(t, ok, "property %q not found", name)
+	return property
+}
+
+func TestToolSchema_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     any
+		required []string
+	}{
+		{name: "search", args: searchToolArgs{}, required: []string{"table"}},
+		{name: "explain_search", args: explainSearchToolArgs{}, required: []string{"table"}},
+		{name: "aggregate", args: aggregateToolArgs{}, required: []string{"table"}},
+		{name: "show_tables", args: showTablesToolArgs{}, required: nil},
+		{name: "describe_table", args: describeTableToolArgs{}, required: []string{"table"}},
+		{name: "create_table", args: createTableToolArgs{}, required: []string{"table", "columns"}},
+		{name: "alter_table", args: alterTableToolArgs{}, required: []string{"table"}},
+		{name: "migrate_schema", args: migrateSchemaToolArgs{}, required: nil},
+		{name: "insert_document", args: insertDocumentToolArgs{}, required: []string{"table", "document"}},
+		{name: "insert_documents", args: insertDocumentsToolArgs{}, required: []string{"table", "documents"}},
+		{name: "update_document", args: updateDocumentToolArgs{}, required: []string{"table", "id", "document"}},
+		{name: "update_by_query", args: updateByQueryToolArgs{}, required: []string{"table", "set"}},
+		{name: "delete_document", args: deleteDocumentToolArgs{}, required: []string{"table", "id"}},
+		{name: "delete_by_query", args: deleteByQueryToolArgs{}, required: []string{"table"}},
+		{name: "bulk_documents", args: bulkDocumentsToolArgs{}, required: nil},
+		{name: "show_cluster_status", args: showClusterStatusToolArgs{}, required: nil},
+		{name: "create_cluster", args: createClusterToolArgs{}, required: []string{"name"}},
+		{name: "join_cluster", args: joinClusterToolArgs{}, required: []string{"name", "at"}},
+		{name: "alter_cluster", args: alterClusterToolArgs{}, required: []string{"name", "operation"}},
+		{name: "delete_cluster", args: deleteClusterToolArgs{}, required: []string{"name"}},
+		{name: "set_cluster", args: setClusterToolArgs{}, required: []string{"name", "variable", "value"}},
+		{name: "execute_sql", args: executeSQLToolArgs{}, required: []string{"query"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := reflectToolArgs(t, tt.args)
+			assert.Equal(t, "object", schema.Type)
+			assert.ElementsMatch(t, tt.required, schema.Required)
+			assert.Equal(t, "integer", getProperty(t, schema, "timeout_ms").Type)
+			assert.Equal(t, "boolean", getProperty(t, schema, "dry_run").Type)
+		})
+	}
+}
+
+func TestToolSchema_SearchProperties(t *testing.T) {
+	schema := reflectToolArgs(t, searchToolArgs{})
+
+	table := getProperty(t, schema, "table")
+	assert.Equal(t, "string", table.Type)
+	assert.Equal(t, "Table name to search in", table.Description)
+
+	ranker := getProperty(t, schema, "ranker")
+	assert.Contains(t, ranker.Enum, "proximity_bm25")
+	assert.Contains(t, ranker.Enum, "sph04")
+
+	matchMode := getProperty(t, schema, "match_mode")
+	assert.Equal(t, []any{"all", "any", "phrase", "boolean", "extended"}, matchMode.Enum)
+
+	highlight := getProperty(t, schema, "highlight")
+	assert.Equal(t, "object", highlight.Type)
+	assert.Equal(t, "Highlighting options", highlight.Description)
+	assert.Equal(t, "Words around match", getProperty(t, highlight, "around").Description)
+
+	orderBy := getProperty(t, schema, "order_by")
+	assert.Equal(t, "array", orderBy.Type)
+	require.NotNil(t, orderBy.Items)
+	assert.Equal(t, "string", orderBy.Items.Type)
+
+	assert.Equal(t, "object", getProperty(t, schema, "fuzzy").Type)
+
+	filter := getProperty(t, schema, "filter")
+	assert.Contains(t, getProperty(t, filter, "operator").Enum, "between")
+	assert.Equal(t, []any{"any", "all"}, getProperty(t, filter, "quantifier").Enum)
+	assert.Equal(t, "object", getProperty(t, filter, "not").Type)
+	require.NotNil(t, getProperty(t, filter, "and").Items)
+}
+
+func TestToolSchema_BoolQueryClauses(t *testing.T) {
+	schema := reflectToolArgs(t, searchToolArgs{})
+	boolQuery := getProperty(t, schema, "bool_query")
+
+	for _, list := range []string{"must", "should", "must_not"} {
+		t.Run(list, func(t *testing.T) {
+			clauses := getProperty(t, boolQuery, list)
+			require.Equal(t, "array", clauses.Type)
+			require.NotNil(t, clauses.Items)
+
+			clause := clauses.Items
+			assert.Equal(t, []string{"type"}, clause.Required)
+			assert.Contains(t, getProperty(t, clause, "type").Enum, "geo_distance")
+
+			data := getProperty(t, clause, "data")
+			titles := make([]string, 0, len(data.AnyOf))
+			for _, variant := range data.AnyOf {
+				titles = append(titles, variant.Title)
+			}
+			assert.Equal(t, []string{"match", "range", "equals", "in", "geo_distance", "query_string", "match_all", "bool"}, titles)
+
+			match := data.AnyOf[0]
+			assert.Equal(t, []string{"field", "query"}, match.Required)
+			assert.Equal(t, []any{"and", "or"}, getProperty(t, match, "operator").Enum)
+		})
+	}
+}
+
+func TestToolSchema_SetClusterValueAcceptsScalars(t *testing.T) {
+	schema := reflectToolArgs(t, setClusterToolArgs{})
+
+	value := getProperty(t, schema, "value")
+	types := make([]string, 0, len(value.OneOf))
+	for _, variant := range value.OneOf {
+		types = append(types, variant.Type)
+	}
+	assert.Equal(t, []string{"string", "number", "boolean"}, types)
+}
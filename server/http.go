@@ -0,0 +1,337 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+const (
+	// sessionHeader carries the session id of the streamable HTTP transport
+	sessionHeader = "Mcp-Session-Id"
+	// maxMessageSize limits the size of a single client message
+	maxMessageSize = 4 << 20
+)
+
+// httpHandler serves MCP sessions over streamable HTTP and legacy SSE
+type httpHandler struct {
+	server   *Server
+	sessions *sessionStore
+}
+
+// newHTTPHandler creates the HTTP routes of the selected transport
+func newHTTPHandler(s *Server, sessions *sessionStore) http.Handler {
+	h := &httpHandler{
+		server:   s,
+		sessions: sessions,
+	}
+
+	mux := http.NewServeMux()
+	switch s.config.Transport {
+	case TransportSSE:
+		mux.HandleFunc("/sse", h.handleSSE)
+		mux.HandleFunc("/messages", h.handleSSEMessage)
+	default:
+		mux.HandleFunc("/mcp", h.handleStreamable)
+	}
+	return h.protect(mux)
+}
+
+// protect rejects requests from foreign browser origins (DNS rebinding) and, when a token is
+// configured, requests without the matching bearer token
+func (h *httpHandler) protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !h.originAllowed(origin) {
+			h.server.logger.Warn("Rejected request from foreign origin", "origin", origin)
+			http.Error(w, "Origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		if token := h.server.config.AuthToken; token != "" {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// originAllowed reports whether a browser origin is local or explicitly allowed
+func (h *httpHandler) originAllowed(origin string) bool {
+	for _, allowed := range h.server.config.AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := parsed.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handleStreamable implements the streamable HTTP transport endpoint
+func (h *httpHandler) handleStreamable(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleStreamablePost(w, r)
+	case http.MethodDelete:
+		if !h.sessions.remove(r.Header.Get(sessionHeader)) {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleStreamablePost passes a client message to its session and writes the response
+func (h *httpHandler) handleStreamablePost(w http.ResponseWriter, r *http.Request) {
+	message, err := readMessage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var sess *session
+	if sessionID := r.Header.Get(sessionHeader); sessionID != "" {
+		var ok bool
+		if sess, ok = h.sessions.get(sessionID); !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+	} else {
+		if message.Type != transport.BaseMessageTypeJSONRPCRequestType || message.JsonRpcRequest.Method != "initialize" {
+			http.Error(w, "Missing "+sessionHeader+" header", http.StatusBadRequest)
+			return
+		}
+		if sess, err = h.openSession(false); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set(sessionHeader, sess.id)
+
+	if message.Type != transport.BaseMessageTypeJSONRPCRequestType {
+		sess.transport.dispatch(r.Context(), message)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	id := message.JsonRpcRequest.Id
+	waiter, err := sess.transport.await(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	defer sess.transport.forget(id)
+
+	// The request context is canceled when the client disconnects, which also cancels the tool call
+	sess.transport.dispatch(r.Context(), message)
+
+	select {
+	case response := <-waiter:
+		writeJSON(w, response)
+	case <-r.Context().Done():
+	case <-sess.transport.ctx.Done():
+		http.Error(w, "Session closed", http.StatusServiceUnavailable)
+	}
+}
+
+// handleSSE opens a legacy SSE session and streams server messages to the client
+func (h *httpHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sess, err := h.openSession(true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer h.sessions.remove(sess.id)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "event: endpoint\ndata: /messages?sessionId=%s\n\n", sess.id)
+	flusher.Flush()
+
+	for {
+		select {
+		case message := <-sess.transport.events:
+			data, err := json.Marshal(message)
+			if err != nil {
+				h.server.logger.Error("Failed to encode SSE message", "session", sess.id, "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+			sess.touch()
+		case <-r.Context().Done():
+			return
+		case <-sess.transport.ctx.Done():
+			return
+		}
+	}
+}
+
+// handleSSEMessage accepts a client message for a legacy SSE session
+func (h *httpHandler) handleSSEMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sess, ok := h.sessions.get(r.URL.Query().Get("sessionId"))
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := readMessage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Responses are streamed over SSE after this request returns, so they must outlive it
+	sess.transport.dispatch(sess.transport.ctx, message)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// openSession creates a session served by its own MCP server
+func (h *httpHandler) openSession(withEvents bool) (*session, error) {
+	t := newSessionTransport(withEvents)
+	if err := h.server.serve(t); err != nil {
+		_ = t.Close()
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	sess, err := h.sessions.add(t)
+	if err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+
+	h.server.logger.Debug("MCP session opened", "session", sess.id)
+	return sess, nil
+}
+
+// runHTTP serves the HTTP based transports until ctx is canceled
+func (s *Server) runHTTP(ctx context.Context) error {
+	sessions := newSessionStore()
+
+	httpServer := &http.Server{
+		Addr:              s.config.ListenAddr,
+		Handler:           newHTTPHandler(s, sessions),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Open SSE streams would otherwise block the graceful shutdown
+	httpServer.RegisterOnShutdown(sessions.closeAll)
+
+	go s.expireSessions(ctx, sessions)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		s.logger.Info("Listening for MCP clients", "transport", s.config.Transport, "addr", s.config.ListenAddr)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		sessions.closeAll()
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutting down MCP server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("HTTP server shutdown failed: %w", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+	return nil
+}
+
+// expireSessions periodically closes sessions idle for longer than the configured timeout
+func (s *Server) expireSessions(ctx context.Context, sessions *sessionStore) {
+	if s.config.SessionIdleTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.SessionIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := sessions.expire(time.Now().Add(-s.config.SessionIdleTimeout)); n > 0 {
+				s.logger.Debug("Expired idle MCP sessions", "count", n)
+			}
+		}
+	}
+}
+
+// readMessage reads a single JSON-RPC message from the request body
+func readMessage(r *http.Request) (*transport.BaseJsonRpcMessage, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) > maxMessageSize {
+		return nil, fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+	}
+	return decodeMessage(body)
+}
+
+// writeJSON writes a JSON-RPC message as the response body
+func writeJSON(w http.ResponseWriter, message *transport.BaseJsonRpcMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
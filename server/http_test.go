@@ -0,0 +1,316 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/config"
+	"manticore-mcp-server/tools"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+
+func newTestServer(t *testing.T, transport string) (*httptest.Server, *sessionStore) {
+	t.Helper()
+
+	return newTestServerWithConfig(t, &config.Config{Transport: transport, MaxResultsPerQuery: 10})
+}
+
+func newTestServerWithConfig(t *testing.T, cfg *config.Config) (*httptest.Server, *sessionStore) {
+	t.Helper()
+
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{{"Table": "products", "Type": "rt"}}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	sessions := newSessionStore()
+	server := httptest.NewServer(newHTTPHandler(New(tools.NewHandler(mockClient, logger), cfg, logger), sessions))
+	t.Cleanup(func() {
+		sessions.closeAll()
+		server.Close()
+	})
+
+	return server, sessions
+}
+
+func postMessage(t *testing.T, url, sessionID, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decodeResponse(t *testing.T, resp *http.Response) map[string]interface{} {
+	t.Helper()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+func TestStreamableHTTP_Session(t *testing.T) {
+	server, sessions := newTestServer(t, TransportHTTP)
+	endpoint := server.URL + "/mcp"
+
+	resp := postMessage(t, endpoint, "", initializeRequest)
+	sessionID := resp.Header.Get(sessionHeader)
+	require.NotEmpty(t, sessionID)
+
+	initResult := decodeResponse(t, resp)
+	assert.InDelta(t, 1, initResult["id"], 0)
+	assert.Contains(t, initResult, "result")
+
+	resp = postMessage(t, endpoint, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	toolsResult := decodeResponse(t, postMessage(t, endpoint, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list","params":{}}`))
+	toolList := toolsResult["result"].(map[string]interface{})["tools"].([]interface{})
+	names := make([]string, 0, len(toolList))
+	for _, tool := range toolList {
+		names = append(names, tool.(map[string]interface{})["name"].(string))
+	}
+	assert.Contains(t, names, "search")
+	assert.Contains(t, names, "show_tables")
+
+	callResult := decodeResponse(t, postMessage(t, endpoint, sessionID, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"show_tables","arguments":{}}}`))
+	content := callResult["result"].(map[string]interface{})["content"].([]interface{})
+	require.Len(t, content, 1)
+	assert.Contains(t, content[0].(map[string]interface{})["text"], "products")
+
+	// Sessions are isolated from each other
+	resp = postMessage(t, endpoint, "", initializeRequest)
+	otherID := resp.Header.Get(sessionHeader)
+	resp.Body.Close()
+	assert.NotEqual(t, sessionID, otherID)
+
+	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	require.NoError(t, err)
+	req.Header.Set(sessionHeader, sessionID)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	_, exists := sessions.get(sessionID)
+	assert.False(t, exists)
+	_, exists = sessions.get(otherID)
+	assert.True(t, exists)
+}
+
+func TestStreamableHTTP_Errors(t *testing.T) {
+	server, _ := newTestServer(t, TransportHTTP)
+	endpoint := server.URL + "/mcp"
+
+	tests := []struct {
+		name      string
+		sessionID string
+		body      string
+		status    int
+	}{
+		{
+			name:   "missing session",
+			body:   `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:      "unknown session",
+			sessionID: "does-not-exist",
+			body:      `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}`,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:   "malformed message",
+			body:   `{"jsonrpc":`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postMessage(t, endpoint, tt.sessionID, tt.body)
+			resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	resp, err := http.Get(endpoint)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestStreamableHTTP_Origin(t *testing.T) {
+	server, _ := newTestServerWithConfig(t, &config.Config{
+		Transport:          TransportHTTP,
+		MaxResultsPerQuery: 10,
+		AllowedOrigins:     []string{"https://agents.example.com"},
+	})
+	endpoint := server.URL + "/mcp"
+
+	tests := []struct {
+		origin string
+		status int
+	}{
+		{origin: "", status: http.StatusOK},
+		{origin: "http://localhost:3000", status: http.StatusOK},
+		{origin: "http://127.0.0.1:8080", status: http.StatusOK},
+		{origin: "https://agents.example.com", status: http.StatusOK},
+		{origin: "http://attacker.example.com", status: http.StatusForbidden},
+		{origin: "null", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(initializeRequest))
+			require.NoError(t, err)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestStreamableHTTP_AuthToken(t *testing.T) {
+	server, _ := newTestServerWithConfig(t, &config.Config{
+		Transport:          TransportHTTP,
+		MaxResultsPerQuery: 10,
+		AuthToken:          "secret",
+	})
+	endpoint := server.URL + "/mcp"
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{name: "missing token", status: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer guess", status: http.StatusUnauthorized},
+		{name: "wrong scheme", authorization: "Basic secret", status: http.StatusUnauthorized},
+		{name: "valid token", authorization: "Bearer secret", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(initializeRequest))
+			require.NoError(t, err)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestSSE_Session(t *testing.T) {
+	server, _ := newTestServer(t, TransportSSE)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/sse", nil)
+	require.NoError(t, err)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(stream.Body)
+	readEvent := func() (string, string) {
+		var event, data string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "":
+				return event, data
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+
+	event, endpoint := readEvent()
+	require.Equal(t, "endpoint", event)
+	require.True(t, strings.HasPrefix(endpoint, "/messages?sessionId="))
+
+	resp := postMessage(t, server.URL+endpoint, "", initializeRequest)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	event, data := readEvent()
+	require.Equal(t, "message", event)
+
+	var message map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(data), &message))
+	assert.InDelta(t, 1, message["id"], 0)
+	assert.Contains(t, message, "result")
+
+	resp = postMessage(t, server.URL+"/messages?sessionId=unknown", "", initializeRequest)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSessionStore_Expire(t *testing.T) {
+	sessions := newSessionStore()
+
+	idle, err := sessions.add(newSessionTransport(false))
+	require.NoError(t, err)
+	active, err := sessions.add(newSessionTransport(false))
+	require.NoError(t, err)
+
+	idle.mu.Lock()
+	idle.lastSeen = time.Now().Add(-time.Hour)
+	idle.mu.Unlock()
+
+	assert.Equal(t, 1, sessions.expire(time.Now().Add(-time.Minute)))
+
+	_, exists := sessions.get(idle.id)
+	assert.False(t, exists)
+	assert.Error(t, idle.transport.ctx.Err(), "expired session transport should be closed")
+
+	_, exists = sessions.get(active.id)
+	assert.True(t, exists)
+}
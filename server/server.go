@@ -1,15 +1,29 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"manticore-mcp-server/config"
 	"manticore-mcp-server/mcp"
 	"manticore-mcp-server/tools"
 
 	mcp_golang "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport"
 	"github.com/metoro-io/mcp-golang/transport/stdio"
 )
 
+// Supported MCP transports
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+	TransportSSE   = "sse"
+)
+
 // Server handles MCP protocol communication
 type Server struct {
 	toolHandler *tools.Handler
@@ -26,15 +40,40 @@ func New(toolHandler *tools.Handler, cfg *config.Config, logger *slog.Logger) *S
 	}
 }
 
-// Run starts the MCP server
+// Run starts the MCP server and blocks until SIGINT or SIGTERM is received
 func (s *Server) Run() error {
-	s.logger.Info("Starting Manticore Search MCP Server...")
+	s.logger.Info("Starting Manticore Search MCP Server...", "transport", s.config.Transport)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch s.config.Transport {
+	case TransportHTTP, TransportSSE:
+		return s.runHTTP(ctx)
+	case TransportStdio, "":
+		return s.runStdio(ctx)
+	default:
+		return fmt.Errorf("unsupported transport: %s", s.config.Transport)
+	}
+}
+
+// runStdio serves a single client over stdin/stdout
+func (s *Server) runStdio(ctx context.Context) error {
 	// Create stdio transport for Claude Code
-	transport := stdio.NewStdioServerTransport()
+	if err := s.serve(stdio.NewStdioServerTransport()); err != nil {
+		s.logger.Error("MCP server error", "error", err)
+		return err
+	}
 
+	<-ctx.Done()
+	s.logger.Info("Shutting down MCP server...")
+	return nil
+}
+
+// serve creates an MCP server with all tools registered and connects it to the transport
+func (s *Server) serve(t transport.Transport) error {
 	// Create MCP server
-	server := mcp_golang.NewServer(transport)
+	server := mcp_golang.NewServer(t)
 
 	// Create MCP registry
 	registry := mcp.NewRegistry(s.toolHandler, s.config, s.logger)
@@ -45,13 +84,8 @@ func (s *Server) Run() error {
 		return err
 	}
 
-	s.logger.Info("Tools registered successfully")
+	s.logger.Debug("Tools registered successfully")
 
 	// Start server
-	if err := server.Serve(); err != nil {
-		s.logger.Error("MCP server error", "error", err)
-		return err
-	}
-
-	return nil
+	return server.Serve()
 }
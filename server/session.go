@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+var (
+	errSessionClosed = errors.New("session closed")
+	errNoListener    = errors.New("no listener for server message")
+)
+
+// sessionEventBuffer is the number of server messages queued for an SSE stream
+const sessionEventBuffer = 64
+
+// session holds the state of a single HTTP client. Every session is served by its
+// own MCP server instance, so protocol state is never shared between clients.
+type session struct {
+	id        string
+	transport *sessionTransport
+
+	mu       sync.Mutex
+	lastSeen time.Time
+}
+
+// touch records client activity on the session
+func (s *session) touch() {
+	s.mu.Lock()
+	s.lastSeen = time.Now()
+	s.mu.Unlock()
+}
+
+// idleSince reports when the session was last used
+func (s *session) idleSince() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSeen
+}
+
+// sessionTransport implements transport.Transport for a single HTTP session. Responses
+// to requests posted over streamable HTTP are handed back to the waiting POST, every
+// other server message is queued for the session's SSE stream.
+type sessionTransport struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu             sync.RWMutex
+	pending        map[transport.RequestId]chan *transport.BaseJsonRpcMessage
+	events         chan *transport.BaseJsonRpcMessage
+	messageHandler func(ctx context.Context, message *transport.BaseJsonRpcMessage)
+	errorHandler   func(error)
+	closeHandler   func()
+	closeOnce      sync.Once
+}
+
+// newSessionTransport creates a session transport. Only SSE sessions queue events.
+func newSessionTransport(withEvents bool) *sessionTransport {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &sessionTransport{
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(map[transport.RequestId]chan *transport.BaseJsonRpcMessage),
+	}
+	if withEvents {
+		t.events = make(chan *transport.BaseJsonRpcMessage, sessionEventBuffer)
+	}
+	return t
+}
+
+// Start implements transport.Transport. Messages are pushed by HTTP handlers, so there is nothing to start.
+func (t *sessionTransport) Start(_ context.Context) error {
+	return nil
+}
+
+// Send implements transport.Transport
+func (t *sessionTransport) Send(_ context.Context, message *transport.BaseJsonRpcMessage) error {
+	if id, ok := responseID(message); ok {
+		t.mu.RLock()
+		waiter := t.pending[id]
+		t.mu.RUnlock()
+
+		if waiter != nil {
+			waiter <- message
+			return nil
+		}
+	}
+
+	select {
+	case <-t.ctx.Done():
+		return errSessionClosed
+	case t.events <- message:
+		return nil
+	default:
+		return errNoListener
+	}
+}
+
+// Close implements transport.Transport
+func (t *sessionTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.cancel()
+
+		t.mu.RLock()
+		handler := t.closeHandler
+		t.mu.RUnlock()
+
+		if handler != nil {
+			handler()
+		}
+	})
+	return nil
+}
+
+// SetCloseHandler implements transport.Transport
+func (t *sessionTransport) SetCloseHandler(handler func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeHandler = handler
+}
+
+// SetErrorHandler implements transport.Transport
+func (t *sessionTransport) SetErrorHandler(handler func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorHandler = handler
+}
+
+// SetMessageHandler implements transport.Transport
+func (t *sessionTransport) SetMessageHandler(handler func(ctx context.Context, message *transport.BaseJsonRpcMessage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messageHandler = handler
+}
+
+// dispatch passes a client message to the MCP protocol
+func (t *sessionTransport) dispatch(ctx context.Context, message *transport.BaseJsonRpcMessage) {
+	t.mu.RLock()
+	handler := t.messageHandler
+	t.mu.RUnlock()
+
+	if handler != nil {
+		handler(ctx, message)
+	}
+}
+
+// await registers interest in the response to request id
+func (t *sessionTransport) await(id transport.RequestId) (<-chan *transport.BaseJsonRpcMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.pending[id]; exists {
+		return nil, fmt.Errorf("request id %d is already in progress", id)
+	}
+
+	waiter := make(chan *transport.BaseJsonRpcMessage, 1)
+	t.pending[id] = waiter
+	return waiter, nil
+}
+
+// forget removes a response waiter registered by await
+func (t *sessionTransport) forget(id transport.RequestId) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, id)
+}
+
+// responseID returns the request id a response or error message answers
+func responseID(message *transport.BaseJsonRpcMessage) (transport.RequestId, bool) {
+	switch message.Type {
+	case transport.BaseMessageTypeJSONRPCResponseType:
+		return message.JsonRpcResponse.Id, true
+	case transport.BaseMessageTypeJSONRPCErrorType:
+		return message.JsonRpcError.Id, true
+	default:
+		return 0, false
+	}
+}
+
+// decodeMessage decodes a single JSON-RPC message sent by a client
+func decodeMessage(body []byte) (*transport.BaseJsonRpcMessage, error) {
+	var probe struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC message: %w", err)
+	}
+
+	switch {
+	case probe.Method != "" && probe.ID != nil:
+		var request transport.BaseJSONRPCRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC request: %w", err)
+		}
+		return transport.NewBaseMessageRequest(&request), nil
+	case probe.Method != "":
+		var notification transport.BaseJSONRPCNotification
+		if err := json.Unmarshal(body, &notification); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC notification: %w", err)
+		}
+		return transport.NewBaseMessageNotification(&notification), nil
+	case probe.Error != nil:
+		var response transport.BaseJSONRPCError
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC error: %w", err)
+		}
+		return transport.NewBaseMessageError(&response), nil
+	default:
+		var response transport.BaseJSONRPCResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC response: %w", err)
+		}
+		return transport.NewBaseMessageResponse(&response), nil
+	}
+}
+
+// sessionStore keeps active sessions and closes the ones left idle
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: make(map[string]*session)}
+}
+
+// add stores a session under a new random id
+func (s *sessionStore) add(t *sessionTransport) (*session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	sess := &session{id: id, transport: t, lastSeen: time.Now()}
+
+	s.mu.Lock()
+	s.sessions[id] = sess
+	s.mu.Unlock()
+
+	return sess, nil
+}
+
+// get returns an active session and marks it as used
+func (s *sessionStore) get(id string) (*session, bool) {
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	s.mu.Unlock()
+
+	if ok {
+		sess.touch()
+	}
+	return sess, ok
+}
+
+// remove closes and forgets a session
+func (s *sessionStore) remove(id string) bool {
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if ok {
+		_ = sess.transport.Close()
+	}
+	return ok
+}
+
+// expire closes sessions idle since before the deadline and returns their number
+func (s *sessionStore) expire(deadline time.Time) int {
+	var expired []string
+
+	s.mu.Lock()
+	for id, sess := range s.sessions {
+		if sess.idleSince().Before(deadline) {
+			expired = append(expired, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range expired {
+		s.remove(id)
+	}
+	return len(expired)
+}
+
+// closeAll closes every session
+func (s *sessionStore) closeAll() {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		s.remove(id)
+	}
+}
+
+// newSessionID generates a random session identifier
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,406 @@
+package aggregate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+)
+
+var (
+	ErrInvalidAggregation = errors.New("invalid aggregation")
+	ErrMultipleStatements = errors.New("aggregation query must be a single statement")
+)
+
+// Metric functions
+const (
+	MetricCount         = "count"
+	MetricCountDistinct = "count_distinct"
+	MetricSum           = "sum"
+	MetricAvg           = "avg"
+	MetricMin           = "min"
+	MetricMax           = "max"
+)
+
+// defaultLimit is the number of groups returned when no limit is given
+const defaultLimit = 20
+
+// metricFunctions maps metric functions to their SQL aggregate functions
+var metricFunctions = map[string]string{
+	MetricSum: "SUM",
+	MetricAvg: "AVG",
+	MetricMin: "MIN",
+	MetricMax: "MAX",
+}
+
+// Handler handles aggregations over grouped documents
+type Handler struct {
+	client client.ManticoreClient
+	logger *slog.Logger
+}
+
+// NewHandler creates a new aggregation handler
+func NewHandler(c client.ManticoreClient, logger *slog.Logger) *Handler {
+	return &Handler{
+		client: c,
+		logger: logger,
+	}
+}
+
+// Args represents arguments for aggregate tool
+type Args struct {
+	Table   string `json:"table" jsonschema:"required" description:"Table name to aggregate"`
+	Cluster string `json:"cluster,omitempty" description:"Cluster name (optional)"`
+
+	// Matched documents
+	Query  string         `json:"query,omitempty" description:"Full-text query restricting the aggregated documents"`
+	Filter *search.Filter `json:"filter,omitempty" description:"Structured filter restricting the aggregated documents"`
+
+	// Grouping
+	Dimensions []string       `json:"dimensions,omitempty" description:"Attributes or JSON attribute paths to group by (none = a single bucket over all documents)"`
+	Metrics    []Metric       `json:"metrics,omitempty" description:"Metrics computed per bucket (default: count)"`
+	Having     *search.Filter `json:"having,omitempty" description:"Condition on metric names or dimensions that buckets must match"`
+	OrderBy    []Order        `json:"order_by,omitempty" description:"Bucket order by metric names or dimensions (default: first metric descending)"`
+
+	// Pagination
+	Limit  int `json:"limit,omitempty" description:"Maximum number of buckets (default: 20)"`
+	Offset int `json:"offset,omitempty" description:"Offset for pagination (default: 0)"`
+
+	// Accuracy
+	AccurateAggregation bool `json:"accurate_aggregation,omitempty" description:"Guarantee exact group counts and metrics on multi-threaded or distributed tables"`
+	MaxMatches          int  `json:"max_matches,omitempty" description:"Maximum groups retained in RAM, raise it for exact results over many groups"`
+	TimeoutMs           int  `json:"timeout_ms,omitempty" description:"Abort the call after this many milliseconds, also applied as max_query_time (0 = server default)"`
+}
+
+// Metric describes a value computed per bucket
+type Metric struct {
+	Function string `json:"function" jsonschema:"required,enum=count,enum=count_distinct,enum=sum,enum=avg,enum=min,enum=max" description:"Aggregate function"`
+	Field    string `json:"field,omitempty" description:"Attribute the function is applied to (not used by count)"`
+	Name     string `json:"name,omitempty" description:"Column name of the metric (default: function_field, or count)"`
+}
+
+// Order sorts buckets by a metric or a dimension
+type Order struct {
+	Field     string `json:"field" jsonschema:"required" description:"Metric name or dimension"`
+	Direction string `json:"direction,omitempty" jsonschema:"enum=asc,enum=desc" description:"Sort direction (default: desc)"`
+}
+
+// Result represents the buckets of an aggregation
+type Result struct {
+	Columns     []string                 `json:"columns"`
+	Buckets     []map[string]interface{} `json:"buckets"`
+	TotalGroups int                      `json:"total_groups"`
+	QueryTimeMs float64                  `json:"query_time_ms"`
+	HasMore     bool                     `json:"has_more"`
+}
+
+// Execute groups the matched documents and computes the metrics of every bucket
+func (h *Handler) Execute(ctx context.Context, args Args) (*Result, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("table parameter is required")
+	}
+
+	// Set defaults
+	if args.Limit <= 0 {
+		args.Limit = defaultLimit
+	}
+	if len(args.Metrics) == 0 {
+		args.Metrics = []Metric{{Function: MetricCount}}
+	}
+
+	sql, err := BuildSQL(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+	if len(client.SplitStatements(sql)) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
+	h.logger.Debug("Executing aggregation query", "sql", sql)
+
+	// SHOW META in the same request reports the number of groups found
+	results, err := h.client.ExecuteSQLResults(ctx, sql+"; SHOW META")
+	if err != nil {
+		return nil, fmt.Errorf("aggregation failed: %w", err)
+	}
+
+	result := &Result{
+		Columns: columnNames(args),
+		Buckets: []map[string]interface{}{},
+	}
+	if len(results) == 0 {
+		return result, nil
+	}
+	if results[0].Error != "" {
+		return nil, fmt.Errorf("aggregation failed: %s", results[0].Error)
+	}
+
+	result.Buckets = results[0].Rows
+	result.TotalGroups = args.Offset + len(result.Buckets)
+
+	if len(results) > 1 {
+		meta := search.ParseVariables(results[1].Rows)
+		// Without dimensions all documents form a single bucket, total_found counts documents then
+		if total, err := strconv.Atoi(meta["total_found"]); err == nil && len(args.Dimensions) > 0 {
+			result.TotalGroups = total
+		}
+		if seconds, err := strconv.ParseFloat(meta["time"], 64); err == nil {
+			result.QueryTimeMs = seconds * 1000
+		}
+	}
+
+	result.HasMore = args.Offset+len(result.Buckets) < result.TotalGroups
+	return result, nil
+}
+
+// BuildSQL renders the grouped SELECT of an aggregation
+func BuildSQL(args Args) (string, error) {
+	if err := validate(args); err != nil {
+		return "", err
+	}
+
+	selectList := make([]string, 0, len(args.Dimensions)+len(args.Metrics))
+	selectList = append(selectList, args.Dimensions...)
+	for _, metric := range args.Metrics {
+		selectList = append(selectList, metricSQL(metric)+" AS "+metricName(metric))
+	}
+
+	var sql strings.Builder
+	sql.WriteString("SELECT ")
+	sql.WriteString(strings.Join(selectList, ", "))
+	sql.WriteString(" FROM ")
+	if args.Cluster != "" {
+		sql.WriteString(args.Cluster)
+		sql.WriteString(":")
+	}
+	sql.WriteString(args.Table)
+
+	// WHERE clause
+	var conditions []string
+	if args.Query != "" {
+		query, err := search.FormatLiteral(args.Query)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, "MATCH("+query+")")
+	}
+	if args.Filter != nil {
+		condition, err := search.BuildFilterSQL(*args.Filter)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, "("+condition+")")
+	}
+	if len(conditions) > 0 {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	// GROUP BY and HAVING clauses
+	if len(args.Dimensions) > 0 {
+		sql.WriteString(" GROUP BY ")
+		sql.WriteString(strings.Join(args.Dimensions, ", "))
+	}
+	if args.Having != nil {
+		condition, err := search.BuildFilterSQL(*args.Having)
+		if err != nil {
+			return "", fmt.Errorf("invalid having: %w", err)
+		}
+		sql.WriteString(" HAVING ")
+		sql.WriteString(condition)
+	}
+
+	// ORDER BY clause
+	orders := make([]string, 0, len(args.OrderBy))
+	for _, order := range args.OrderBy {
+		direction := "DESC"
+		if strings.EqualFold(order.Direction, "asc") {
+			direction = "ASC"
+		}
+		orders = append(orders, order.Field+" "+direction)
+	}
+	if len(orders) == 0 && len(args.Dimensions) > 0 {
+		orders = append(orders, metricName(args.Metrics[0])+" DESC")
+	}
+	if len(orders) > 0 {
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(strings.Join(orders, ", "))
+	}
+
+	// LIMIT and OFFSET clauses
+	sql.WriteString(" LIMIT ")
+	sql.WriteString(strconv.Itoa(args.Limit))
+	if args.Offset > 0 {
+		sql.WriteString(" OFFSET ")
+		sql.WriteString(strconv.Itoa(args.Offset))
+	}
+
+	// OPTION clause
+	var options []string
+	if args.AccurateAggregation {
+		options = append(options, "accurate_aggregation=1")
+	}
+	if args.MaxMatches > 0 {
+		options = append(options, "max_matches="+strconv.Itoa(args.MaxMatches))
+	}
+	if args.TimeoutMs > 0 {
+		options = append(options, "max_query_time="+strconv.Itoa(args.TimeoutMs))
+	}
+	if len(options) > 0 {
+		sql.WriteString(" OPTION ")
+		sql.WriteString(strings.Join(options, ", "))
+	}
+
+	return sql.String(), nil
+}
+
+// validate checks identifiers, metrics and the columns referenced by having and order_by
+func validate(args Args) error {
+	if err := search.ValidateName(args.Table); err != nil {
+		return fmt.Errorf("invalid table: %w", err)
+	}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return fmt.Errorf("invalid cluster: %w", err)
+		}
+	}
+	if args.Limit < 0 || args.Offset < 0 || args.MaxMatches < 0 {
+		return fmt.Errorf("%w: limit, offset and max_matches must not be negative", ErrInvalidAggregation)
+	}
+
+	columns := make(map[string]bool, len(args.Dimensions)+len(args.Metrics))
+	for _, dimension := range args.Dimensions {
+		if err := search.ValidateIdentifier(dimension); err != nil {
+			return fmt.Errorf("invalid dimensions: %w", err)
+		}
+		if columns[dimension] {
+			return fmt.Errorf("%w: duplicate dimension %q", ErrInvalidAggregation, dimension)
+		}
+		columns[dimension] = true
+	}
+
+	if len(args.Metrics) == 0 {
+		return fmt.Errorf("%w: at least one metric is required", ErrInvalidAggregation)
+	}
+	distinct := 0
+	for _, metric := range args.Metrics {
+		if err := validateMetric(metric); err != nil {
+			return err
+		}
+		if metric.Function == MetricCountDistinct {
+			distinct++
+		}
+		name := metricName(metric)
+		if columns[name] {
+			return fmt.Errorf("%w: duplicate column %q", ErrInvalidAggregation, name)
+		}
+		columns[name] = true
+	}
+	// Manticore computes a single COUNT(DISTINCT) per query
+	if distinct > 1 {
+		return fmt.Errorf("%w: only one count_distinct metric per query is supported", ErrInvalidAggregation)
+	}
+
+	if args.Having != nil {
+		if len(args.Dimensions) == 0 {
+			return fmt.Errorf("%w: having requires dimensions", ErrInvalidAggregation)
+		}
+		for _, field := range filterFields(*args.Having) {
+			if !columns[field] {
+				return fmt.Errorf("%w: having references unknown column %q", ErrInvalidAggregation, field)
+			}
+		}
+	}
+	for _, order := range args.OrderBy {
+		if !columns[order.Field] {
+			return fmt.Errorf("%w: order_by references unknown column %q", ErrInvalidAggregation, order.Field)
+		}
+		switch strings.ToLower(order.Direction) {
+		case "", "asc", "desc":
+		default:
+			return fmt.Errorf("%w: unsupported direction %q", ErrInvalidAggregation, order.Direction)
+		}
+	}
+	return nil
+}
+
+// validateMetric checks the function, field and name of a metric
+func validateMetric(metric Metric) error {
+	switch metric.Function {
+	case MetricCount:
+		if metric.Field != "" {
+			return fmt.Errorf("%w: count does not take a field, use count_distinct", ErrInvalidAggregation)
+		}
+	case MetricCountDistinct, MetricSum, MetricAvg, MetricMin, MetricMax:
+		if metric.Field == "" {
+			return fmt.Errorf("%w: %s requires a field", ErrInvalidAggregation, metric.Function)
+		}
+		if err := search.ValidateIdentifier(metric.Field); err != nil {
+			return fmt.Errorf("invalid metrics: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported metric function %q", ErrInvalidAggregation, metric.Function)
+	}
+
+	if err := search.ValidateName(metricName(metric)); err != nil {
+		return fmt.Errorf("invalid metrics: %w", err)
+	}
+	return nil
+}
+
+// metricSQL renders the aggregate function of a metric
+func metricSQL(metric Metric) string {
+	switch metric.Function {
+	case MetricCount:
+		return "COUNT(*)"
+	case MetricCountDistinct:
+		return "COUNT(DISTINCT " + metric.Field + ")"
+	}
+	return metricFunctions[metric.Function] + "(" + metric.Field + ")"
+}
+
+// metricName returns the column name of a metric. Default names of JSON paths replace
+// separators with underscores, e.g. avg of meta.price becomes avg_meta_price.
+func metricName(metric Metric) string {
+	if metric.Name != "" {
+		return metric.Name
+	}
+	if metric.Function == MetricCount {
+		return MetricCount
+	}
+	name := strings.NewReplacer(".", "_", "[", "_", "]", "").Replace(metric.Field)
+	return metric.Function + "_" + name
+}
+
+// columnNames lists the dimensions followed by the metric names in select order
+func columnNames(args Args) []string {
+	columns := make([]string, 0, len(args.Dimensions)+len(args.Metrics))
+	columns = append(columns, args.Dimensions...)
+	for _, metric := range args.Metrics {
+		columns = append(columns, metricName(metric))
+	}
+	return columns
+}
+
+// filterFields collects the fields compared anywhere in a filter
+func filterFields(filter search.Filter) []string {
+	var fields []string
+	if filter.Field != "" {
+		fields = append(fields, filter.Field)
+	}
+	for _, nested := range filter.And {
+		fields = append(fields, filterFields(nested)...)
+	}
+	for _, nested := range filter.Or {
+		fields = append(fields, filterFields(nested)...)
+	}
+	if filter.Not != nil {
+		fields = append(fields, filterFields(*filter.Not)...)
+	}
+	return fields
+}
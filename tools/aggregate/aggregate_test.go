@@ -0,0 +1,185 @@
+package aggregate
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     Args
+		expected string
+	}{
+		{
+			name: "count per dimension",
+			args: Args{
+				Table:      "products",
+				Dimensions: []string{"brand_id"},
+				Metrics:    []Metric{{Function: MetricCount}},
+				Limit:      20,
+			},
+			expected: "SELECT brand_id, COUNT(*) AS count FROM products GROUP BY brand_id ORDER BY count DESC LIMIT 20",
+		},
+		{
+			name: "metrics with query, filter and having",
+			args: Args{
+				Table:      "products",
+				Cluster:    "shop",
+				Query:      "laptop's",
+				Filter:     &search.Filter{Field: "in_stock", Operator: "eq", Value: true},
+				Dimensions: []string{"brand_id", "meta.color"},
+				Metrics: []Metric{
+					{Function: MetricAvg, Field: "price"},
+					{Function: MetricCountDistinct, Field: "seller_id", Name: "sellers"},
+					{Function: MetricMax, Field: "meta.rating"},
+				},
+				Having:              &search.Filter{Field: "avg_price", Operator: "gt", Value: float64(100)},
+				OrderBy:             []Order{{Field: "sellers", Direction: "asc"}, {Field: "brand_id"}},
+				Limit:               10,
+				Offset:              20,
+				AccurateAggregation: true,
+				MaxMatches:          5000,
+			},
+			expected: "SELECT brand_id, meta.color, AVG(price) AS avg_price, COUNT(DISTINCT seller_id) AS sellers, MAX(meta.rating) AS max_meta_rating" +
+				" FROM shop:products WHERE MATCH('laptop\\'s') AND (in_stock = 1)" +
+				" GROUP BY brand_id, meta.color HAVING avg_price > 100" +
+				" ORDER BY sellers ASC, brand_id DESC LIMIT 10 OFFSET 20" +
+				" OPTION accurate_aggregation=1, max_matches=5000",
+		},
+		{
+			name: "single bucket",
+			args: Args{
+				Table:     "products",
+				Metrics:   []Metric{{Function: MetricSum, Field: "stock"}, {Function: MetricMin, Field: "price", Name: "cheapest"}},
+				Limit:     1,
+				TimeoutMs: 500,
+			},
+			expected: "SELECT SUM(stock) AS sum_stock, MIN(price) AS cheapest FROM products LIMIT 1 OPTION max_query_time=500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := BuildSQL(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+		})
+	}
+}
+
+func TestBuildSQL_Invalid(t *testing.T) {
+	count := []Metric{{Function: MetricCount}}
+
+	tests := []struct {
+		name string
+		args Args
+	}{
+		{name: "table injection", args: Args{Table: "products; DROP TABLE x", Metrics: count}},
+		{name: "dimension injection", args: Args{Table: "products", Dimensions: []string{"brand_id) OR (1"}, Metrics: count}},
+		{name: "no metrics", args: Args{Table: "products"}},
+		{name: "unknown function", args: Args{Table: "products", Metrics: []Metric{{Function: "median", Field: "price"}}}},
+		{name: "sum without field", args: Args{Table: "products", Metrics: []Metric{{Function: MetricSum}}}},
+		{name: "count with field", args: Args{Table: "products", Metrics: []Metric{{Function: MetricCount, Field: "price"}}}},
+		{name: "field injection", args: Args{Table: "products", Metrics: []Metric{{Function: MetricAvg, Field: "price) FROM x --"}}}},
+		{name: "name injection", args: Args{Table: "products", Metrics: []Metric{{Function: MetricCount, Name: "c FROM x"}}}},
+		{name: "duplicate name", args: Args{Table: "products", Dimensions: []string{"count"}, Metrics: count}},
+		{
+			name: "two distinct counts",
+			args: Args{Table: "products", Metrics: []Metric{
+				{Function: MetricCountDistinct, Field: "a"},
+				{Function: MetricCountDistinct, Field: "b"},
+			}},
+		},
+		{
+			name: "having unknown column",
+			args: Args{Table: "products", Dimensions: []string{"brand_id"}, Metrics: count,
+				Having: &search.Filter{Field: "price", Operator: "gt", Value: 1}},
+		},
+		{
+			name: "having without dimensions",
+			args: Args{Table: "products", Metrics: count, Having: &search.Filter{Field: "count", Operator: "gt", Value: 1}},
+		},
+		{name: "order by unknown column", args: Args{Table: "products", Metrics: count, OrderBy: []Order{{Field: "price"}}}},
+		{name: "unknown direction", args: Args{Table: "products", Metrics: count, OrderBy: []Order{{Field: "count", Direction: "up"}}}},
+		{name: "negative offset", args: Args{Table: "products", Metrics: count, Offset: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildSQL(tt.args)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHandler_Execute(t *testing.T) {
+	var sql string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			sql = query
+			return []client.SQLResult{
+				{Rows: []map[string]interface{}{
+					{"brand_id": float64(3), "count": float64(120), "avg_price": 899.5},
+					{"brand_id": float64(7), "count": float64(80), "avg_price": 450.25},
+				}},
+				{Rows: []map[string]interface{}{
+					{"Variable_name": "total_found", "Value": "12"},
+					{"Variable_name": "time", "Value": "0.004"},
+				}},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{
+		Table:      "products",
+		Dimensions: []string{"brand_id"},
+		Metrics:    []Metric{{Function: MetricCount}, {Function: MetricAvg, Field: "price"}},
+		Limit:      2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT brand_id, COUNT(*) AS count, AVG(price) AS avg_price FROM products GROUP BY brand_id ORDER BY count DESC LIMIT 2; SHOW META", sql)
+	assert.Equal(t, []string{"brand_id", "count", "avg_price"}, result.Columns)
+	require.Len(t, result.Buckets, 2)
+	assert.InDelta(t, 899.5, result.Buckets[0]["avg_price"], 0)
+	assert.Equal(t, 12, result.TotalGroups)
+	assert.True(t, result.HasMore)
+	assert.InDelta(t, 4.0, result.QueryTimeMs, 0.001)
+}
+
+func TestHandler_ExecuteDefaultMetric(t *testing.T) {
+	var sql string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			sql = query
+			return []client.SQLResult{
+				{Rows: []map[string]interface{}{{"count": float64(500)}}},
+				{Rows: []map[string]interface{}{{"Variable_name": "total_found", "Value": "500"}}},
+			}, nil
+		},
+	}
+	handler := NewHandler(mockClient, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+
+	result, err := handler.Execute(context.Background(), Args{Table: "products", Query: "laptop"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT COUNT(*) AS count FROM products WHERE MATCH('laptop') LIMIT 20; SHOW META", sql)
+	// total_found counts documents when there are no dimensions
+	assert.Equal(t, 1, result.TotalGroups)
+	assert.False(t, result.HasMore)
+}
@@ -25,30 +25,30 @@ func NewHandler(c client.ManticoreClient, logger *slog.Logger) *Handler {
 
 // CreateClusterArgs represents arguments for create_cluster tool
 type CreateClusterArgs struct {
-	Name  string   `json:"name" description:"Cluster name"`
+	Name  string   `json:"name" jsonschema:"required" description:"Cluster name"`
 	Path  string   `json:"path,omitempty" description:"Data directory path (optional)"`
 	Nodes []string `json:"nodes,omitempty" description:"List of nodes (host:port format)"`
 }
 
 // JoinClusterArgs represents arguments for join_cluster tool
 type JoinClusterArgs struct {
-	Name  string   `json:"name" description:"Cluster name to join"`
-	At    string   `json:"at" description:"Address of existing cluster node (host:port)"`
+	Name  string   `json:"name" jsonschema:"required" description:"Cluster name to join"`
+	At    string   `json:"at" jsonschema:"required" description:"Address of existing cluster node (host:port)"`
 	Nodes []string `json:"nodes,omitempty" description:"Explicit list of cluster nodes (optional)"`
 	Path  string   `json:"path,omitempty" description:"Custom path for cluster data (optional)"`
 }
 
 // AlterClusterArgs represents arguments for alter_cluster tool
 type AlterClusterArgs struct {
-	Name      string   `json:"name" description:"Cluster name"`
-	Operation string   `json:"operation" description:"Operation: add, drop, update_nodes"`
+	Name      string   `json:"name" jsonschema:"required" description:"Cluster name"`
+	Operation string   `json:"operation" jsonschema:"required,enum=add,enum=drop,enum=update_nodes" description:"Operation: add, drop, update_nodes"`
 	Table     string   `json:"table,omitempty" description:"Table name (for add/drop operations)"`
 	Nodes     []string `json:"nodes,omitempty" description:"Nodes list (for update_nodes operation)"`
 }
 
 // DeleteClusterArgs represents arguments for delete_cluster tool
 type DeleteClusterArgs struct {
-	Name string `json:"name" description:"Cluster name to delete"`
+	Name string `json:"name" jsonschema:"required" description:"Cluster name to delete"`
 }
 
 // ShowClusterStatusArgs represents arguments for show_cluster_status tool
@@ -58,9 +58,9 @@ type ShowClusterStatusArgs struct {
 
 // SetClusterArgs represents arguments for set_cluster tool
 type SetClusterArgs struct {
-	Name     string `json:"name" description:"Cluster name"`
-	Variable string `json:"variable" description:"Cluster variable name"`
-	Value    string `json:"value" description:"Variable value"`
+	Name     string `json:"name" jsonschema:"required" description:"Cluster name"`
+	Variable string `json:"variable" jsonschema:"required" description:"Cluster variable name"`
+	Value    string `json:"value" jsonschema:"required,oneof_type=string;number;boolean" description:"Variable value"`
 	Global   bool   `json:"global,omitempty" description:"Set as global variable"`
 }
 
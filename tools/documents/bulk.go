@@ -0,0 +1,548 @@
+package documents
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+)
+
+// ErrInvalidBulk is returned for bulk input that cannot be sent to Manticore
+var ErrInvalidBulk = errors.New("invalid bulk request")
+
+// DefaultBulkBatchSize is the number of actions sent per /bulk request by default
+const DefaultBulkBatchSize = 1000
+
+// Bulk actions
+const (
+	BulkInsert  = "insert"
+	BulkReplace = "replace"
+	BulkUpdate  = "update"
+	BulkDelete  = "delete"
+)
+
+// Statuses of bulk items
+const (
+	BulkItemOK      = "ok"
+	BulkItemFailed  = "failed"
+	BulkItemSkipped = "skipped"
+)
+
+// BulkArgs represents arguments for bulk_documents tool
+type BulkArgs struct {
+	Table     string     `json:"table,omitempty" description:"Table of items that do not name their own (required for CSV files)"`
+	Cluster   string     `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	Action    string     `json:"action,omitempty" description:"Action of items that do not name their own: insert, replace, update or delete (default: insert)"`
+	Items     []BulkItem `json:"items,omitempty" description:"Actions to perform, each with action, table, id and document"`
+	File      string     `json:"file,omitempty" description:"Path of a JSONL or CSV file with the items instead of items"`
+	Format    string     `json:"format,omitempty" description:"Format of file: jsonl or csv (default: from the file extension)"`
+	BatchSize int        `json:"batch_size,omitempty" description:"Number of actions per /bulk request (default: 1000)"`
+}
+
+// BulkItem is a single action of a bulk request
+type BulkItem struct {
+	Action   string                 `json:"action,omitempty" description:"insert, replace, update or delete (default: the action argument)"`
+	Table    string                 `json:"table,omitempty" description:"Table name (default: the table argument)"`
+	ID       *int64                 `json:"id,omitempty" description:"Document ID (required except for insert)"`
+	Document map[string]interface{} `json:"document,omitempty" description:"Document fields (required except for delete)"`
+}
+
+// BulkResult holds the outcome of every item of a bulk request in input order
+type BulkResult struct {
+	Items     []BulkItemResult `json:"items"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Skipped   int              `json:"skipped"`
+	Batches   int              `json:"batches"`
+}
+
+// BulkItemResult is the outcome of a bulk item. Items after a failed one are skipped, Manticore
+// stops processing a request at the first error.
+type BulkItemResult struct {
+	Line   int    `json:"line"`
+	Action string `json:"action"`
+	Table  string `json:"table"`
+	ID     uint64 `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Bulk validates all items, sends them as NDJSON to /bulk in batches of BatchSize and reports
+// per item whether it was applied. Nothing is sent when an item is invalid, and no further
+// batches are sent after a batch with errors.
+func (h *Handler) Bulk(ctx context.Context, args BulkArgs) (*BulkResult, error) {
+	items := args.Items
+	if args.File != "" {
+		if len(items) > 0 {
+			return nil, fmt.Errorf("%w: items and file are mutually exclusive", ErrInvalidBulk)
+		}
+
+		var err error
+		if items, err = h.ReadBulkFile(ctx, args.File, args.Format, args.Table); err != nil {
+			return nil, err
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: items or file parameter is required", ErrInvalidBulk)
+	}
+
+	batchSize := args.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBulkBatchSize
+	}
+
+	actions := make([]map[string]interface{}, 0, len(items))
+	result := &BulkResult{Items: make([]BulkItemResult, 0, len(items))}
+	for i, item := range items {
+		action, itemResult, err := buildBulkAction(item, args)
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %d: %v", ErrInvalidBulk, i+1, err)
+		}
+		itemResult.Line = i + 1
+		actions = append(actions, action)
+		result.Items = append(result.Items, itemResult)
+	}
+
+	failed := false
+	for start := 0; start < len(actions); start += batchSize {
+		end := min(start+batchSize, len(actions))
+		batch := result.Items[start:end]
+		if failed {
+			markBulkItems(batch, BulkItemSkipped, "not sent after a failed batch")
+			continue
+		}
+
+		h.logger.Debug("Executing bulk request", "actions", end-start, "batch", result.Batches+1)
+
+		response, err := h.client.Bulk(ctx, actions[start:end])
+		if err != nil {
+			if start == 0 {
+				return nil, fmt.Errorf("bulk failed: %w", err)
+			}
+			// Earlier batches were applied, so report them instead of failing the call
+			markBulkItems(batch, BulkItemFailed, err.Error())
+			failed = true
+			continue
+		}
+		result.Batches++
+
+		applyBulkResponse(batch, response)
+		failed = response.Errors
+	}
+
+	for _, item := range result.Items {
+		switch item.Status {
+		case BulkItemOK:
+			result.Succeeded++
+		case BulkItemFailed:
+			result.Failed++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+// buildBulkAction converts an item into a /bulk action line and the result it starts from
+func buildBulkAction(item BulkItem, args BulkArgs) (map[string]interface{}, BulkItemResult, error) {
+	action := strings.ToLower(item.Action)
+	if action == "" {
+		action = strings.ToLower(args.Action)
+	}
+	if action == "" {
+		action = BulkInsert
+	}
+	table := item.Table
+	if table == "" {
+		table = args.Table
+	}
+	result := BulkItemResult{Action: action, Table: table}
+
+	switch {
+	case table == "":
+		return nil, result, fmt.Errorf("table is required")
+	case action != BulkInsert && action != BulkReplace && action != BulkUpdate && action != BulkDelete:
+		return nil, result, fmt.Errorf("unknown action %q", action)
+	case action != BulkInsert && item.ID == nil:
+		return nil, result, fmt.Errorf("id is required for %s", action)
+	case action != BulkDelete && len(item.Document) == 0:
+		return nil, result, fmt.Errorf("document is required for %s", action)
+	case action == BulkDelete && len(item.Document) > 0:
+		return nil, result, fmt.Errorf("document is not allowed for delete")
+	}
+	if err := search.ValidateName(table); err != nil {
+		return nil, result, err
+	}
+
+	body := map[string]interface{}{"table": table}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return nil, result, err
+		}
+		body["cluster"] = args.Cluster
+	}
+	if item.ID != nil {
+		if *item.ID < 0 {
+			return nil, result, fmt.Errorf("id must not be negative")
+		}
+		body["id"] = *item.ID
+		result.ID = uint64(*item.ID)
+	}
+	if action != BulkDelete {
+		body["doc"] = item.Document
+	}
+
+	return map[string]interface{}{action: body}, result, nil
+}
+
+// applyBulkResponse sets the status of the items of a batch from the /bulk response. With one
+// response item per action each item gets its own outcome, otherwise Manticore grouped actions
+// into transactions and only the lines around current_line are known to have failed.
+func applyBulkResponse(items []BulkItemResult, response *client.BulkResponse) {
+	if len(response.Items) == len(items) {
+		failed := false
+		for i, entry := range response.Items {
+			for _, outcome := range entry {
+				if outcome.ID != 0 {
+					items[i].ID = outcome.ID
+				}
+				switch {
+				case failed:
+					items[i].Status, items[i].Error = BulkItemSkipped, "not applied after a failed item"
+				case outcome.Error != nil || outcome.Status >= 400:
+					items[i].Status, items[i].Error = BulkItemFailed, bulkErrorMessage(outcome.Error, response.Error)
+					failed = true
+				default:
+					items[i].Status = BulkItemOK
+				}
+			}
+		}
+		if !response.Errors || failed {
+			return
+		}
+	}
+
+	if !response.Errors {
+		markBulkItems(items, BulkItemOK, "")
+		return
+	}
+
+	// current_line is the 1-based line processing stopped at, skipped_lines the lines of its
+	// transaction that were rolled back
+	failedEnd := min(max(response.CurrentLine, 1), len(items))
+	failedStart := max(failedEnd-max(response.SkippedLines, 1), 0)
+	markBulkItems(items[:failedStart], BulkItemOK, "")
+	markBulkItems(items[failedStart:failedEnd], BulkItemFailed, bulkErrorMessage(nil, response.Error))
+	markBulkItems(items[failedEnd:], BulkItemSkipped, "not applied after a failed item")
+}
+
+// bulkErrorMessage formats the error of a bulk item, which is either a string or an object
+func bulkErrorMessage(itemError any, responseError string) string {
+	switch v := itemError.(type) {
+	case string:
+		if v != "" {
+			return v
+		}
+	case map[string]interface{}:
+		if reason, ok := v["reason"].(string); ok {
+			return reason
+		}
+		if encoded, err := json.Marshal(v); err == nil {
+			return string(encoded)
+		}
+	}
+	if responseError != "" {
+		return responseError
+	}
+	return "bulk action failed"
+}
+
+// markBulkItems sets the status and error of items
+func markBulkItems(items []BulkItemResult, status, message string) {
+	for i := range items {
+		items[i].Status = status
+		items[i].Error = message
+	}
+}
+
+// ReadBulkFile reads bulk items from a JSONL or CSV file. A JSONL line with a document key is
+// an item, any other line is the document itself with an optional id field. CSV files have a
+// header row naming the columns of table, whose values are converted to the column types
+// reported by DESCRIBE; an id column holds document IDs and empty cells are omitted.
+func (h *Handler) ReadBulkFile(ctx context.Context, path, format, table string) ([]BulkItem, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jsonl", ".ndjson", ".json":
+			format = "jsonl"
+		case ".csv":
+			format = "csv"
+		default:
+			return nil, fmt.Errorf("%w: cannot detect the format of %s, set format to jsonl or csv", ErrInvalidBulk, path)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bulk file: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(format) {
+	case "jsonl", "ndjson":
+		return readBulkJSONL(file)
+	case "csv":
+		if table == "" {
+			return nil, fmt.Errorf("%w: table parameter is required for CSV files", ErrInvalidBulk)
+		}
+		columns, err := h.tableColumns(ctx, table, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("%w: table %s has no columns", ErrInvalidBulk, table)
+		}
+		return readBulkCSV(file, columns)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidBulk, format)
+	}
+}
+
+// readBulkJSONL reads one item per line, numbers are kept as written
+func readBulkJSONL(r io.Reader) ([]BulkItem, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var items []BulkItem
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidBulk, line, err)
+		}
+
+		item, err := jsonlItem(object)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidBulk, line, err)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bulk file: %w", err)
+	}
+	return items, nil
+}
+
+// jsonlItem converts a JSONL line into an item
+func jsonlItem(object map[string]interface{}) (BulkItem, error) {
+	var item BulkItem
+	if _, ok := object["document"]; !ok {
+		if _, ok := object["action"]; !ok {
+			// A plain document
+			id, hasID := object["id"]
+			delete(object, "id")
+			item.Document = object
+			if hasID {
+				value, err := documentID(id)
+				if err != nil {
+					return item, err
+				}
+				item.ID = &value
+			}
+			return item, nil
+		}
+	}
+
+	for key, value := range object {
+		switch key {
+		case "action", "table":
+			text, ok := value.(string)
+			if !ok {
+				return item, fmt.Errorf("%s must be a string", key)
+			}
+			if key == "action" {
+				item.Action = text
+			} else {
+				item.Table = text
+			}
+		case "id":
+			id, err := documentID(value)
+			if err != nil {
+				return item, err
+			}
+			item.ID = &id
+		case "document":
+			document, ok := value.(map[string]interface{})
+			if !ok {
+				return item, fmt.Errorf("document must be an object")
+			}
+			item.Document = document
+		default:
+			return item, fmt.Errorf("unknown item key %q", key)
+		}
+	}
+	return item, nil
+}
+
+// documentID converts a decoded id into a document ID
+func documentID(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		id, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid id %s", v)
+		}
+		return id, nil
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, fmt.Errorf("invalid id %v", v)
+		}
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid id %q", v)
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("invalid id %v", value)
+	}
+}
+
+// readBulkCSV reads one item per record after the header row
+func readBulkCSV(r io.Reader, columns map[string]string) ([]BulkItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CSV header: %v", ErrInvalidBulk, err)
+	}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if _, ok := columns[name]; !ok && name != "id" {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidBulk, name)
+		}
+		header[i] = name
+	}
+
+	var items []BulkItem
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBulk, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		item := BulkItem{Document: make(map[string]interface{}, len(record))}
+		for i, cell := range record {
+			if cell == "" {
+				continue
+			}
+			if header[i] == "id" {
+				id, err := strconv.ParseInt(cell, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: invalid id %q", ErrInvalidBulk, line, cell)
+				}
+				item.ID = &id
+				continue
+			}
+
+			value, err := convertCSVValue(cell, columns[header[i]])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: column %s: %v", ErrInvalidBulk, line, header[i], err)
+			}
+			item.Document[header[i]] = value
+		}
+		items = append(items, item)
+	}
+}
+
+// convertCSVValue converts a CSV cell to the JSON value of a column type
+func convertCSVValue(cell, columnType string) (interface{}, error) {
+	switch columnType {
+	case "uint", "integer", "bigint":
+		return strconv.ParseInt(cell, 10, 64)
+	case "timestamp":
+		if value, err := strconv.ParseInt(cell, 10, 64); err == nil {
+			return value, nil
+		}
+		parsed, err := time.Parse(time.RFC3339, cell)
+		if err != nil {
+			return nil, fmt.Errorf("expected unix time or RFC3339 timestamp, got %q", cell)
+		}
+		return parsed.Unix(), nil
+	case "float":
+		return strconv.ParseFloat(cell, 64)
+	case "bool":
+		return strconv.ParseBool(cell)
+	case "json":
+		var value interface{}
+		if err := json.Unmarshal([]byte(cell), &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		return value, nil
+	case "mva", "mva64", "multi", "multi64":
+		values := []int64{}
+		for _, part := range splitCSVList(cell) {
+			value, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %q", part)
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case "float_vector":
+		values := []float64{}
+		for _, part := range splitCSVList(cell) {
+			value, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid float %q", part)
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	default:
+		return cell, nil
+	}
+}
+
+// splitCSVList splits a list such as 1,2,3 or [1, 2, 3] stored in a single cell
+func splitCSVList(cell string) []string {
+	cell = strings.Trim(strings.TrimSpace(cell), "[]()")
+	if strings.TrimSpace(cell) == "" {
+		return nil
+	}
+
+	parts := strings.Split(cell, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
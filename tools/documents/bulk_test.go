@@ -0,0 +1,218 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkHandler(mockClient *client.ManticoreClientMock) *Handler {
+	return NewHandler(mockClient, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestHandler_Bulk(t *testing.T) {
+	responses := []*client.BulkResponse{
+		{Items: []map[string]client.BulkItem{{"bulk": {Table: "products", ID: 2, Result: "created", Status: 201}}}, CurrentLine: 3},
+		// The second batch fails at its second line, a transaction of one line
+		{CurrentLine: 2, SkippedLines: 1, Errors: true, Error: "duplicate id '4'"},
+	}
+	mockClient := &client.ManticoreClientMock{
+		BulkFunc: func(_ context.Context, _ []map[string]interface{}) (*client.BulkResponse, error) {
+			response := responses[0]
+			responses = responses[1:]
+			return response, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.Bulk(context.Background(), BulkArgs{
+		Table:   "products",
+		Cluster: "shop",
+		Items: []BulkItem{
+			{ID: int64Ptr(1), Document: map[string]interface{}{"title": "a"}},
+			{ID: int64Ptr(2), Document: map[string]interface{}{"title": "b"}},
+			{Action: "update", ID: int64Ptr(3), Document: map[string]interface{}{"price": 10}},
+			{ID: int64Ptr(4), Document: map[string]interface{}{"title": "d"}},
+			{Action: "DELETE", Table: "archive", ID: int64Ptr(5)},
+			{Action: "replace", ID: int64Ptr(6), Document: map[string]interface{}{"title": "f"}},
+		},
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+
+	calls := mockClient.BulkCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, []map[string]interface{}{
+		{"insert": map[string]interface{}{"table": "products", "cluster": "shop", "id": int64(1), "doc": map[string]interface{}{"title": "a"}}},
+		{"insert": map[string]interface{}{"table": "products", "cluster": "shop", "id": int64(2), "doc": map[string]interface{}{"title": "b"}}},
+	}, calls[0].Actions)
+	assert.Equal(t, map[string]interface{}{"table": "products", "cluster": "shop", "id": int64(3), "doc": map[string]interface{}{"price": 10}}, calls[1].Actions[0]["update"])
+
+	statuses := make([]string, 0, len(result.Items))
+	for _, item := range result.Items {
+		statuses = append(statuses, item.Status)
+	}
+	assert.Equal(t, []string{BulkItemOK, BulkItemOK, BulkItemOK, BulkItemFailed, BulkItemSkipped, BulkItemSkipped}, statuses)
+	assert.Equal(t, "duplicate id '4'", result.Items[3].Error)
+	assert.Equal(t, BulkItemResult{Line: 5, Action: BulkDelete, Table: "archive", ID: 5, Status: BulkItemSkipped, Error: "not sent after a failed batch"}, result.Items[4])
+	assert.Equal(t, 3, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 2, result.Batches)
+}
+
+func TestHandler_BulkItemResults(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		BulkFunc: func(_ context.Context, _ []map[string]interface{}) (*client.BulkResponse, error) {
+			return &client.BulkResponse{
+				Items: []map[string]client.BulkItem{
+					{"insert": {Table: "products", ID: 1001, Result: "created", Status: 201}},
+					{"insert": {Table: "products", ID: 7, Status: 409, Error: map[string]interface{}{"type": "duplicate", "reason": "duplicate id '7'"}}},
+					{"delete": {Table: "products", ID: 8, Status: 200}},
+				},
+				Errors: true,
+			}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.Bulk(context.Background(), BulkArgs{
+		Table: "products",
+		Items: []BulkItem{
+			{Document: map[string]interface{}{"title": "auto id"}},
+			{ID: int64Ptr(7), Document: map[string]interface{}{"title": "dup"}},
+			{Action: "delete", ID: int64Ptr(8)},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, uint64(1001), result.Items[0].ID)
+	assert.Equal(t, BulkItemOK, result.Items[0].Status)
+	assert.Equal(t, BulkItemFailed, result.Items[1].Status)
+	assert.Equal(t, "duplicate id '7'", result.Items[1].Error)
+	assert.Equal(t, BulkItemSkipped, result.Items[2].Status)
+}
+
+func TestHandler_BulkInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args BulkArgs
+	}{
+		{name: "no items", args: BulkArgs{Table: "products"}},
+		{name: "no table", args: BulkArgs{Items: []BulkItem{{Document: map[string]interface{}{"a": 1}}}}},
+		{name: "table injection", args: BulkArgs{Table: "products; DROP TABLE x", Items: []BulkItem{{Document: map[string]interface{}{"a": 1}}}}},
+		{name: "unknown action", args: BulkArgs{Table: "products", Action: "upsert", Items: []BulkItem{{ID: int64Ptr(1), Document: map[string]interface{}{"a": 1}}}}},
+		{name: "update without id", args: BulkArgs{Table: "products", Items: []BulkItem{{Action: "update", Document: map[string]interface{}{"a": 1}}}}},
+		{name: "insert without document", args: BulkArgs{Table: "products", Items: []BulkItem{{ID: int64Ptr(1)}}}},
+		{name: "delete with document", args: BulkArgs{Table: "products", Items: []BulkItem{{Action: "delete", ID: int64Ptr(1), Document: map[string]interface{}{"a": 1}}}}},
+		{name: "negative id", args: BulkArgs{Table: "products", Items: []BulkItem{{ID: int64Ptr(-1), Document: map[string]interface{}{"a": 1}}}}},
+		{name: "items and file", args: BulkArgs{Table: "products", File: "docs.jsonl", Items: []BulkItem{{Document: map[string]interface{}{"a": 1}}}}},
+	}
+
+	mockClient := &client.ManticoreClientMock{}
+	handler := newBulkHandler(mockClient)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.Bulk(context.Background(), tt.args)
+			require.ErrorIs(t, err, ErrInvalidBulk)
+		})
+	}
+	assert.Empty(t, mockClient.BulkCalls())
+}
+
+func TestHandler_ReadBulkFileJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id": 9007199254740993, "title": "plain", "price": 1.50}
+
+{"action": "delete", "table": "archive", "id": 3}
+{"action": "update", "id": "4", "document": {"price": 2}}
+`), 0o600))
+
+	items, err := newBulkHandler(&client.ManticoreClientMock{}).ReadBulkFile(context.Background(), path, "", "")
+	require.NoError(t, err)
+
+	require.Len(t, items, 3)
+	assert.Equal(t, BulkItem{ID: int64Ptr(9007199254740993), Document: map[string]interface{}{"title": "plain", "price": json.Number("1.50")}}, items[0])
+	assert.Equal(t, BulkItem{Action: "delete", Table: "archive", ID: int64Ptr(3)}, items[1])
+	assert.Equal(t, BulkItem{Action: "update", ID: int64Ptr(4), Document: map[string]interface{}{"price": json.Number("2")}}, items[2])
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"title\": \"ok\"}\n{\"title\": \n"), 0o600))
+	_, err = newBulkHandler(&client.ManticoreClientMock{}).ReadBulkFile(context.Background(), path, "", "")
+	require.ErrorIs(t, err, ErrInvalidBulk)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestHandler_ReadBulkFileCSV(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{
+				{"Field": "id", "Type": "bigint"},
+				{"Field": "title", "Type": "text"},
+				{"Field": "price", "Type": "float"},
+				{"Field": "in_stock", "Type": "bool"},
+				{"Field": "tags", "Type": "mva"},
+				{"Field": "meta", "Type": "json"},
+				{"Field": "created_at", "Type": "timestamp"},
+				{"Field": "embedding", "Type": "float_vector"},
+			}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docs.csv")
+	require.NoError(t, os.WriteFile(path, []byte(`id,title,price,in_stock,tags,meta,created_at,embedding
+1,"Laptop, 15""",999.5,true,"1,2,3","{""color"":""red""}",2024-01-02T03:04:05Z,"[0.5, 1]"
+,Phone,,false,,,1700000000,
+`), 0o600))
+
+	items, err := handler.ReadBulkFile(context.Background(), path, "", "products")
+	require.NoError(t, err)
+
+	assert.Equal(t, "DESCRIBE products", mockClient.ExecuteSQLCalls()[0].Query)
+	require.Len(t, items, 2)
+	assert.Equal(t, BulkItem{ID: int64Ptr(1), Document: map[string]interface{}{
+		"title":      "Laptop, 15\"",
+		"price":      999.5,
+		"in_stock":   true,
+		"tags":       []int64{1, 2, 3},
+		"meta":       map[string]interface{}{"color": "red"},
+		"created_at": int64(1704164645),
+		"embedding":  []float64{0.5, 1},
+	}}, items[0])
+	assert.Equal(t, BulkItem{Document: map[string]interface{}{"title": "Phone", "in_stock": false, "created_at": int64(1700000000)}}, items[1])
+
+	invalid := map[string]string{
+		"unknown column": "id,color\n1,red\n",
+		"invalid float":  "id,price\n1,cheap\n",
+		"invalid id":     "id,title\nx,a\n",
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			_, err := handler.ReadBulkFile(context.Background(), path, "", "products")
+			require.ErrorIs(t, err, ErrInvalidBulk)
+		})
+	}
+
+	_, err = handler.ReadBulkFile(context.Background(), path, "", "")
+	require.ErrorIs(t, err, ErrInvalidBulk)
+
+	_, err = handler.ReadBulkFile(context.Background(), filepath.Join(dir, "docs.txt"), "", "products")
+	require.ErrorIs(t, err, ErrInvalidBulk)
+}
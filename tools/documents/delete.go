@@ -0,0 +1,196 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+)
+
+// DefaultDeleteConfirmThreshold is the number of matching documents above which delete_by_query
+// requires confirm_count by default
+const DefaultDeleteConfirmThreshold = 100
+
+// ErrConfirmationRequired is returned when a delete matches more documents than the threshold
+// and confirm_count does not repeat the previewed number of matches
+var ErrConfirmationRequired = errors.New("delete confirmation required")
+
+// DeleteByQueryArgs represents arguments for delete_by_query tool
+type DeleteByQueryArgs struct {
+	Table        string         `json:"table" jsonschema:"required" description:"Table name to delete from"`
+	Cluster      string         `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	Query        string         `json:"query,omitempty" description:"Full-text query selecting the documents to delete"`
+	Filter       *search.Filter `json:"filter,omitempty" description:"Structured filter selecting the documents to delete"`
+	IDs          []int64        `json:"ids,omitempty" description:"IDs of the documents to delete"`
+	ConfirmCount *int           `json:"confirm_count,omitempty" description:"Number of matching documents reported by a preview; required when it exceeds the server threshold"`
+	Preview      bool           `json:"preview,omitempty" description:"Only count the documents the delete would remove"`
+
+	// Threshold is set by the server, not by the caller
+	Threshold int `json:"-"`
+}
+
+// DeleteByQueryResult holds the number of deleted or, in preview mode, matching documents.
+// IDs lists the deleted documents unless the delete needed a confirmation. CountUnknown is set
+// when the matches could not be counted, as in a dry run.
+type DeleteByQueryResult struct {
+	SQL          string   `json:"sql"`
+	Matched      int      `json:"matched"`
+	CountUnknown bool     `json:"count_unknown,omitempty"`
+	Deleted      int      `json:"deleted"`
+	IDs          []uint64 `json:"ids,omitempty"`
+	Threshold    int      `json:"threshold"`
+	Preview      bool     `json:"preview,omitempty"`
+}
+
+// DeleteByQuery deletes documents selected by a full-text query, structured filter and/or id
+// list. The matching documents are counted first: up to the threshold their ids are read and
+// deleted, above it the delete only runs when confirm_count equals the number of matches.
+func (h *Handler) DeleteByQuery(ctx context.Context, args DeleteByQueryArgs) (*DeleteByQueryResult, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("table parameter is required")
+	}
+	if err := search.ValidateName(args.Table); err != nil {
+		return nil, err
+	}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	where, err := buildDeleteWhere(args)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := args.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDeleteConfirmThreshold
+	}
+
+	sql := "DELETE FROM " + h.buildTableName(args.Cluster, args.Table) + " WHERE " + where
+	if len(client.SplitStatements(sql)) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
+	matched, counted, err := h.countMatches(ctx, args.Table, where)
+	if err != nil {
+		return nil, fmt.Errorf("delete preview failed: %w", err)
+	}
+
+	result := &DeleteByQueryResult{SQL: sql, Matched: matched, CountUnknown: !counted, Threshold: threshold, Preview: args.Preview}
+	if args.Preview || (counted && matched == 0) {
+		return result, nil
+	}
+	// Without a count there is nothing to confirm or read ids for, so a dry run goes on to
+	// show the DELETE of the condition. A real delete never runs unbounded.
+	if !counted {
+		if !h.client.DryRun() {
+			return nil, fmt.Errorf("delete preview failed: matching documents of %s could not be counted", args.Table)
+		}
+		return h.runDeleteByQuery(ctx, result)
+	}
+
+	if args.ConfirmCount != nil && *args.ConfirmCount != matched {
+		return nil, fmt.Errorf("%w: confirm_count is %d but %d documents match", ErrConfirmationRequired, *args.ConfirmCount, matched)
+	}
+	if matched > threshold && args.ConfirmCount == nil {
+		return nil, fmt.Errorf("%w: %d documents match, more than the limit of %d; repeat with confirm_count %d to delete them",
+			ErrConfirmationRequired, matched, threshold, matched)
+	}
+
+	// Small deletes remove exactly the documents that were read, so their ids can be reported
+	if matched <= threshold {
+		ids, err := h.matchingIDs(ctx, args.Table, where, matched)
+		if err != nil {
+			return nil, fmt.Errorf("delete preview failed: %w", err)
+		}
+		if len(ids) == 0 {
+			return result, nil
+		}
+		result.IDs = ids
+		result.SQL = "DELETE FROM " + h.buildTableName(args.Cluster, args.Table) + " WHERE id IN (" + joinIDs(ids) + ")"
+	}
+
+	return h.runDeleteByQuery(ctx, result)
+}
+
+// runDeleteByQuery executes the DELETE of a result and records the number of deleted documents
+func (h *Handler) runDeleteByQuery(ctx context.Context, result *DeleteByQueryResult) (*DeleteByQueryResult, error) {
+	h.logger.Debug("Executing delete by query", "matched", result.Matched, "sql", result.SQL)
+
+	results, err := h.client.ExecuteSQLResults(ctx, result.SQL)
+	if err != nil {
+		return nil, fmt.Errorf("delete by query failed: %w", err)
+	}
+	if len(results) > 0 {
+		if results[0].Error != "" {
+			return nil, fmt.Errorf("delete by query failed: %s", results[0].Error)
+		}
+		result.Deleted = results[0].AffectedRows
+	}
+
+	return result, nil
+}
+
+// buildDeleteWhere renders the WHERE condition of an id list combined with a full-text query
+// and structured filter
+func buildDeleteWhere(args DeleteByQueryArgs) (string, error) {
+	var parts []string
+	if len(args.IDs) > 0 {
+		ids := make([]uint64, 0, len(args.IDs))
+		for _, id := range args.IDs {
+			if id <= 0 {
+				return "", fmt.Errorf("invalid id %d", id)
+			}
+			ids = append(ids, uint64(id))
+		}
+		parts = append(parts, "id IN ("+joinIDs(ids)+")")
+	}
+
+	if args.Query != "" || args.Filter != nil {
+		condition, err := buildQueryWhere(args.Query, args.Filter)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, condition)
+	}
+
+	if len(parts) == 0 {
+		return "", fmt.Errorf("either ids, query or filter parameter is required")
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// matchingIDs reads the ids of up to limit documents matching a WHERE condition
+func (h *Handler) matchingIDs(ctx context.Context, table, where string, limit int) ([]uint64, error) {
+	sql := fmt.Sprintf("SELECT id FROM %s WHERE %s ORDER BY id ASC LIMIT %d OPTION max_matches=%d", table, where, limit, limit)
+
+	h.logger.Debug("Reading matching document ids", "sql", sql)
+
+	rows, err := h.client.ExecuteSQL(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(rows))
+	for _, row := range rows {
+		if id, ok := toInt64(row["id"]); ok && id > 0 {
+			ids = append(ids, uint64(id))
+		}
+	}
+	return ids, nil
+}
+
+// joinIDs renders ids as a comma-separated list
+func joinIDs(ids []uint64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatUint(id, 10))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,167 @@
+package documents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDeleteClient returns a mock reporting matched documents and the given ids
+func newDeleteClient(matched int, ids ...float64) *client.ManticoreClientMock {
+	return &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			if strings.HasPrefix(query, "SELECT COUNT(*)") {
+				return []map[string]interface{}{{"matched": float64(matched)}}, nil
+			}
+			rows := make([]map[string]interface{}, 0, len(ids))
+			for _, id := range ids {
+				rows = append(rows, map[string]interface{}{"id": id})
+			}
+			return rows, nil
+		},
+		ExecuteSQLResultsFunc: func(_ context.Context, _ string) ([]client.SQLResult, error) {
+			return []client.SQLResult{{Rows: []map[string]interface{}{}, AffectedRows: matched}}, nil
+		},
+	}
+}
+
+func TestHandler_DeleteByQuery(t *testing.T) {
+	mockClient := newDeleteClient(2, 3, 8)
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.DeleteByQuery(context.Background(), DeleteByQueryArgs{
+		Table:   "products",
+		Cluster: "shop",
+		Query:   "discontinued",
+		Filter:  &search.Filter{Field: "stock", Operator: "eq", Value: float64(0)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &DeleteByQueryResult{
+		SQL:       "DELETE FROM shop:products WHERE id IN (3,8)",
+		Matched:   2,
+		Deleted:   2,
+		IDs:       []uint64{3, 8},
+		Threshold: DefaultDeleteConfirmThreshold,
+	}, result)
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "SELECT COUNT(*) AS matched FROM products WHERE MATCH('discontinued') AND (stock = 0)", calls[0].Query)
+	assert.Equal(t, "SELECT id FROM products WHERE MATCH('discontinued') AND (stock = 0) ORDER BY id ASC LIMIT 2 OPTION max_matches=2", calls[1].Query)
+	require.Len(t, mockClient.ExecuteSQLResultsCalls(), 1)
+}
+
+func TestHandler_DeleteByQueryIDs(t *testing.T) {
+	mockClient := newDeleteClient(1, 5)
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.DeleteByQuery(context.Background(), DeleteByQueryArgs{Table: "products", IDs: []int64{5, 6}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint64{5}, result.IDs)
+	assert.Equal(t, "SELECT COUNT(*) AS matched FROM products WHERE id IN (5,6)", mockClient.ExecuteSQLCalls()[0].Query)
+	assert.Equal(t, "DELETE FROM products WHERE id IN (5)", mockClient.ExecuteSQLResultsCalls()[0].Query)
+}
+
+func TestHandler_DeleteByQueryThreshold(t *testing.T) {
+	filter := &search.Filter{Field: "price", Operator: "lt", Value: float64(1)}
+	confirm := func(n int) *int { return &n }
+
+	tests := []struct {
+		name     string
+		args     DeleteByQueryArgs
+		deleted  int
+		expected error
+	}{
+		{name: "unconfirmed", args: DeleteByQueryArgs{Table: "products", Filter: filter, Threshold: 10}, expected: ErrConfirmationRequired},
+		{name: "wrong confirmation", args: DeleteByQueryArgs{Table: "products", Filter: filter, Threshold: 10, ConfirmCount: confirm(11)}, expected: ErrConfirmationRequired},
+		{name: "confirmed", args: DeleteByQueryArgs{Table: "products", Filter: filter, Threshold: 10, ConfirmCount: confirm(12)}, deleted: 12},
+		{name: "preview", args: DeleteByQueryArgs{Table: "products", Filter: filter, Threshold: 10, Preview: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newDeleteClient(12)
+			handler := newBulkHandler(mockClient)
+
+			result, err := handler.DeleteByQuery(context.Background(), tt.args)
+			if tt.expected != nil {
+				require.ErrorIs(t, err, tt.expected)
+				assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 12, result.Matched)
+			assert.Equal(t, tt.deleted, result.Deleted)
+			assert.Empty(t, result.IDs)
+			assert.Equal(t, "DELETE FROM products WHERE (price < 1)", result.SQL)
+			// Confirmed deletes run the previewed condition without reading ids
+			assert.Len(t, mockClient.ExecuteSQLCalls(), 1)
+		})
+	}
+}
+
+func TestHandler_DeleteByQueryInvalid(t *testing.T) {
+	mockClient := newDeleteClient(0)
+	handler := newBulkHandler(mockClient)
+
+	tests := []struct {
+		name string
+		args DeleteByQueryArgs
+	}{
+		{name: "no table", args: DeleteByQueryArgs{Query: "a"}},
+		{name: "no selection", args: DeleteByQueryArgs{Table: "products"}},
+		{name: "invalid id", args: DeleteByQueryArgs{Table: "products", IDs: []int64{0}}},
+		{name: "invalid filter", args: DeleteByQueryArgs{Table: "products", Filter: &search.Filter{Field: "1=1 OR id", Operator: "gt", Value: 0}}},
+		{name: "table injection", args: DeleteByQueryArgs{Table: "products WHERE 1=1;", Query: "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.DeleteByQuery(context.Background(), tt.args)
+			require.Error(t, err)
+		})
+	}
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+
+	// Nothing matches, nothing is deleted
+	result, err := handler.DeleteByQuery(context.Background(), DeleteByQueryArgs{Table: "products", Query: "a"})
+	require.NoError(t, err)
+	assert.Zero(t, result.Deleted)
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+}
+
+func TestHandler_DeleteByQueryUnknownCount(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		mockClient := &client.ManticoreClientMock{
+			ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+				return []map[string]interface{}{}, nil
+			},
+			ExecuteSQLResultsFunc: func(_ context.Context, _ string) ([]client.SQLResult, error) {
+				return []client.SQLResult{{Rows: []map[string]interface{}{}}}, nil
+			},
+			DryRunFunc: func() bool { return dryRun },
+		}
+
+		result, err := newBulkHandler(mockClient).DeleteByQuery(context.Background(), DeleteByQueryArgs{Table: "products", Query: "a"})
+		if !dryRun {
+			// A real delete never runs without a count to check against the threshold
+			require.Error(t, err)
+			assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.True(t, result.CountUnknown)
+		calls := mockClient.ExecuteSQLResultsCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "DELETE FROM products WHERE MATCH('a')", calls[0].Query)
+	}
+}
@@ -2,51 +2,64 @@ package documents
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
+	"sync"
 
 	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
 )
 
+// ErrMultipleStatements is returned when a condition would make a query run more than one statement
+var ErrMultipleStatements = errors.New("document query must be a single statement")
+
 // Handler handles document operations
 type Handler struct {
 	client client.ManticoreClient
 	logger *slog.Logger
+
+	mu      sync.Mutex
+	schemas map[string]tableSchema
 }
 
 // NewHandler creates a new document handler
 func NewHandler(c client.ManticoreClient, logger *slog.Logger) *Handler {
 	return &Handler{
-		client: c,
-		logger: logger,
+		client:  c,
+		logger:  logger,
+		schemas: make(map[string]tableSchema),
 	}
 }
 
 // InsertDocumentArgs represents arguments for insert_document tool
 type InsertDocumentArgs struct {
-	Table    string                 `json:"table" description:"Table name to insert into"`
+	Table    string                 `json:"table" jsonschema:"required" description:"Table name to insert into"`
 	Cluster  string                 `json:"cluster,omitempty" description:"Cluster name (optional)"`
-	Document map[string]interface{} `json:"document" description:"Document fields as key-value pairs"`
+	Document map[string]interface{} `json:"document" jsonschema:"required" description:"Document fields as key-value pairs"`
 	ID       *int64                 `json:"id,omitempty" description:"Document ID (optional, auto-generated if not provided)"`
-	Replace  bool                   `json:"replace,omitempty" description:"Use REPLACE instead of INSERT"`
+	Replace  bool                   `json:"replace,omitempty" description:"Use REPLACE instead of INSERT (requires --enable-writes)"`
 }
 
 // UpdateDocumentArgs represents arguments for update_document tool
 type UpdateDocumentArgs struct {
-	Table     string                 `json:"table" description:"Table name to update"`
+	Table     string                 `json:"table" jsonschema:"required" description:"Table name to update"`
 	Cluster   string                 `json:"cluster,omitempty" description:"Cluster name (optional)"`
-	ID        int64                  `json:"id" description:"Document ID to update"`
-	Document  map[string]interface{} `json:"document" description:"Fields to update as key-value pairs"`
-	Condition string                 `json:"condition,omitempty" description:"Additional WHERE condition"`
+	ID        int64                  `json:"id" jsonschema:"required" description:"Document ID to update"`
+	Document  map[string]interface{} `json:"document" jsonschema:"required" description:"Fields to update as key-value pairs"`
+	Filter    *search.Filter         `json:"filter,omitempty" description:"Additional structured filter conditions"`
+	Condition string                 `json:"condition,omitempty" description:"Additional raw SQL WHERE condition (rejected unless the server allows raw where)"`
 }
 
 // DeleteDocumentArgs represents arguments for delete_document tool
 type DeleteDocumentArgs struct {
-	Table     string `json:"table" description:"Table name to delete from"`
-	Cluster   string `json:"cluster,omitempty" description:"Cluster name (optional)"`
-	ID        *int64 `json:"id,omitempty" description:"Document ID to delete (optional if condition provided)"`
-	Condition string `json:"condition,omitempty" description:"WHERE condition for deletion"`
+	Table     string         `json:"table" jsonschema:"required" description:"Table name to delete from"`
+	Cluster   string         `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	ID        *int64         `json:"id" jsonschema:"required" description:"Document ID to delete; use delete_by_query to delete by filter"`
+	Filter    *search.Filter `json:"filter,omitempty" description:"Additional structured filter conditions the document must match"`
+	Condition string         `json:"condition,omitempty" description:"Additional raw SQL WHERE condition (rejected unless the server allows raw where)"`
 }
 
 // InsertDocument inserts a document into Manticore table
@@ -57,6 +70,15 @@ func (h *Handler) InsertDocument(ctx context.Context, args InsertDocumentArgs) (
 	if len(args.Document) == 0 {
 		return nil, fmt.Errorf("document parameter is required and cannot be empty")
 	}
+	if err := validateTarget(args.Table, args.Cluster); err != nil {
+		return nil, err
+	}
+
+	// Fetch column types to format values
+	schema, err := h.tableColumns(ctx, args.Table, documentColumns(args.Document))
+	if err != nil {
+		return nil, err
+	}
 
 	// Build table name with cluster prefix if provided
 	tableName := h.buildTableName(args.Cluster, args.Table)
@@ -81,14 +103,22 @@ func (h *Handler) InsertDocument(ctx context.Context, args InsertDocumentArgs) (
 		values = append(values, fmt.Sprintf("%d", *args.ID))
 	}
 
-	// Add document fields
-	for column, value := range args.Document {
+	// Add document fields in name order
+	for _, column := range documentColumns(args.Document) {
+		if err := checkInsertColumn(column); err != nil {
+			return nil, err
+		}
+		value := args.Document[column]
 		// Skip nil values to avoid syntax errors
 		if value == nil {
 			continue
 		}
+		literal, err := h.formatColumnValue(schema, column, value)
+		if err != nil {
+			return nil, err
+		}
 		columns = append(columns, column)
-		values = append(values, h.formatValue(value))
+		values = append(values, literal)
 	}
 
 	sql.WriteString(strings.Join(columns, ", "))
@@ -96,6 +126,10 @@ func (h *Handler) InsertDocument(ctx context.Context, args InsertDocumentArgs) (
 	sql.WriteString(strings.Join(values, ", "))
 	sql.WriteString(")")
 
+	if len(client.SplitStatements(sql.String())) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
 	h.logger.Debug("Executing insert document query", "sql", sql.String())
 
 	result, err := h.client.ExecuteSQL(ctx, sql.String())
@@ -114,6 +148,15 @@ func (h *Handler) UpdateDocument(ctx context.Context, args UpdateDocumentArgs) (
 	if len(args.Document) == 0 {
 		return nil, fmt.Errorf("document parameter is required and cannot be empty")
 	}
+	if err := validateTarget(args.Table, args.Cluster); err != nil {
+		return nil, err
+	}
+
+	// Fetch column types to format values
+	schema, err := h.tableColumns(ctx, args.Table, documentColumns(args.Document))
+	if err != nil {
+		return nil, err
+	}
 
 	// Build table name with cluster prefix if provided
 	tableName := h.buildTableName(args.Cluster, args.Table)
@@ -126,8 +169,12 @@ func (h *Handler) UpdateDocument(ctx context.Context, args UpdateDocumentArgs) (
 
 	// Build SET clause
 	setParts := make([]string, 0, len(args.Document))
-	for column, value := range args.Document {
-		setParts = append(setParts, column+"="+h.formatValue(value))
+	for _, column := range documentColumns(args.Document) {
+		literal, err := h.formatColumnValue(schema, column, args.Document[column])
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, column+"="+literal)
 	}
 	sql.WriteString(strings.Join(setParts, ", "))
 
@@ -135,6 +182,17 @@ func (h *Handler) UpdateDocument(ctx context.Context, args UpdateDocumentArgs) (
 	sql.WriteString(" WHERE id=")
 	sql.WriteString(fmt.Sprintf("%d", args.ID))
 
+	// Add structured filter if provided
+	if args.Filter != nil {
+		condition, err := search.BuildFilterSQL(*args.Filter)
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(" AND (")
+		sql.WriteString(condition)
+		sql.WriteString(")")
+	}
+
 	// Add additional condition if provided
 	if args.Condition != "" {
 		sql.WriteString(" AND (")
@@ -142,6 +200,10 @@ func (h *Handler) UpdateDocument(ctx context.Context, args UpdateDocumentArgs) (
 		sql.WriteString(")")
 	}
 
+	if len(client.SplitStatements(sql.String())) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
 	h.logger.Debug("Executing update document query", "sql", sql.String())
 
 	result, err := h.client.ExecuteSQL(ctx, sql.String())
@@ -152,13 +214,18 @@ func (h *Handler) UpdateDocument(ctx context.Context, args UpdateDocumentArgs) (
 	return result, nil
 }
 
-// DeleteDocument deletes a document from Manticore table
+// DeleteDocument deletes a document from Manticore table by ID. Filter and condition only
+// narrow the delete of that document; deletes of many documents go through DeleteByQuery,
+// which counts them first.
 func (h *Handler) DeleteDocument(ctx context.Context, args DeleteDocumentArgs) ([]map[string]interface{}, error) {
 	if args.Table == "" {
 		return nil, fmt.Errorf("table parameter is required")
 	}
-	if args.ID == nil && args.Condition == "" {
-		return nil, fmt.Errorf("either id or condition parameter is required")
+	if args.ID == nil {
+		return nil, fmt.Errorf("id parameter is required, use delete_by_query to delete documents by filter")
+	}
+	if err := validateTarget(args.Table, args.Cluster); err != nil {
+		return nil, err
 	}
 
 	// Build table name with cluster prefix if provided
@@ -170,11 +237,16 @@ func (h *Handler) DeleteDocument(ctx context.Context, args DeleteDocumentArgs) (
 	sql.WriteString(tableName)
 	sql.WriteString(" WHERE ")
 
-	var whereParts []string
+	// Add ID condition
+	whereParts := []string{fmt.Sprintf("id=%d", *args.ID)}
 
-	// Add ID condition if provided
-	if args.ID != nil {
-		whereParts = append(whereParts, fmt.Sprintf("id=%d", *args.ID))
+	// Add structured filter if provided
+	if args.Filter != nil {
+		condition, err := search.BuildFilterSQL(*args.Filter)
+		if err != nil {
+			return nil, err
+		}
+		whereParts = append(whereParts, "("+condition+")")
 	}
 
 	// Add custom condition if provided
@@ -184,6 +256,10 @@ func (h *Handler) DeleteDocument(ctx context.Context, args DeleteDocumentArgs) (
 
 	sql.WriteString(strings.Join(whereParts, " AND "))
 
+	if len(client.SplitStatements(sql.String())) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
 	h.logger.Debug("Executing delete document query", "sql", sql.String())
 
 	result, err := h.client.ExecuteSQL(ctx, sql.String())
@@ -198,10 +274,7 @@ func (h *Handler) DeleteDocument(ctx context.Context, args DeleteDocumentArgs) (
 func (h *Handler) formatValue(value interface{}) string {
 	switch v := value.(type) {
 	case string:
-		// Escape single quotes according to Manticore documentation
-		escapedValue := strings.ReplaceAll(v, "\\", "\\\\")         // Escape backslashes first
-		escapedValue = strings.ReplaceAll(escapedValue, "'", "\\'") // Escape single quotes
-		return "'" + escapedValue + "'"
+		return quoteString(v)
 	case int, int8, int16, int32, int64:
 		return fmt.Sprintf("%d", v)
 	case uint, uint8, uint16, uint32, uint64:
@@ -217,9 +290,45 @@ func (h *Handler) formatValue(value interface{}) string {
 		return "NULL"
 	default:
 		// Convert to string as fallback
-		escapedValue := strings.ReplaceAll(fmt.Sprintf("%v", v), "'", "''")
-		return "'" + escapedValue + "'"
+		return quoteString(fmt.Sprintf("%v", v))
+	}
+}
+
+// quoteString renders a string literal, escaping backslashes and single quotes according to
+// Manticore documentation
+func quoteString(value string) string {
+	escapedValue := strings.ReplaceAll(value, "\\", "\\\\")     // Escape backslashes first
+	escapedValue = strings.ReplaceAll(escapedValue, "'", "\\'") // Escape single quotes
+	return "'" + escapedValue + "'"
+}
+
+// checkInsertColumn rejects JSON paths such as meta.color, which only updates can set
+func checkInsertColumn(column string) error {
+	if baseColumn(column) != column {
+		return fmt.Errorf("%w %q: JSON paths can only be set by updates", ErrUnknownColumn, column)
+	}
+	return nil
+}
+
+// documentColumns returns the field names of a document in name order
+func documentColumns(document map[string]interface{}) []string {
+	columns := make([]string, 0, len(document))
+	for column := range document {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// validateTarget checks the table and the optional cluster a document query is sent to
+func validateTarget(table, cluster string) error {
+	if err := search.ValidateName(table); err != nil {
+		return err
+	}
+	if cluster != "" {
+		return search.ValidateName(cluster)
 	}
+	return nil
 }
 
 // buildTableName constructs table name with cluster prefix if provided
@@ -7,11 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"manticore-mcp-server/client"
 	"manticore-mcp-server/config"
 	"manticore-mcp-server/testutils"
+	"manticore-mcp-server/tools/search"
 )
 
 type DocumentsTestSuite struct {
@@ -319,7 +322,7 @@ func (s *DocumentsTestSuite) TestDeleteDocumentByID() {
 	s.Len(checkResult2, 1)
 }
 
-func (s *DocumentsTestSuite) TestDeleteDocumentByCondition() {
+func (s *DocumentsTestSuite) TestDeleteDocumentConditionRequiresID() {
 	ctx := context.Background()
 
 	// Insert test documents
@@ -359,25 +362,19 @@ func (s *DocumentsTestSuite) TestDeleteDocumentByCondition() {
 		s.NoError(err)
 	}
 
-	// Delete documents by condition
+	// A condition without an ID is rejected, deletes by condition go through DeleteByQuery
 	args := DeleteDocumentArgs{
 		Table:     "test_documents_table",
 		Condition: "category = 1",
 	}
 
-	result, err := s.handler.DeleteDocument(ctx, args)
-	s.NoError(err)
-	s.NotNil(result)
+	_, err := s.handler.DeleteDocument(ctx, args)
+	s.Error(err)
 
-	// Verify documents with category 1 were deleted
-	checkResult, err := s.client.ExecuteSQL(ctx, "SELECT * FROM test_documents_table WHERE category = 1")
+	// Verify no document was deleted
+	checkResult, err := s.client.ExecuteSQL(ctx, "SELECT * FROM test_documents_table")
 	s.NoError(err)
-	s.Empty(checkResult)
-
-	// Verify document with category 2 still exists
-	checkResult2, err := s.client.ExecuteSQL(ctx, "SELECT * FROM test_documents_table WHERE category = 2")
-	s.NoError(err)
-	s.Len(checkResult2, 1)
+	s.Len(checkResult, 3)
 }
 
 func (s *DocumentsTestSuite) TestDeleteDocumentByIDAndCondition() {
@@ -526,12 +523,12 @@ func (s *DocumentsTestSuite) TestDocumentErrors() {
 	_, err = s.handler.DeleteDocument(ctx, args4)
 	s.Error(err, "Should error when table is missing for delete")
 
-	// Test delete without ID or condition
+	// Test delete without ID
 	args5 := DeleteDocumentArgs{
 		Table: "test_documents_table",
 	}
 	_, err = s.handler.DeleteDocument(ctx, args5)
-	s.Error(err, "Should error when both ID and condition are missing for delete")
+	s.Error(err, "Should error when ID is missing for delete")
 }
 
 func (s *DocumentsTestSuite) TestNilValues() {
@@ -556,3 +553,105 @@ func (s *DocumentsTestSuite) TestNilValues() {
 func TestDocumentsSuite(t *testing.T) {
 	suite.Run(t, new(DocumentsTestSuite))
 }
+
+func TestHandler_DocumentTargetsInvalid(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{ExecuteSQLFunc: describeProducts}
+	handler := newBulkHandler(mockClient)
+	ctx := context.Background()
+	id := int64(1)
+
+	_, err := handler.InsertDocument(ctx, InsertDocumentArgs{
+		Table:    "products",
+		Cluster:  "t (title) VALUES ('a'); DROP TABLE products; INSERT INTO x",
+		Document: map[string]interface{}{"title": "a"},
+	})
+	require.ErrorIs(t, err, search.ErrInvalidIdentifier)
+
+	_, err = handler.UpdateDocument(ctx, UpdateDocumentArgs{
+		Table:    "products WHERE id>0 --",
+		ID:       1,
+		Document: map[string]interface{}{"title": "a"},
+	})
+	require.ErrorIs(t, err, search.ErrInvalidIdentifier)
+
+	_, err = handler.UpdateDocument(ctx, UpdateDocumentArgs{
+		Table:    "products",
+		Cluster:  "shop WHERE 1=1 --",
+		ID:       1,
+		Document: map[string]interface{}{"title": "a"},
+	})
+	require.ErrorIs(t, err, search.ErrInvalidIdentifier)
+
+	_, err = handler.DeleteDocument(ctx, DeleteDocumentArgs{Table: "products WHERE id>0 --", ID: &id})
+	require.ErrorIs(t, err, search.ErrInvalidIdentifier)
+
+	_, err = handler.DeleteDocument(ctx, DeleteDocumentArgs{Table: "products", Cluster: "shop:x", ID: &id})
+	require.ErrorIs(t, err, search.ErrInvalidIdentifier)
+
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+}
+
+func TestHandler_InsertDocumentColumnOrder(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			if query == "DESCRIBE products" {
+				return describeProducts(context.Background(), query)
+			}
+			return []map[string]interface{}{}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+	id := int64(3)
+
+	for i := 0; i < 5; i++ {
+		_, err := handler.InsertDocument(context.Background(), InsertDocumentArgs{
+			Table:    "products",
+			ID:       &id,
+			Document: map[string]interface{}{"title": "a", "price": 1.5, "tags": []interface{}{float64(1)}, "meta": map[string]interface{}{"k": "v"}},
+		})
+		require.NoError(t, err)
+	}
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 6)
+	for _, call := range calls[1:] {
+		assert.Equal(t, `INSERT INTO products (id, meta, price, tags, title) VALUES (3, '{"k":"v"}', 1.5, (1), 'a')`, call.Query)
+	}
+}
+
+func TestHandler_formatValue(t *testing.T) {
+	handler := newBulkHandler(&client.ManticoreClientMock{})
+
+	assert.Equal(t, `'a\\b\'c'`, handler.formatValue(`a\b'c`))
+	// Values of other types are escaped like strings
+	assert.Equal(t, `'[a\\b \'c]'`, handler.formatValue([]string{`a\b`, "'c"}))
+}
+
+func TestHandler_InsertRejectsJSONPaths(t *testing.T) {
+	describeNothing := func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+		return []map[string]interface{}{}, nil
+	}
+
+	for _, describe := range []func(context.Context, string) ([]map[string]interface{}, error){describeProducts, describeNothing} {
+		mockClient := &client.ManticoreClientMock{ExecuteSQLFunc: describe}
+		handler := newBulkHandler(mockClient)
+
+		_, err := handler.InsertDocument(context.Background(), InsertDocumentArgs{
+			Table:    "products",
+			Document: map[string]interface{}{"title": "a", "meta.color": "red"},
+		})
+		require.ErrorIs(t, err, ErrUnknownColumn)
+
+		_, err = handler.InsertDocuments(context.Background(), InsertDocumentsArgs{
+			Table:     "products",
+			Documents: []map[string]interface{}{{"title": "a", "meta.color": "red"}},
+		})
+		require.ErrorIs(t, err, ErrUnknownColumn)
+
+		// Only DESCRIBE ran, no INSERT was sent
+		for _, call := range mockClient.ExecuteSQLCalls() {
+			assert.Equal(t, "DESCRIBE products", call.Query)
+		}
+		assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+	}
+}
@@ -0,0 +1,216 @@
+package documents
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/tools/search"
+)
+
+// DefaultInsertBatchSize is the number of rows per INSERT statement by default
+const DefaultInsertBatchSize = 100
+
+// InsertDocumentsArgs represents arguments for insert_documents tool
+type InsertDocumentsArgs struct {
+	Table     string                   `json:"table" jsonschema:"required" description:"Table name to insert into"`
+	Cluster   string                   `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	Documents []map[string]interface{} `json:"documents" jsonschema:"required" description:"Documents to insert, each with an optional id field (auto-generated if missing)"`
+	Replace   bool                     `json:"replace,omitempty" description:"Use REPLACE instead of INSERT (requires --enable-writes)"`
+	BatchSize int                      `json:"batch_size,omitempty" description:"Number of rows per INSERT statement (default: 100)"`
+}
+
+// InsertDocumentsResult holds the ids Manticore assigned to the inserted documents in input order
+type InsertDocumentsResult struct {
+	Columns    []string `json:"columns"`
+	IDs        []uint64 `json:"ids"`
+	Inserted   int      `json:"inserted"`
+	Statements int      `json:"statements"`
+}
+
+// InsertDocuments inserts documents with multi-row INSERT statements. All documents share the
+// union of their columns, missing values are filled with the default of the column type
+// reported by DESCRIBE, and the ids of each statement are read with last_insert_id(). When a
+// statement fails, the result of the statements before it is returned with the error.
+func (h *Handler) InsertDocuments(ctx context.Context, args InsertDocumentsArgs) (*InsertDocumentsResult, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("table parameter is required")
+	}
+	if len(args.Documents) == 0 {
+		return nil, fmt.Errorf("documents parameter is required and cannot be empty")
+	}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0)
+	for _, document := range args.Documents {
+		names = append(names, documentColumns(document)...)
+	}
+	types, err := h.tableColumns(ctx, args.Table, names)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := unifiedColumns(args.Documents, types)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]string, 0, len(args.Documents))
+	for i, document := range args.Documents {
+		row, err := h.formatRow(document, columns, types)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		rows = append(rows, row)
+	}
+
+	batchSize := args.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultInsertBatchSize
+	}
+
+	verb := "INSERT INTO "
+	if args.Replace {
+		verb = "REPLACE INTO "
+	}
+	prefix := verb + h.buildTableName(args.Cluster, args.Table) + " (" + strings.Join(columns, ", ") + ") VALUES "
+
+	result := &InsertDocumentsResult{Columns: columns, IDs: make([]uint64, 0, len(rows))}
+	for start := 0; start < len(rows); start += batchSize {
+		end := min(start+batchSize, len(rows))
+		sql := prefix + strings.Join(rows[start:end], ", ")
+
+		h.logger.Debug("Executing insert documents query", "rows", end-start, "sql", sql)
+
+		// last_insert_id() reports the ids of the previous statement of the same session
+		results, err := h.client.ExecuteSQLResults(ctx, sql+"; SELECT last_insert_id()")
+		if err != nil {
+			return result, fmt.Errorf("insert documents failed after %d documents: %w", result.Inserted, err)
+		}
+		if len(results) > 0 && results[0].Error != "" {
+			return result, fmt.Errorf("insert documents failed after %d documents: %s", result.Inserted, results[0].Error)
+		}
+		result.Statements++
+		result.Inserted += end - start
+
+		if len(results) > 1 {
+			result.IDs = append(result.IDs, parseInsertIDs(results[1].Rows)...)
+		}
+	}
+
+	return result, nil
+}
+
+// unifiedColumns returns id, when any document has one, followed by the other columns used by
+// any document in name order. Columns missing from the table schema are rejected unless the
+// schema is unknown, e.g. in a dry run.
+func unifiedColumns(documents []map[string]interface{}, types map[string]string) ([]string, error) {
+	seen := make(map[string]bool)
+	hasID := false
+	var names []string
+	for i, document := range documents {
+		for name := range document {
+			if name == "id" {
+				hasID = true
+				continue
+			}
+			if seen[name] {
+				continue
+			}
+			if err := checkInsertColumn(name); err != nil {
+				return nil, fmt.Errorf("document %d: %w", i+1, err)
+			}
+			if _, ok := types[name]; !ok && len(types) > 0 {
+				return nil, fmt.Errorf("document %d: %w %q", i+1, ErrUnknownColumn, name)
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if hasID {
+		names = append([]string{"id"}, names...)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("documents have no columns")
+	}
+	return names, nil
+}
+
+// formatRow renders the VALUES tuple of a document, using column type defaults for missing
+// and null values. A missing id is 0, which makes Manticore generate one. Without the column
+// types there is no default, so every document must set every column.
+func (h *Handler) formatRow(document map[string]interface{}, columns []string, types map[string]string) (string, error) {
+	values := make([]string, 0, len(columns))
+	for _, column := range columns {
+		value, ok := document[column]
+		switch {
+		case column == "id":
+			if !ok || value == nil {
+				values = append(values, "0")
+				continue
+			}
+			id, err := documentID(value)
+			if err != nil {
+				return "", err
+			}
+			if id < 0 {
+				return "", fmt.Errorf("id must not be negative")
+			}
+			values = append(values, strconv.FormatInt(id, 10))
+		case (!ok || value == nil) && len(types) == 0:
+			return "", fmt.Errorf("column %s has no value and the table schema is unknown", column)
+		case !ok:
+			values = append(values, columnDefault(types[column]))
+		default:
+			literal, err := h.formatColumnValue(types, column, value)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, literal)
+		}
+	}
+	return "(" + strings.Join(values, ", ") + ")", nil
+}
+
+// columnDefault returns the SQL literal Manticore stores for a column without a value
+func columnDefault(columnType string) string {
+	switch columnType {
+	case "text", "string":
+		return "''"
+	case "json":
+		return "'{}'"
+	case "mva", "mva64", "multi", "multi64", "float_vector":
+		return "()"
+	default:
+		return "0"
+	}
+}
+
+// parseInsertIDs reads the comma-separated ids of a last_insert_id() result
+func parseInsertIDs(rows []map[string]interface{}) []uint64 {
+	var ids []uint64
+	for _, row := range rows {
+		for _, value := range row {
+			var text string
+			switch v := value.(type) {
+			case string:
+				text = v
+			case float64:
+				text = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+			for _, part := range strings.Split(text, ",") {
+				if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64); err == nil {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,125 @@
+package documents
+
+import (
+	"context"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func describeProducts(_ context.Context, _ string) ([]map[string]interface{}, error) {
+	return []map[string]interface{}{
+		{"Field": "id", "Type": "bigint"},
+		{"Field": "title", "Type": "text"},
+		{"Field": "price", "Type": "float"},
+		{"Field": "tags", "Type": "mva"},
+		{"Field": "meta", "Type": "json"},
+	}, nil
+}
+
+func TestHandler_InsertDocuments(t *testing.T) {
+	lastIDs := []interface{}{"1001,1002", "7"}
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: describeProducts,
+		ExecuteSQLResultsFunc: func(_ context.Context, _ string) ([]client.SQLResult, error) {
+			ids := lastIDs[0]
+			lastIDs = lastIDs[1:]
+			return []client.SQLResult{
+				{Rows: []map[string]interface{}{}},
+				{Rows: []map[string]interface{}{{"last_insert_id()": ids}}},
+			}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.InsertDocuments(context.Background(), InsertDocumentsArgs{
+		Table:   "products",
+		Cluster: "shop",
+		Documents: []map[string]interface{}{
+			{"title": "Laptop", "price": 999.5},
+			{"title": "Phone's", "meta": nil},
+			{"id": float64(7), "tags": nil, "meta": "{\"a\":1}"},
+		},
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"id", "meta", "price", "tags", "title"}, result.Columns)
+	assert.Equal(t, []uint64{1001, 1002, 7}, result.IDs)
+	assert.Equal(t, 3, result.Inserted)
+	assert.Equal(t, 2, result.Statements)
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "INSERT INTO shop:products (id, meta, price, tags, title) VALUES"+
+		" (0, '{}', 999.5, (), 'Laptop'), (0, '{}', 0, (), 'Phone\\'s'); SELECT last_insert_id()", calls[0].Query)
+	assert.Equal(t, "INSERT INTO shop:products (id, meta, price, tags, title) VALUES"+
+		" (7, '{\"a\":1}', 0, (), ''); SELECT last_insert_id()", calls[1].Query)
+}
+
+func TestHandler_InsertDocumentsReplace(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: describeProducts,
+		ExecuteSQLResultsFunc: func(_ context.Context, _ string) ([]client.SQLResult, error) {
+			return []client.SQLResult{{}, {Rows: []map[string]interface{}{{"last_insert_id()": "5"}}}}, nil
+		},
+	}
+
+	result, err := newBulkHandler(mockClient).InsertDocuments(context.Background(), InsertDocumentsArgs{
+		Table:     "products",
+		Documents: []map[string]interface{}{{"id": float64(5), "title": "a"}},
+		Replace:   true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint64{5}, result.IDs)
+	assert.Equal(t, "REPLACE INTO products (id, title) VALUES (5, 'a'); SELECT last_insert_id()", mockClient.ExecuteSQLResultsCalls()[0].Query)
+}
+
+func TestHandler_InsertDocumentsErrors(t *testing.T) {
+	calls := 0
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: describeProducts,
+		ExecuteSQLResultsFunc: func(_ context.Context, _ string) ([]client.SQLResult, error) {
+			calls++
+			if calls == 2 {
+				return []client.SQLResult{{Error: "duplicate id '2'"}}, nil
+			}
+			return []client.SQLResult{{}, {Rows: []map[string]interface{}{{"last_insert_id()": "1"}}}}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.InsertDocuments(context.Background(), InsertDocumentsArgs{
+		Table:     "products",
+		Documents: []map[string]interface{}{{"id": float64(1), "title": "a"}, {"id": float64(2), "title": "b"}},
+		BatchSize: 1,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 1 documents: duplicate id '2'")
+
+	// The first batch is committed and reported with the error
+	require.NotNil(t, result)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 1, result.Statements)
+	assert.Equal(t, []uint64{1}, result.IDs)
+
+	invalid := []InsertDocumentsArgs{
+		{Documents: []map[string]interface{}{{"title": "a"}}},
+		{Table: "products"},
+		{Table: "products", Documents: []map[string]interface{}{{"color": "red"}}},
+		{Table: "products", Documents: []map[string]interface{}{{"meta.color": "red"}}},
+		{Table: "products", Documents: []map[string]interface{}{{"id": 1.5, "title": "a"}}},
+		{Table: "products", Documents: []map[string]interface{}{{"id": float64(-1), "title": "a"}}},
+		{Table: "products", Documents: []map[string]interface{}{{}}},
+		{Table: "products", Cluster: "shop; DROP", Documents: []map[string]interface{}{{"title": "a"}}},
+	}
+	for _, args := range invalid {
+		_, err := handler.InsertDocuments(context.Background(), args)
+		require.Error(t, err, args)
+	}
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,342 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"manticore-mcp-server/tools/search"
+)
+
+var (
+	// ErrUnknownColumn is returned for document fields that are not columns of the table
+	ErrUnknownColumn = errors.New("unknown column")
+	// ErrTypeMismatch is returned for values that cannot be stored in a column of the table
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+// schemaCacheTTL is how long a table schema fetched with DESCRIBE is reused
+const schemaCacheTTL = time.Minute
+
+// tableSchema holds the column types of a table by column name
+type tableSchema struct {
+	columns map[string]string
+	fetched time.Time
+}
+
+// tableColumns returns the column types of a table from the schema cache. The schema is
+// fetched again once when it is stale or lacks one of the given columns, which may have
+// been added since. An empty result means DESCRIBE reported no columns, e.g. in a dry run.
+func (h *Handler) tableColumns(ctx context.Context, table string, columns []string) (map[string]string, error) {
+	if err := search.ValidateName(table); err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	schema, ok := h.schemas[table]
+	h.mu.Unlock()
+
+	if ok && time.Since(schema.fetched) < schemaCacheTTL {
+		complete := true
+		for _, column := range columns {
+			if _, known := schema.columns[baseColumn(column)]; !known {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			return schema.columns, nil
+		}
+	}
+
+	rows, err := h.client.ExecuteSQL(ctx, "DESCRIBE "+table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table %s: %w", table, err)
+	}
+
+	schema = tableSchema{columns: make(map[string]string, len(rows)), fetched: time.Now()}
+	for _, row := range rows {
+		field, _ := row["Field"].(string)
+		columnType, _ := row["Type"].(string)
+		if field != "" {
+			schema.columns[field] = strings.ToLower(columnType)
+		}
+	}
+	if len(schema.columns) == 0 {
+		return schema.columns, nil
+	}
+
+	h.mu.Lock()
+	h.schemas[table] = schema
+	h.mu.Unlock()
+
+	return schema.columns, nil
+}
+
+// formatColumnValue renders a value as the SQL literal of a column, e.g. (1,2,3) for multi
+// and float_vector columns, a JSON string for json columns and unix time for RFC3339
+// timestamps. JSON paths such as meta.color take scalar values. Without a schema the
+// literal is derived from the value, see formatUntypedValue.
+func (h *Handler) formatColumnValue(columns map[string]string, column string, value interface{}) (string, error) {
+	if err := search.ValidateIdentifier(column); err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return h.formatUntypedValue(column, value)
+	}
+
+	base := baseColumn(column)
+	columnType, ok := columns[base]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownColumn, base)
+	}
+	if base != column {
+		if columnType != "json" {
+			return "", fmt.Errorf("%w: column %s of type %s has no path %s", ErrTypeMismatch, base, columnType, column)
+		}
+		return formatJSONPathValue(column, value)
+	}
+
+	if value == nil {
+		return columnDefault(columnType), nil
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("%w: column %s of type %s does not accept %s %v", ErrTypeMismatch, column, columnType, jsonType(value), value)
+	}
+
+	switch columnType {
+	case "text", "string":
+		text, ok := value.(string)
+		if !ok {
+			return "", mismatch()
+		}
+		return h.formatValue(text), nil
+	case "uint", "integer", "bigint":
+		number, ok := toInt64(value)
+		if !ok {
+			return "", mismatch()
+		}
+		return strconv.FormatInt(number, 10), nil
+	case "timestamp":
+		if text, ok := value.(string); ok {
+			parsed, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return "", fmt.Errorf("%w: column %s of type timestamp expects unix time or an RFC3339 string, got %q", ErrTypeMismatch, column, text)
+			}
+			return strconv.FormatInt(parsed.Unix(), 10), nil
+		}
+		number, ok := toInt64(value)
+		if !ok {
+			return "", mismatch()
+		}
+		return strconv.FormatInt(number, 10), nil
+	case "float":
+		number, ok := toFloat64(value)
+		if !ok {
+			return "", mismatch()
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case "bool":
+		switch v := value.(type) {
+		case bool:
+			return h.formatValue(v), nil
+		default:
+			if number, ok := toInt64(value); ok && (number == 0 || number == 1) {
+				return strconv.FormatInt(number, 10), nil
+			}
+			return "", mismatch()
+		}
+	case "json":
+		// Strings holding a JSON object or array are stored as is
+		if text, ok := value.(string); ok {
+			trimmed := strings.TrimSpace(text)
+			if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+				return h.formatValue(trimmed), nil
+			}
+		}
+		return h.formatJSONValue(column, value)
+	case "mva", "mva64", "multi", "multi64":
+		values, ok := toList(value)
+		if !ok {
+			return "", mismatch()
+		}
+		parts := make([]string, 0, len(values))
+		for _, item := range values {
+			number, ok := toInt64(item)
+			if !ok || (number < 0 && (columnType == "mva" || columnType == "multi")) {
+				return "", fmt.Errorf("%w: column %s of type %s does not accept element %v", ErrTypeMismatch, column, columnType, item)
+			}
+			parts = append(parts, strconv.FormatInt(number, 10))
+		}
+		return "(" + strings.Join(parts, ",") + ")", nil
+	case "float_vector":
+		values, ok := toList(value)
+		if !ok {
+			return "", mismatch()
+		}
+		parts := make([]string, 0, len(values))
+		for _, item := range values {
+			number, ok := toFloat64(item)
+			if !ok {
+				return "", fmt.Errorf("%w: column %s of type float_vector does not accept element %v", ErrTypeMismatch, column, item)
+			}
+			parts = append(parts, strconv.FormatFloat(number, 'f', -1, 64))
+		}
+		return "(" + strings.Join(parts, ",") + ")", nil
+	default:
+		return h.formatValue(value), nil
+	}
+}
+
+// formatUntypedValue renders a value for a column of unknown type, e.g. in a dry run, the way
+// the typed path would for the column type the value suggests: lists of numbers as multi or
+// float_vector values, objects and other lists as JSON strings and scalars by their Go type
+func (h *Handler) formatUntypedValue(column string, value interface{}) (string, error) {
+	if baseColumn(column) != column {
+		return formatJSONPathValue(column, value)
+	}
+
+	switch value.(type) {
+	case []interface{}, []int64, []float64:
+		values, _ := toList(value)
+		parts := make([]string, 0, len(values))
+		for _, item := range values {
+			if number, ok := toInt64(item); ok {
+				parts = append(parts, strconv.FormatInt(number, 10))
+				continue
+			}
+			number, ok := toFloat64(item)
+			if !ok {
+				// Not a multi value, so the list can only be stored as JSON
+				return h.formatJSONValue(column, value)
+			}
+			parts = append(parts, strconv.FormatFloat(number, 'f', -1, 64))
+		}
+		return "(" + strings.Join(parts, ",") + ")", nil
+	case map[string]interface{}:
+		return h.formatJSONValue(column, value)
+	default:
+		return h.formatValue(value), nil
+	}
+}
+
+// formatJSONValue renders a value as the JSON string literal of a json column
+func (h *Handler) formatJSONValue(column string, value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: column %s: %v", ErrTypeMismatch, column, err)
+	}
+	return h.formatValue(string(encoded)), nil
+}
+
+// formatJSONPathValue renders the scalar value of a JSON attribute path
+func formatJSONPathValue(column string, value interface{}) (string, error) {
+	if number, ok := value.(json.Number); ok {
+		return number.String(), nil
+	}
+	literal, err := search.FormatLiteral(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: path %s takes a string, number or boolean: %v", ErrTypeMismatch, column, err)
+	}
+	return literal, nil
+}
+
+// baseColumn returns the column of a JSON path such as meta.color or tags[0]
+func baseColumn(column string) string {
+	if i := strings.IndexAny(column, ".["); i >= 0 {
+		return column[:i]
+	}
+	return column
+}
+
+// toInt64 converts an integral number to int64
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float64:
+		return int64(v), v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
+	case json.Number:
+		number, err := v.Int64()
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toFloat64 converts a finite number to float64
+func toFloat64(value interface{}) (float64, bool) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	default:
+		return 0, false
+	}
+	return number, !math.IsNaN(number) && !math.IsInf(number, 0)
+}
+
+// toList converts a list value to a slice; a single number is a list of one
+func toList(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []int64:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, item)
+		}
+		return values, true
+	case []float64:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, item)
+		}
+		return values, true
+	default:
+		if _, ok := toFloat64(value); ok {
+			return []interface{}{value}, true
+		}
+		return nil, false
+	}
+}
+
+// jsonType names the JSON type of a value for error messages
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}, []int64, []float64:
+		return "array"
+	default:
+		return "number"
+	}
+}
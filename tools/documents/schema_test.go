@@ -0,0 +1,171 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testColumns = map[string]string{
+	"id":         "bigint",
+	"title":      "text",
+	"brand":      "string",
+	"stock":      "uint",
+	"views":      "bigint",
+	"price":      "float",
+	"in_stock":   "bool",
+	"created_at": "timestamp",
+	"meta":       "json",
+	"tags":       "mva",
+	"tags64":     "mva64",
+	"embedding":  "float_vector",
+}
+
+func TestHandler_formatColumnValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   string
+		value    interface{}
+		expected string
+	}{
+		{name: "text", column: "title", value: "it's", expected: `'it\'s'`},
+		{name: "string", column: "brand", value: "acme", expected: "'acme'"},
+		{name: "uint", column: "stock", value: float64(5), expected: "5"},
+		{name: "bigint json number", column: "views", value: json.Number("9007199254740993"), expected: "9007199254740993"},
+		{name: "float", column: "price", value: 19.99, expected: "19.99"},
+		{name: "float from integer", column: "price", value: float64(20), expected: "20"},
+		{name: "bool", column: "in_stock", value: true, expected: "1"},
+		{name: "bool from number", column: "in_stock", value: float64(0), expected: "0"},
+		{name: "timestamp", column: "created_at", value: float64(1700000000), expected: "1700000000"},
+		{name: "timestamp from RFC3339", column: "created_at", value: "2024-01-02T03:04:05+01:00", expected: "1704161045"},
+		{name: "json object", column: "meta", value: map[string]interface{}{"color": "red", "note": "it's"}, expected: `'{"color":"red","note":"it\'s"}'`},
+		{name: "json array", column: "meta", value: []interface{}{float64(1), "a"}, expected: `'[1,"a"]'`},
+		{name: "json text", column: "meta", value: `{"a": 1}`, expected: `'{"a": 1}'`},
+		{name: "json string", column: "meta", value: "plain", expected: `'"plain"'`},
+		{name: "json path", column: "meta.color", value: "blue", expected: "'blue'"},
+		{name: "json path number", column: "meta.sizes[0]", value: float64(42), expected: "42"},
+		{name: "multi", column: "tags", value: []interface{}{float64(1), float64(2), float64(3)}, expected: "(1,2,3)"},
+		{name: "multi single value", column: "tags", value: float64(7), expected: "(7)"},
+		{name: "multi empty", column: "tags", value: []interface{}{}, expected: "()"},
+		{name: "multi64", column: "tags64", value: []interface{}{float64(-1), json.Number("5000000000")}, expected: "(-1,5000000000)"},
+		{name: "float_vector", column: "embedding", value: []interface{}{0.1, float64(-2), 0.25}, expected: "(0.1,-2,0.25)"},
+		{name: "null", column: "title", value: nil, expected: "''"},
+		{name: "null multi", column: "tags", value: nil, expected: "()"},
+	}
+
+	handler := newBulkHandler(&client.ManticoreClientMock{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			literal, err := handler.formatColumnValue(testColumns, tt.column, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, literal)
+		})
+	}
+}
+
+func TestHandler_formatColumnValueUnknownSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   string
+		value    interface{}
+		expected string
+	}{
+		{name: "string", column: "title", value: "it's", expected: `'it\'s'`},
+		{name: "number", column: "price", value: 19.99, expected: "19.99"},
+		{name: "multi", column: "tags", value: []interface{}{float64(1), float64(2)}, expected: "(1,2)"},
+		{name: "float_vector", column: "embedding", value: []interface{}{0.1, float64(-2)}, expected: "(0.1,-2)"},
+		{name: "json object", column: "meta", value: map[string]interface{}{"note": "it's"}, expected: `'{"note":"it\'s"}'`},
+		{name: "json array", column: "meta", value: []interface{}{float64(1), "a"}, expected: `'[1,"a"]'`},
+		{name: "json path", column: "meta.color", value: "blue", expected: "'blue'"},
+	}
+
+	handler := newBulkHandler(&client.ManticoreClientMock{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			literal, err := handler.formatColumnValue(nil, tt.column, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, literal)
+		})
+	}
+}
+
+func TestHandler_formatColumnValueErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   string
+		value    interface{}
+		expected error
+	}{
+		{name: "unknown column", column: "color", value: "red", expected: ErrUnknownColumn},
+		{name: "unknown json base", column: "attrs.color", value: "red", expected: ErrUnknownColumn},
+		{name: "text number", column: "title", value: float64(1), expected: ErrTypeMismatch},
+		{name: "uint string", column: "stock", value: "5", expected: ErrTypeMismatch},
+		{name: "uint fraction", column: "stock", value: 1.5, expected: ErrTypeMismatch},
+		{name: "float string", column: "price", value: "cheap", expected: ErrTypeMismatch},
+		{name: "bool number", column: "in_stock", value: float64(2), expected: ErrTypeMismatch},
+		{name: "timestamp text", column: "created_at", value: "yesterday", expected: ErrTypeMismatch},
+		{name: "multi strings", column: "tags", value: []interface{}{"a"}, expected: ErrTypeMismatch},
+		{name: "multi negative", column: "tags", value: []interface{}{float64(-1)}, expected: ErrTypeMismatch},
+		{name: "multi object", column: "tags", value: map[string]interface{}{"a": 1}, expected: ErrTypeMismatch},
+		{name: "float_vector strings", column: "embedding", value: []interface{}{"0.1"}, expected: ErrTypeMismatch},
+		{name: "path of non-json", column: "title.x", value: "a", expected: ErrTypeMismatch},
+		{name: "json path object", column: "meta.size", value: map[string]interface{}{"w": 1}, expected: ErrTypeMismatch},
+	}
+
+	handler := newBulkHandler(&client.ManticoreClientMock{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.formatColumnValue(testColumns, tt.column, tt.value)
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+
+	// Column names are validated even without a schema
+	_, err := handler.formatColumnValue(nil, "price=0, title", float64(1))
+	require.Error(t, err)
+}
+
+func TestHandler_tableColumnsCache(t *testing.T) {
+	columns := []map[string]interface{}{{"Field": "id", "Type": "bigint"}, {"Field": "title", "Type": "text"}}
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			if query == "DESCRIBE products" {
+				return columns, nil
+			}
+			return []map[string]interface{}{}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+	ctx := context.Background()
+
+	_, err := handler.InsertDocument(ctx, InsertDocumentArgs{Table: "products", Document: map[string]interface{}{"title": "a"}})
+	require.NoError(t, err)
+	_, err = handler.UpdateDocument(ctx, UpdateDocumentArgs{Table: "products", ID: 1, Document: map[string]interface{}{"title": "b"}})
+	require.NoError(t, err)
+
+	// A column added since the schema was cached makes it refresh once
+	columns = append(columns, map[string]interface{}{"Field": "tags", "Type": "mva"})
+	_, err = handler.UpdateDocument(ctx, UpdateDocumentArgs{Table: "products", ID: 1, Document: map[string]interface{}{"tags": []interface{}{float64(3), float64(1)}}})
+	require.NoError(t, err)
+
+	_, err = handler.UpdateDocument(ctx, UpdateDocumentArgs{Table: "products", ID: 1, Document: map[string]interface{}{"color": "red"}})
+	require.ErrorIs(t, err, ErrUnknownColumn)
+
+	var queries []string
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		queries = append(queries, call.Query)
+	}
+	assert.Equal(t, []string{
+		"DESCRIBE products",
+		"INSERT INTO products (title) VALUES ('a')",
+		"UPDATE products SET title='b' WHERE id=1",
+		"DESCRIBE products",
+		"UPDATE products SET tags=(3,1) WHERE id=1",
+		"DESCRIBE products",
+	}, queries)
+}
@@ -0,0 +1,148 @@
+package documents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+)
+
+// UpdateByQueryArgs represents arguments for update_by_query tool
+type UpdateByQueryArgs struct {
+	Table   string                 `json:"table" jsonschema:"required" description:"Table name to update"`
+	Cluster string                 `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	Query   string                 `json:"query,omitempty" description:"Full-text query selecting the documents to update"`
+	Filter  *search.Filter         `json:"filter,omitempty" description:"Structured filter selecting the documents to update"`
+	Set     map[string]interface{} `json:"set" jsonschema:"required" description:"Attributes or JSON paths such as meta.color to set; lists replace multi values"`
+	Strict  bool                   `json:"strict,omitempty" description:"Fail instead of ignoring updates of missing JSON paths or mistyped values (OPTION strict=1)"`
+	Preview bool                   `json:"preview,omitempty" description:"Only count the documents the update would hit"`
+}
+
+// UpdateByQueryResult holds the statement and the number of updated or, in preview mode,
+// matching documents
+type UpdateByQueryResult struct {
+	SQL      string `json:"sql"`
+	Affected int    `json:"affected"`
+	Matched  int    `json:"matched,omitempty"`
+	Preview  bool   `json:"preview,omitempty"`
+}
+
+// UpdateByQuery updates every document matching a full-text query and/or structured filter.
+// In preview mode it counts the matching documents and returns the UPDATE it would run.
+func (h *Handler) UpdateByQuery(ctx context.Context, args UpdateByQueryArgs) (*UpdateByQueryResult, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("table parameter is required")
+	}
+	if len(args.Set) == 0 {
+		return nil, fmt.Errorf("set parameter is required and cannot be empty")
+	}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	where, err := buildQueryWhere(args.Query, args.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch column types to format values
+	columns := documentColumns(args.Set)
+	schema, err := h.tableColumns(ctx, args.Table, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	setParts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if baseColumn(column) == "id" {
+			return nil, fmt.Errorf("id cannot be updated")
+		}
+		literal, err := h.formatColumnValue(schema, column, args.Set[column])
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, column+"="+literal)
+	}
+
+	sql := "UPDATE " + h.buildTableName(args.Cluster, args.Table) + " SET " + strings.Join(setParts, ", ") + " WHERE " + where
+	if args.Strict {
+		sql += " OPTION strict=1"
+	}
+	if len(client.SplitStatements(sql)) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
+	result := &UpdateByQueryResult{SQL: sql, Preview: args.Preview}
+	if args.Preview {
+		matched, _, err := h.countMatches(ctx, args.Table, where)
+		if err != nil {
+			return nil, fmt.Errorf("update preview failed: %w", err)
+		}
+		result.Matched = matched
+		return result, nil
+	}
+
+	h.logger.Debug("Executing update by query", "sql", sql)
+
+	results, err := h.client.ExecuteSQLResults(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("update by query failed: %w", err)
+	}
+	if len(results) > 0 {
+		if results[0].Error != "" {
+			return nil, fmt.Errorf("update by query failed: %s", results[0].Error)
+		}
+		result.Affected = results[0].AffectedRows
+	}
+
+	return result, nil
+}
+
+// buildQueryWhere renders the WHERE condition of a full-text query and structured filter, at
+// least one of which is required so a statement never hits the whole table by accident
+func buildQueryWhere(query string, filter *search.Filter) (string, error) {
+	var parts []string
+	if query != "" {
+		literal, err := search.FormatLiteral(query)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "MATCH("+literal+")")
+	}
+	if filter != nil {
+		condition, err := search.BuildFilterSQL(*filter)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "("+condition+")")
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("either query or filter parameter is required")
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// countMatches counts the documents of a table matching a WHERE condition. In a dry run, which
+// returns no count row, the count is unknown.
+func (h *Handler) countMatches(ctx context.Context, table, where string) (int, bool, error) {
+	sql := "SELECT COUNT(*) AS matched FROM " + table + " WHERE " + where
+
+	h.logger.Debug("Counting matching documents", "sql", sql)
+
+	rows, err := h.client.ExecuteSQL(ctx, sql)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(rows) == 0 {
+		if h.client.DryRun() {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("no count returned for %s", table)
+	}
+	matched, _ := toInt64(rows[0]["matched"])
+	return int(matched), true, nil
+}
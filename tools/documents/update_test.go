@@ -0,0 +1,111 @@
+package documents
+
+import (
+	"context"
+	"testing"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_UpdateByQuery(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{
+				{"Field": "id", "Type": "bigint"},
+				{"Field": "title", "Type": "text"},
+				{"Field": "price", "Type": "float"},
+				{"Field": "tags", "Type": "mva"},
+				{"Field": "j", "Type": "json"},
+			}, nil
+		},
+		ExecuteSQLResultsFunc: func(_ context.Context, _ string) ([]client.SQLResult, error) {
+			return []client.SQLResult{{Rows: []map[string]interface{}{}, Total: 12, AffectedRows: 12}}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.UpdateByQuery(context.Background(), UpdateByQueryArgs{
+		Table:   "products",
+		Cluster: "shop",
+		Query:   "old 'model'",
+		Filter:  &search.Filter{Field: "price", Operator: "lt", Value: float64(100)},
+		Set: map[string]interface{}{
+			"j.a.b": float64(5),
+			"tags":  []interface{}{float64(4), float64(2)},
+			"price": 9.5,
+		},
+		Strict: true,
+	})
+	require.NoError(t, err)
+
+	expected := "UPDATE shop:products SET j.a.b=5, price=9.5, tags=(4,2) WHERE MATCH('old \\'model\\'') AND (price < 100) OPTION strict=1"
+	assert.Equal(t, expected, result.SQL)
+	assert.Equal(t, 12, result.Affected)
+	assert.False(t, result.Preview)
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, expected, calls[0].Query)
+}
+
+func TestHandler_UpdateByQueryPreview(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			if query == "DESCRIBE products" {
+				return []map[string]interface{}{{"Field": "id", "Type": "bigint"}, {"Field": "price", "Type": "float"}}, nil
+			}
+			return []map[string]interface{}{{"matched": float64(3)}}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+
+	result, err := handler.UpdateByQuery(context.Background(), UpdateByQueryArgs{
+		Table:   "products",
+		Filter:  &search.Filter{Field: "price", Operator: "eq", Value: float64(0)},
+		Set:     map[string]interface{}{"price": float64(1)},
+		Preview: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &UpdateByQueryResult{SQL: "UPDATE products SET price=1 WHERE (price = 0)", Matched: 3, Preview: true}, result)
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "SELECT COUNT(*) AS matched FROM products WHERE (price = 0)", calls[1].Query)
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+}
+
+func TestHandler_UpdateByQueryInvalid(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{{"Field": "id", "Type": "bigint"}, {"Field": "price", "Type": "float"}}, nil
+		},
+	}
+	handler := newBulkHandler(mockClient)
+	set := map[string]interface{}{"price": float64(1)}
+
+	tests := []struct {
+		name string
+		args UpdateByQueryArgs
+	}{
+		{name: "no table", args: UpdateByQueryArgs{Query: "a", Set: set}},
+		{name: "no set", args: UpdateByQueryArgs{Table: "products", Query: "a"}},
+		{name: "no query or filter", args: UpdateByQueryArgs{Table: "products", Set: set}},
+		{name: "invalid filter", args: UpdateByQueryArgs{Table: "products", Filter: &search.Filter{Field: "price) OR (1", Operator: "eq", Value: 1}, Set: set}},
+		{name: "id", args: UpdateByQueryArgs{Table: "products", Query: "a", Set: map[string]interface{}{"id": float64(1)}}},
+		{name: "unknown column", args: UpdateByQueryArgs{Table: "products", Query: "a", Set: map[string]interface{}{"color": "red"}}},
+		{name: "type mismatch", args: UpdateByQueryArgs{Table: "products", Query: "a", Set: map[string]interface{}{"price": "cheap"}}},
+		{name: "cluster injection", args: UpdateByQueryArgs{Table: "products", Cluster: "c; DROP", Query: "a", Set: set}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.UpdateByQuery(context.Background(), tt.args)
+			require.Error(t, err)
+		})
+	}
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+}
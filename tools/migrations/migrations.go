@@ -0,0 +1,357 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/search"
+	"manticore-mcp-server/tools/tables"
+)
+
+// DefaultHistoryTable is the table keeping the migration history by default
+const DefaultHistoryTable = "mcp_migrations"
+
+// ErrInvalidSpec is returned when a table spec file cannot be read or is invalid
+var ErrInvalidSpec = errors.New("invalid table spec")
+
+// Plan step actions
+const (
+	ActionCreate = "create"
+	ActionAlter  = "alter"
+	ActionNone   = "unchanged"
+)
+
+// specExtensions are the file extensions read from a spec directory
+var specExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// Handler handles schema migrations
+type Handler struct {
+	client client.ManticoreClient
+	tables *tables.Handler
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewHandler creates a new migration handler
+func NewHandler(c client.ManticoreClient, t *tables.Handler, logger *slog.Logger) *Handler {
+	return &Handler{
+		client: c,
+		tables: t,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Spec is the desired definition of one table, read from a YAML or JSON file
+type Spec struct {
+	File     string                `json:"-"`
+	Table    string                `json:"table"`
+	Cluster  string                `json:"cluster,omitempty"`
+	Columns  []tables.Column       `json:"columns"`
+	Settings *tables.TableSettings `json:"settings,omitempty"`
+
+	checksum string
+}
+
+// MigrateArgs represents arguments for migrate_schema tool
+type MigrateArgs struct {
+	Apply bool `json:"apply,omitempty" description:"Apply the plan instead of only returning it"`
+
+	// Dir and HistoryTable are set by the server, not by the caller
+	Dir          string `json:"-"`
+	HistoryTable string `json:"-"`
+}
+
+// Step is the change of one table spec
+type Step struct {
+	File       string   `json:"file"`
+	Table      string   `json:"table"`
+	Cluster    string   `json:"cluster,omitempty"`
+	Action     string   `json:"action"`
+	Statements []string `json:"statements"`
+	Applied    bool     `json:"applied,omitempty"`
+
+	checksum string
+}
+
+// Plan holds the steps reaching the specs from the live schema in spec file order
+type Plan struct {
+	Steps      []Step `json:"steps"`
+	Statements int    `json:"statements"`
+	Applied    bool   `json:"applied,omitempty"`
+}
+
+// Migrate reads the table specs of a directory, plans the CREATE and ALTER statements reaching
+// them and, when asked, applies the plan
+func (h *Handler) Migrate(ctx context.Context, args MigrateArgs) (*Plan, error) {
+	if args.Dir == "" {
+		return nil, fmt.Errorf("migrations directory is not configured")
+	}
+
+	specs, err := LoadSpecs(args.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := h.Plan(ctx, specs)
+	if err != nil {
+		return nil, err
+	}
+	if !args.Apply {
+		return plan, nil
+	}
+
+	if err := h.Apply(ctx, plan, args.HistoryTable); err != nil {
+		return plan, err
+	}
+	return plan, nil
+}
+
+// LoadSpecs reads the *.yaml, *.yml and *.json table specs of a directory in file name order
+func LoadSpecs(dir string) ([]Spec, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && specExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	specs := make([]Spec, 0, len(names))
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		spec, err := readSpec(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		spec.File = name
+
+		table := strings.ToLower(spec.Table)
+		if other, ok := seen[table]; ok {
+			return nil, fmt.Errorf("%w: %s: table %s is already defined in %s", ErrInvalidSpec, name, spec.Table, other)
+		}
+		seen[table] = name
+
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// readSpec parses a YAML or JSON table spec file. YAML is converted to JSON first so both
+// formats share the json field names and reject unknown fields.
+func readSpec(path string) (Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Spec{}, fmt.Errorf("failed to read spec: %w", err)
+	}
+	name := filepath.Base(path)
+
+	content := data
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return Spec{}, fmt.Errorf("%w: %s: %v", ErrInvalidSpec, name, err)
+		}
+		if content, err = json.Marshal(value); err != nil {
+			return Spec{}, fmt.Errorf("%w: %s: %v", ErrInvalidSpec, name, err)
+		}
+	}
+
+	var spec Spec
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return Spec{}, fmt.Errorf("%w: %s: %v", ErrInvalidSpec, name, err)
+	}
+
+	// Validate the whole definition up front so a plan never fails half way
+	if _, err := tables.CreateStatements(spec.createArgs()); err != nil {
+		return Spec{}, fmt.Errorf("%w: %s: %v", ErrInvalidSpec, name, err)
+	}
+
+	sum := sha256.Sum256(data)
+	spec.checksum = hex.EncodeToString(sum[:])
+	return spec, nil
+}
+
+// createArgs converts the spec into a create_table definition
+func (s Spec) createArgs() tables.CreateTableArgs {
+	return tables.CreateTableArgs{
+		Table:    s.Table,
+		Columns:  s.Columns,
+		Settings: s.Settings,
+		Cluster:  s.Cluster,
+	}
+}
+
+// Plan compares the specs with the live schema: missing tables are created and existing ones
+// are altered with the minimal statements reported by DESCRIBE and SHOW TABLE ... SETTINGS
+func (h *Handler) Plan(ctx context.Context, specs []Spec) (*Plan, error) {
+	existing, err := h.existingTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Steps: make([]Step, 0, len(specs))}
+	for _, spec := range specs {
+		step := Step{
+			File:     spec.File,
+			Table:    spec.Table,
+			Cluster:  spec.Cluster,
+			checksum: spec.checksum,
+		}
+
+		if existing[strings.ToLower(spec.Table)] {
+			step.Statements, err = h.tables.DiffTable(ctx, spec.Table, spec.Cluster, tables.TableSchema{
+				Columns:  spec.Columns,
+				Settings: spec.Settings,
+			})
+			step.Action = ActionAlter
+		} else {
+			step.Statements, err = tables.CreateStatements(spec.createArgs())
+			step.Action = ActionCreate
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.File, err)
+		}
+
+		if len(step.Statements) == 0 {
+			step.Action = ActionNone
+			step.Statements = []string{}
+		}
+		plan.Statements += len(step.Statements)
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan, nil
+}
+
+// Apply runs the statements of a plan in order and records every applied step in the
+// history table. It stops at the first failing statement.
+func (h *Handler) Apply(ctx context.Context, plan *Plan, historyTable string) error {
+	if historyTable == "" {
+		historyTable = DefaultHistoryTable
+	}
+	if err := search.ValidateName(historyTable); err != nil {
+		return err
+	}
+
+	if plan.Statements > 0 {
+		sql := "CREATE TABLE IF NOT EXISTS " + historyTable +
+			" (file string, table_name string, action string, checksum string, statements json, applied_at timestamp)"
+		if _, err := h.client.ExecuteSQL(ctx, sql); err != nil {
+			return fmt.Errorf("failed to create migration history table: %w", err)
+		}
+	}
+
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		if len(step.Statements) == 0 {
+			continue
+		}
+
+		for _, statement := range step.Statements {
+			h.logger.Info("Applying migration", "file", step.File, "sql", statement)
+
+			if _, err := h.client.ExecuteSQL(ctx, statement); err != nil {
+				return fmt.Errorf("%s: migration failed at %q: %w", step.File, statement, err)
+			}
+		}
+		step.Applied = true
+
+		if err := h.recordStep(ctx, historyTable, *step); err != nil {
+			return err
+		}
+	}
+
+	plan.Applied = true
+	return nil
+}
+
+// recordStep inserts an applied step into the history table
+func (h *Handler) recordStep(ctx context.Context, historyTable string, step Step) error {
+	statements, err := json.Marshal(step.Statements)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, 0, 5)
+	for _, value := range []string{step.File, step.Table, step.Action, step.checksum, string(statements)} {
+		literal, err := search.FormatLiteral(value)
+		if err != nil {
+			return err
+		}
+		values = append(values, literal)
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (file, table_name, action, checksum, statements, applied_at) VALUES (%s, %d)",
+		historyTable, strings.Join(values, ", "), h.now().Unix())
+	if _, err := h.client.ExecuteSQL(ctx, sql); err != nil {
+		return fmt.Errorf("%s: failed to record migration: %w", step.File, err)
+	}
+	return nil
+}
+
+// existingTables returns the lower-cased names of the tables on the server
+func (h *Handler) existingTables(ctx context.Context) (map[string]bool, error) {
+	rows, err := h.client.ExecuteSQL(ctx, "SHOW TABLES")
+	if err != nil {
+		return nil, fmt.Errorf("show tables failed: %w", err)
+	}
+
+	existing := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		// Older servers name the column Index
+		name, ok := row["Table"].(string)
+		if !ok {
+			name, _ = row["Index"].(string)
+		}
+		if name != "" {
+			existing[strings.ToLower(name)] = true
+		}
+	}
+	return existing, nil
+}
+
+// FormatPlan renders a plan as text, one block per spec file
+func FormatPlan(plan *Plan) string {
+	var text strings.Builder
+	for _, step := range plan.Steps {
+		fmt.Fprintf(&text, "%s: %s %s", step.File, step.Action, step.Table)
+		if step.Cluster != "" {
+			fmt.Fprintf(&text, " (cluster %s)", step.Cluster)
+		}
+		text.WriteString("\n")
+		for _, statement := range step.Statements {
+			fmt.Fprintf(&text, "  %s;\n", statement)
+		}
+	}
+
+	switch {
+	case plan.Statements == 0:
+		text.WriteString("Schema is up to date\n")
+	case plan.Applied:
+		fmt.Fprintf(&text, "Applied %d statements\n", plan.Statements)
+	default:
+		fmt.Fprintf(&text, "%d statements to apply\n", plan.Statements)
+	}
+	return text.String()
+}
@@ -0,0 +1,189 @@
+package migrations
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/tables"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const productsSpec = `
+table: products
+columns:
+  - name: title
+    type: text
+  - name: price
+    type: float
+  - name: embedding
+    type: float_vector
+    knn_type: hnsw
+    knn_dims: 4
+    hnsw_similarity: COSINE
+settings:
+  morphology: stem_en
+`
+
+const ordersSpec = `{
+  "table": "orders",
+  "cluster": "shop",
+  "columns": [{"name": "total", "type": "float"}, {"name": "status", "type": "string"}]
+}`
+
+func newMockHandler(mockClient *client.ManticoreClientMock) *Handler {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, tables.NewHandler(mockClient, logger), logger)
+	handler.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return handler
+}
+
+func writeSpecs(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+// newSchemaClient returns a mock where orders exists with a total column only
+func newSchemaClient() *client.ManticoreClientMock {
+	return &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			switch query {
+			case "SHOW TABLES":
+				return []map[string]interface{}{{"Table": "orders", "Type": "rt"}}, nil
+			case "DESCRIBE orders":
+				return []map[string]interface{}{
+					{"Field": "id", "Type": "bigint", "Properties": ""},
+					{"Field": "total", "Type": "float", "Properties": ""},
+				}, nil
+			}
+			return []map[string]interface{}{}, nil
+		},
+	}
+}
+
+func TestLoadSpecs(t *testing.T) {
+	dir := writeSpecs(t, map[string]string{
+		"02_orders.json":   ordersSpec,
+		"01_products.yaml": productsSpec,
+		"README.md":        "not a spec",
+	})
+
+	specs, err := LoadSpecs(dir)
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+
+	assert.Equal(t, "01_products.yaml", specs[0].File)
+	assert.Equal(t, "products", specs[0].Table)
+	assert.Equal(t, tables.Column{Name: "embedding", Type: "float_vector", KNNType: "hnsw", KNNDims: 4, HNSWSimilarity: "COSINE"}, specs[0].Columns[2])
+	assert.Equal(t, "stem_en", specs[0].Settings.Morphology)
+	assert.Len(t, specs[0].checksum, 64)
+
+	assert.Equal(t, "02_orders.json", specs[1].File)
+	assert.Equal(t, "shop", specs[1].Cluster)
+}
+
+func TestLoadSpecsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "unknown field", files: map[string]string{"a.yaml": "table: a\ncolumns:\n  - name: x\n    type: text\n    size: 3\n"}},
+		{name: "invalid type", files: map[string]string{"a.json": `{"table": "a", "columns": [{"name": "x", "type": "varchar"}]}`}},
+		{name: "no columns", files: map[string]string{"a.yaml": "table: a\n"}},
+		{name: "malformed yaml", files: map[string]string{"a.yml": "table: [a"}},
+		{name: "duplicate table", files: map[string]string{"a.json": ordersSpec, "b.json": ordersSpec}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpecs(writeSpecs(t, tt.files))
+			require.ErrorIs(t, err, ErrInvalidSpec)
+		})
+	}
+
+	_, err := LoadSpecs(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestHandler_MigratePlan(t *testing.T) {
+	mockClient := newSchemaClient()
+	handler := newMockHandler(mockClient)
+	dir := writeSpecs(t, map[string]string{"01_products.yaml": productsSpec, "02_orders.json": ordersSpec})
+
+	plan, err := handler.Migrate(context.Background(), MigrateArgs{Dir: dir})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Steps, 2)
+	assert.Equal(t, ActionCreate, plan.Steps[0].Action)
+	assert.Equal(t, []string{
+		"CREATE TABLE products (title text, price float, embedding float_vector knn_type='hnsw' knn_dims='4' hnsw_similarity='COSINE') morphology='stem_en'",
+	}, plan.Steps[0].Statements)
+	assert.Equal(t, ActionAlter, plan.Steps[1].Action)
+	assert.Equal(t, []string{"ALTER TABLE shop:orders ADD COLUMN status string"}, plan.Steps[1].Statements)
+	assert.Equal(t, 2, plan.Statements)
+	assert.False(t, plan.Applied)
+
+	// Planning only reads the schema
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		assert.NotRegexp(t, "^(CREATE|ALTER|INSERT)", call.Query)
+	}
+
+	text := FormatPlan(plan)
+	assert.Contains(t, text, "01_products.yaml: create products\n")
+	assert.Contains(t, text, "02_orders.json: alter orders (cluster shop)\n  ALTER TABLE shop:orders ADD COLUMN status string;\n")
+	assert.Contains(t, text, "2 statements to apply\n")
+}
+
+func TestHandler_MigrateApply(t *testing.T) {
+	mockClient := newSchemaClient()
+	handler := newMockHandler(mockClient)
+	dir := writeSpecs(t, map[string]string{"02_orders.json": ordersSpec})
+
+	plan, err := handler.Migrate(context.Background(), MigrateArgs{Dir: dir, Apply: true, HistoryTable: "schema_history"})
+	require.NoError(t, err)
+	assert.True(t, plan.Applied)
+	assert.True(t, plan.Steps[0].Applied)
+
+	var writes []string
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		if !strings.HasPrefix(call.Query, "SHOW") && !strings.HasPrefix(call.Query, "DESCRIBE") {
+			writes = append(writes, call.Query)
+		}
+	}
+	require.Len(t, writes, 3)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS schema_history (file string, table_name string, action string, checksum string, statements json, applied_at timestamp)", writes[0])
+	assert.Equal(t, "ALTER TABLE shop:orders ADD COLUMN status string", writes[1])
+	assert.Regexp(t, `^INSERT INTO schema_history \(file, table_name, action, checksum, statements, applied_at\) VALUES \('02_orders.json', 'orders', 'alter', '[0-9a-f]{64}', '\["ALTER TABLE shop:orders ADD COLUMN status string"\]', 1700000000\)$`, writes[2])
+	assert.Contains(t, FormatPlan(plan), "Applied 1 statements\n")
+}
+
+func TestHandler_MigrateUpToDate(t *testing.T) {
+	mockClient := newSchemaClient()
+	handler := newMockHandler(mockClient)
+	dir := writeSpecs(t, map[string]string{"orders.json": `{"table": "orders", "columns": [{"name": "total", "type": "float"}]}`})
+
+	plan, err := handler.Migrate(context.Background(), MigrateArgs{Dir: dir, Apply: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, ActionNone, plan.Steps[0].Action)
+	assert.Empty(t, plan.Steps[0].Statements)
+	assert.Equal(t, "orders.json: unchanged orders\nSchema is up to date\n", FormatPlan(plan))
+	// Nothing to apply creates no history table
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		assert.NotContains(t, call.Query, DefaultHistoryTable)
+	}
+}
@@ -0,0 +1,299 @@
+package rawsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/client"
+)
+
+var (
+	ErrStatementNotAllowed = errors.New("statement type is not allowed")
+	ErrMultipleStatements  = errors.New("multiple statements are not allowed")
+	ErrWriteNotAllowed     = errors.New("write statements are not allowed")
+)
+
+// DefaultAllowedStatements are the statement types accepted when no allowlist is configured
+var DefaultAllowedStatements = []string{"SELECT", "SHOW", "DESCRIBE", "CALL"}
+
+// Handler handles raw SQL statements checked against a policy
+type Handler struct {
+	client client.ManticoreClient
+	logger *slog.Logger
+}
+
+// NewHandler creates a new raw SQL handler
+func NewHandler(c client.ManticoreClient, logger *slog.Logger) *Handler {
+	return &Handler{
+		client: c,
+		logger: logger,
+	}
+}
+
+// Args represents arguments for execute_sql tool
+type Args struct {
+	Query string `json:"query" jsonschema:"required" description:"SQL statement to execute, e.g. SELECT, SHOW, DESCRIBE or CALL"`
+}
+
+// Policy restricts the statements a raw SQL call may run. DenyWrites rejects statements
+// classified as writes even when their type is allowed, e.g. SET GLOBAL.
+type Policy struct {
+	AllowedStatements []string
+	AllowMultiple     bool
+	DenyWrites        bool
+	MaxRows           int
+}
+
+// Statement describes a statement as it was sent to Manticore
+type Statement struct {
+	SQL         string `json:"sql"`
+	Type        string `json:"type"`
+	Class       string `json:"class"`
+	LimitCapped bool   `json:"limit_capped,omitempty"`
+}
+
+// Result holds the executed statements and every result set they returned
+type Result struct {
+	Statements []Statement        `json:"statements"`
+	ResultSets []client.SQLResult `json:"result_sets"`
+}
+
+// Execute classifies the statements of a query, checks them against the policy, caps the
+// LIMIT of SELECT statements and returns all result sets with their columns
+func (h *Handler) Execute(ctx context.Context, args Args, policy Policy) (*Result, error) {
+	statements, err := Prepare(args.Query, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		queries = append(queries, statement.SQL)
+	}
+	query := strings.Join(queries, "; ")
+
+	h.logger.Debug("Executing raw SQL", "sql", query)
+
+	results, err := h.client.ExecuteSQLResults(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("SQL execution failed: %w", err)
+	}
+
+	return &Result{
+		Statements: statements,
+		ResultSets: results,
+	}, nil
+}
+
+// Prepare splits a query into statements, rejects comments that change the statements,
+// statement types outside the allowlist,
+// writes when denied and several statements unless allowed, and caps the LIMIT of SELECT
+// statements at MaxRows
+func Prepare(query string, policy Policy) ([]Statement, error) {
+	// The allowlist only holds when Manticore splits the query like the classifier does
+	if err := client.CheckComments(query); err != nil {
+		return nil, err
+	}
+
+	parts := client.SplitStatements(query)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("query parameter is required")
+	}
+	if len(parts) > 1 && !policy.AllowMultiple {
+		return nil, fmt.Errorf("%w: got %d statements", ErrMultipleStatements, len(parts))
+	}
+
+	allowed := policy.AllowedStatements
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedStatements
+	}
+
+	statements := make([]Statement, 0, len(parts))
+	for _, part := range parts {
+		statementType := client.StatementType(part)
+		if !isAllowed(statementType, allowed) {
+			return nil, fmt.Errorf("%w: %s (allowed: %s)", ErrStatementNotAllowed, statementType, strings.Join(allowed, ", "))
+		}
+
+		class := client.ClassifyStatement(part)
+		if class == client.StatementWrite && policy.DenyWrites {
+			return nil, fmt.Errorf("%w: %s", ErrWriteNotAllowed, statementType)
+		}
+
+		statement := Statement{
+			SQL:   part,
+			Type:  statementType,
+			Class: class.String(),
+		}
+		if statementType == "SELECT" && policy.MaxRows > 0 {
+			statement.SQL, statement.LimitCapped = CapLimit(part, policy.MaxRows)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// ReadStatements returns the statement types of an allowlist that are not writes
+func ReadStatements(allowed []string) []string {
+	read := make([]string, 0, len(allowed))
+	for _, name := range allowed {
+		if client.ClassifyStatement(name) == client.StatementRead {
+			read = append(read, name)
+		}
+	}
+	return read
+}
+
+// isAllowed reports whether a statement type is in the allowlist
+func isAllowed(statementType string, allowed []string) bool {
+	for _, name := range allowed {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "DESC" {
+			name = "DESCRIBE"
+		}
+		if name == statementType {
+			return true
+		}
+	}
+	return false
+}
+
+// CapLimit lowers every top-level LIMIT of a SELECT statement above maxRows to maxRows and
+// adds LIMIT maxRows when the statement has none. It reports whether the statement changed.
+func CapLimit(statement string, maxRows int) (string, bool) {
+	limit := strconv.Itoa(maxRows)
+
+	// Replacements of statement[start:end], in statement order
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	tokens, codeEnd := scanTokens(statement)
+	hasLimit, clauseStart := false, -1
+	for i, token := range tokens {
+		switch strings.ToUpper(token.text) {
+		case "OPTION", "FACET":
+			// The LIMIT of the query itself precedes OPTION and FACET clauses
+			if clauseStart < 0 {
+				clauseStart = token.start
+			}
+		case "LIMIT":
+			if clauseStart < 0 {
+				hasLimit = true
+			}
+			// LIMIT count or LIMIT offset, count
+			countIndex := i + 1
+			if countIndex+2 < len(tokens) && tokens[countIndex+1].text == "," {
+				countIndex += 2
+			}
+			if countIndex >= len(tokens) {
+				continue
+			}
+			count := tokens[countIndex]
+			if value, err := strconv.Atoi(count.text); err == nil && value > maxRows {
+				edits = append(edits, edit{start: count.start, end: count.end, text: limit})
+			}
+		}
+	}
+
+	if !hasLimit {
+		if clauseStart < 0 {
+			// Appended after the last code, a trailing line comment would swallow it otherwise
+			edits = append(edits, edit{start: codeEnd, end: codeEnd, text: " LIMIT " + limit})
+		} else {
+			// Inserted before every later edit, which all belong to FACET clauses
+			edits = append([]edit{{start: clauseStart, end: clauseStart, text: "LIMIT " + limit + " "}}, edits...)
+		}
+	}
+	if len(edits) == 0 {
+		return statement, false
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, e := range edits {
+		out.WriteString(statement[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.WriteString(statement[last:])
+	return out.String(), true
+}
+
+// token is a word, number or punctuation character at parenthesis depth zero
+type token struct {
+	text       string
+	start, end int
+}
+
+// scanTokens returns the top-level tokens of a statement, skipping string literals, quoted
+// identifiers, comments and everything inside parentheses, and the offset after the last
+// character that is neither whitespace nor part of a comment
+func scanTokens(statement string) ([]token, int) {
+	var tokens []token
+	depth, codeEnd := 0, 0
+	for i := 0; i < len(statement); i++ {
+		ch := statement[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			continue
+		case ch == '\'' || ch == '"' || ch == '`':
+			i = skipQuoted(statement, i)
+		case strings.HasPrefix(statement[i:], "/*"):
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(statement)
+			}
+			continue
+		case ch == '#' || strings.HasPrefix(statement[i:], "--"):
+			if end := strings.IndexByte(statement[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(statement)
+			}
+			continue
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case isWordChar(ch):
+			start := i
+			for i+1 < len(statement) && isWordChar(statement[i+1]) {
+				i++
+			}
+			if depth == 0 {
+				tokens = append(tokens, token{text: statement[start : i+1], start: start, end: i + 1})
+			}
+		case ch == ',' && depth == 0:
+			tokens = append(tokens, token{text: ",", start: i, end: i + 1})
+		}
+		codeEnd = min(i+1, len(statement))
+	}
+	return tokens, codeEnd
+}
+
+// isWordChar reports whether ch belongs to a keyword, identifier or number
+func isWordChar(ch byte) bool {
+	return ch == '_' || ch == '.' || (ch >= '0' && ch <= '9') || (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z')
+}
+
+// skipQuoted returns the index of the quote closing the literal that starts at i
+func skipQuoted(statement string, i int) int {
+	quote := statement[i]
+	for i++; i < len(statement); i++ {
+		switch statement[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(statement)
+}
@@ -0,0 +1,151 @@
+package rawsql
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+		changed   bool
+	}{
+		{name: "no limit", statement: "SELECT * FROM t", expected: "SELECT * FROM t LIMIT 100", changed: true},
+		{name: "limit below cap", statement: "SELECT * FROM t LIMIT 5", expected: "SELECT * FROM t LIMIT 5"},
+		{name: "limit above cap", statement: "SELECT * FROM t LIMIT 5000", expected: "SELECT * FROM t LIMIT 100", changed: true},
+		{name: "offset and count", statement: "select * from t limit 20, 1000", expected: "select * from t limit 20, 100", changed: true},
+		{name: "limit with offset keyword", statement: "SELECT * FROM t LIMIT 500 OFFSET 10", expected: "SELECT * FROM t LIMIT 100 OFFSET 10", changed: true},
+		{
+			name:      "before option",
+			statement: "SELECT * FROM t WHERE MATCH('limit 1000') OPTION max_matches=5000",
+			expected:  "SELECT * FROM t WHERE MATCH('limit 1000') LIMIT 100 OPTION max_matches=5000",
+			changed:   true,
+		},
+		{
+			name:      "facets",
+			statement: "SELECT * FROM t LIMIT 10 FACET brand LIMIT 500",
+			expected:  "SELECT * FROM t LIMIT 10 FACET brand LIMIT 100",
+			changed:   true,
+		},
+		{
+			name:      "facets without main limit",
+			statement: "SELECT * FROM t FACET brand LIMIT 500",
+			expected:  "SELECT * FROM t LIMIT 100 FACET brand LIMIT 100",
+			changed:   true,
+		},
+		{
+			name:      "subselect",
+			statement: "SELECT * FROM (SELECT * FROM t LIMIT 5000) ORDER BY id ASC",
+			expected:  "SELECT * FROM (SELECT * FROM t LIMIT 5000) ORDER BY id ASC LIMIT 100",
+			changed:   true,
+		},
+		{name: "trailing comment", statement: "SELECT * FROM t -- all rows", expected: "SELECT * FROM t LIMIT 100 -- all rows", changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capped, changed := CapLimit(tt.statement, 100)
+			assert.Equal(t, tt.expected, capped)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	policy := Policy{MaxRows: 50}
+
+	statements, err := Prepare("desc products", policy)
+	require.NoError(t, err)
+	assert.Equal(t, []Statement{{SQL: "desc products", Type: "DESCRIBE", Class: "read"}}, statements)
+
+	statements, err = Prepare("SELECT id FROM products", policy)
+	require.NoError(t, err)
+	assert.Equal(t, []Statement{{SQL: "SELECT id FROM products LIMIT 50", Type: "SELECT", Class: "read", LimitCapped: true}}, statements)
+
+	_, err = Prepare("DELETE FROM products WHERE id=1", policy)
+	require.ErrorIs(t, err, ErrStatementNotAllowed)
+
+	_, err = Prepare("SET GLOBAL query_log_format=sphinxql", policy)
+	require.ErrorIs(t, err, ErrStatementNotAllowed)
+
+	_, err = Prepare("SELECT 1; SHOW META", policy)
+	require.ErrorIs(t, err, ErrMultipleStatements)
+
+	statements, err = Prepare("SELECT 1; SHOW META", Policy{AllowMultiple: true})
+	require.NoError(t, err)
+	assert.Len(t, statements, 2)
+
+	// Every statement is checked when several are allowed
+	_, err = Prepare("SELECT 1; DROP TABLE products", Policy{AllowMultiple: true})
+	require.ErrorIs(t, err, ErrStatementNotAllowed)
+
+	statements, err = Prepare("UPDATE products SET price=1 WHERE id=1", Policy{AllowedStatements: []string{"select", "update"}})
+	require.NoError(t, err)
+	assert.Equal(t, "write", statements[0].Class)
+
+	// Denied writes are rejected even when their statement type is allowed
+	_, err = Prepare("SET GLOBAL query_log_format=sphinxql", Policy{AllowedStatements: []string{"SET"}, DenyWrites: true})
+	require.ErrorIs(t, err, ErrWriteNotAllowed)
+
+	statements, err = Prepare("SET profiling=1", Policy{AllowedStatements: []string{"SET"}, DenyWrites: true})
+	require.NoError(t, err)
+	assert.Equal(t, "read", statements[0].Class)
+
+	_, err = Prepare("SELECT 5 # note; DROP TABLE products", policy)
+	require.ErrorIs(t, err, client.ErrAmbiguousComments)
+
+	_, err = Prepare("SELECT 5--1; DROP TABLE products", Policy{AllowMultiple: true})
+	require.ErrorIs(t, err, ErrStatementNotAllowed)
+
+	_, err = Prepare(" ; ", policy)
+	require.Error(t, err)
+}
+
+func TestReadStatements(t *testing.T) {
+	assert.Equal(t, []string{"select", "SHOW", "SET"}, ReadStatements([]string{"select", "INSERT", "SHOW", "delete", "SET", "DROP"}))
+	assert.Empty(t, ReadStatements([]string{"UPDATE"}))
+}
+
+func TestHandler_Execute(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			return []client.SQLResult{
+				{
+					Columns: []client.SQLColumn{{Name: "id", Type: "long long"}},
+					Rows:    []map[string]interface{}{{"id": float64(1)}},
+					Total:   1,
+				},
+				{
+					Columns: []client.SQLColumn{{Name: "Variable_name", Type: "string"}, {Name: "Value", Type: "string"}},
+					Rows:    []map[string]interface{}{{"Variable_name": "total", "Value": "1"}},
+					Total:   1,
+				},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{Query: "SELECT id FROM products; SHOW META"}, Policy{AllowMultiple: true, MaxRows: 10})
+	require.NoError(t, err)
+
+	calls := mockClient.ExecuteSQLResultsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "SELECT id FROM products LIMIT 10; SHOW META", calls[0].Query)
+
+	require.Len(t, result.Statements, 2)
+	require.Len(t, result.ResultSets, 2)
+	assert.Equal(t, "id", result.ResultSets[0].Columns[0].Name)
+	assert.Equal(t, "SHOW", result.Statements[1].Type)
+}
@@ -0,0 +1,301 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"manticore-mcp-server/client"
+)
+
+// ErrInvalidExplain is returned for searches that cannot be explained
+var ErrInvalidExplain = errors.New("invalid explain")
+
+// ExplainResult holds the profile, the execution plan and the parsed query tree of a search
+type ExplainResult struct {
+	SQL         string         `json:"sql"`
+	Hits        int            `json:"hits"`
+	Total       int            `json:"total"`
+	QueryTimeMs float64        `json:"query_time_ms"`
+	Keywords    []KeywordStat  `json:"keywords,omitempty"`
+	Profile     []ProfileStage `json:"profile,omitempty"`
+	Plan        *QueryPlan     `json:"plan,omitempty"`
+	QueryTree   *QueryPlan     `json:"query_tree,omitempty"`
+}
+
+// ProfileStage is a single row of SHOW PROFILE
+type ProfileStage struct {
+	Status     string  `json:"status"`
+	DurationMs float64 `json:"duration_ms"`
+	Switches   int     `json:"switches"`
+	Percent    float64 `json:"percent"`
+}
+
+// QueryPlan is a transformed query tree reported by SHOW PLAN or EXPLAIN QUERY. Raw keeps
+// the original text when it cannot be parsed.
+type QueryPlan struct {
+	Root *PlanNode `json:"root,omitempty"`
+	Raw  string    `json:"raw"`
+}
+
+// PlanNode is an operator of a query tree such as AND, PHRASE or KEYWORD. Keywords carry
+// their word in Value, settings like querypos=1 are collected in Options.
+type PlanNode struct {
+	Type     string            `json:"type"`
+	Value    string            `json:"value,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+	Children []*PlanNode       `json:"children,omitempty"`
+}
+
+// Explain runs the SQL query search would run with profiling enabled and returns
+// SHOW PROFILE, SHOW PLAN, EXPLAIN QUERY and SHOW META parsed into a structured result
+func (h *Handler) Explain(ctx context.Context, args Args) (*ExplainResult, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("table parameter is required")
+	}
+	switch {
+	case args.Hybrid != nil:
+		return nil, fmt.Errorf("%w: hybrid searches run two queries, explain the query and knn separately", ErrInvalidExplain)
+	case args.UseHTTP || args.BoolQuery != nil:
+		return nil, fmt.Errorf("%w: only supported for SQL searches", ErrInvalidExplain)
+	case args.Query == "" && len(args.Where) == 0 && args.Filter == nil && args.KNN == nil:
+		return nil, fmt.Errorf("query parameter is required for SQL search when no WHERE conditions are provided")
+	}
+
+	// Same defaults as executeSQLQuery, so the explained query is the one search runs
+	if args.Limit <= 0 {
+		args.Limit = 10
+	}
+	if args.MatchMode == "" {
+		args.MatchMode = "extended"
+	}
+	if args.TimeoutMs > 0 && (args.MaxQueryTime <= 0 || args.MaxQueryTime > args.TimeoutMs) {
+		args.MaxQueryTime = args.TimeoutMs
+	}
+
+	sql, err := h.buildSQL(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+	if len(client.SplitStatements(sql)) != 1 {
+		return nil, ErrMultipleStatements
+	}
+
+	// Profiling is enabled per session, so all statements go in one request
+	statements := []string{"SET profiling=1", sql, "SHOW META", "SHOW PROFILE", "SHOW PLAN"}
+	if args.Query != "" {
+		statements = append(statements, "EXPLAIN QUERY "+args.Table+" '"+escapeString(args.Query)+"'")
+	}
+
+	h.logger.Debug("Executing explain query", "sql", sql)
+
+	results, err := h.client.ExecuteSQLResults(ctx, strings.Join(statements, "; "))
+	if err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+
+	// The search and its facets precede the statements appended after it
+	metaIndex := 2 + len(args.Facets)
+	expected := metaIndex + len(statements) - 2
+	if len(results) < expected {
+		return nil, fmt.Errorf("explain failed: expected %d result sets, got %d", expected, len(results))
+	}
+	for _, result := range results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("explain failed: %s", result.Error)
+		}
+	}
+
+	explain := &ExplainResult{
+		SQL:   sql,
+		Hits:  len(results[1].Rows),
+		Total: len(results[1].Rows),
+	}
+
+	meta := &Result{}
+	h.applyMeta(meta, ParseVariables(results[metaIndex].Rows))
+	if meta.Total > 0 {
+		explain.Total = meta.Total
+	}
+	explain.QueryTimeMs = meta.QueryTimeMs
+	explain.Keywords = meta.Keywords
+
+	explain.Profile = parseProfile(results[metaIndex+1].Rows)
+	explain.Plan = parsePlanRows(results[metaIndex+2].Rows)
+	if args.Query != "" {
+		explain.QueryTree = parsePlanRows(results[metaIndex+3].Rows)
+	}
+
+	return explain, nil
+}
+
+// parseProfile converts SHOW PROFILE rows into stages with durations in milliseconds
+func parseProfile(rows []map[string]interface{}) []ProfileStage {
+	stages := make([]ProfileStage, 0, len(rows))
+	for _, row := range rows {
+		status, _ := row["Status"].(string)
+		duration, _ := toFloat(row["Duration"])
+		switches, _ := toFloat(row["Switches"])
+		percent, _ := toFloat(row["Percent"])
+		stages = append(stages, ProfileStage{
+			Status:     status,
+			DurationMs: duration * 1000,
+			Switches:   int(switches),
+			Percent:    percent,
+		})
+	}
+	return stages
+}
+
+// parsePlanRows extracts the transformed tree of SHOW PLAN or EXPLAIN QUERY
+func parsePlanRows(rows []map[string]interface{}) *QueryPlan {
+	var raw string
+	for _, row := range rows {
+		value, _ := row["Value"].(string)
+		if row["Variable"] == "transformed_tree" {
+			raw = value
+			break
+		}
+		if raw == "" {
+			raw = value
+		}
+	}
+	if raw == "" {
+		return nil
+	}
+
+	plan := &QueryPlan{Raw: raw}
+	if root, err := ParsePlan(raw); err == nil {
+		plan.Root = root
+	}
+	return plan
+}
+
+// ParsePlan parses a transformed query tree such as
+// AND(KEYWORD(hello, querypos=1), PHRASE(KEYWORD(big, querypos=2), KEYWORD(data, querypos=3)))
+func ParsePlan(text string) (*PlanNode, error) {
+	p := &planParser{input: text}
+	node, err := p.parseNode()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos:], p.pos)
+	}
+	return node, nil
+}
+
+// planParser is a recursive descent parser of transformed query trees
+type planParser struct {
+	input string
+	pos   int
+}
+
+// parseNode parses an operator with its parenthesized arguments
+func (p *planParser) parseNode() (*PlanNode, error) {
+	p.skipSpace()
+	name := p.readName()
+	if name == "" {
+		return nil, fmt.Errorf("expected operator at offset %d", p.pos)
+	}
+	if p.skipSpace(); !p.consume('(') {
+		return nil, fmt.Errorf("expected ( after %s at offset %d", name, p.pos)
+	}
+
+	node := &PlanNode{Type: name}
+	for {
+		p.skipSpace()
+		if p.consume(')') {
+			return node, nil
+		}
+		if p.pos >= len(p.input) {
+			return nil, fmt.Errorf("unterminated %s", name)
+		}
+
+		if p.nextIsNode() {
+			child, err := p.parseNode()
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+		} else {
+			argument := strings.TrimSpace(p.readArgument())
+			if key, value, ok := strings.Cut(argument, "="); ok {
+				if node.Options == nil {
+					node.Options = make(map[string]string)
+				}
+				node.Options[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			} else if node.Value == "" {
+				node.Value = argument
+			} else {
+				node.Value += ", " + argument
+			}
+		}
+
+		p.skipSpace()
+		p.consume(',')
+	}
+}
+
+// nextIsNode reports whether the input continues with an operator name followed by (
+func (p *planParser) nextIsNode() bool {
+	start := p.pos
+	defer func() { p.pos = start }()
+
+	if p.readName() == "" {
+		return false
+	}
+	p.skipSpace()
+	return p.pos < len(p.input) && p.input[p.pos] == '('
+}
+
+// readName reads an operator name
+func (p *planParser) readName() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// readArgument reads a plain argument up to the next top level comma or closing parenthesis
+func (p *planParser) readArgument() string {
+	start, depth := p.pos, 0
+	for ; p.pos < len(p.input); p.pos++ {
+		switch p.input[p.pos] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return p.input[start:p.pos]
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return p.input[start:p.pos]
+			}
+		}
+	}
+	return p.input[start:]
+}
+
+// consume skips c if it is the next character
+func (p *planParser) consume(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace including the line breaks of indented trees
+func (p *planParser) skipSpace() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlan(t *testing.T) {
+	tree := `AND(
+  AND(KEYWORD(big, querypos=1)),
+  PHRASE(KEYWORD(data, querypos=2), KEYWORD(lake, querypos=3, expanded)),
+  NOT(KEYWORD(cloud, querypos=4)))`
+
+	root, err := ParsePlan(tree)
+	require.NoError(t, err)
+
+	assert.Equal(t, &PlanNode{
+		Type: "AND",
+		Children: []*PlanNode{
+			{Type: "AND", Children: []*PlanNode{
+				{Type: "KEYWORD", Value: "big", Options: map[string]string{"querypos": "1"}},
+			}},
+			{Type: "PHRASE", Children: []*PlanNode{
+				{Type: "KEYWORD", Value: "data", Options: map[string]string{"querypos": "2"}},
+				{Type: "KEYWORD", Value: "lake, expanded", Options: map[string]string{"querypos": "3"}},
+			}},
+			{Type: "NOT", Children: []*PlanNode{
+				{Type: "KEYWORD", Value: "cloud", Options: map[string]string{"querypos": "4"}},
+			}},
+		},
+	}, root)
+}
+
+func TestParsePlan_Invalid(t *testing.T) {
+	for _, tree := range []string{"", "AND(KEYWORD(a, querypos=1)", "KEYWORD", "AND() trailing"} {
+		_, err := ParsePlan(tree)
+		assert.Error(t, err, tree)
+	}
+}
+
+func TestHandler_Explain(t *testing.T) {
+	var sql string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			sql = query
+			return []client.SQLResult{
+				{},
+				{Rows: []map[string]interface{}{{"id": float64(1)}, {"id": float64(2)}}},
+				{Rows: []map[string]interface{}{{"brand_id": float64(3), "count(*)": float64(2)}}},
+				{Rows: []map[string]interface{}{
+					{"Variable_name": "total_found", "Value": "2"},
+					{"Variable_name": "time", "Value": "0.003"},
+					{"Variable_name": "keyword[0]", "Value": "laptop"},
+					{"Variable_name": "docs[0]", "Value": "2"},
+					{"Variable_name": "hits[0]", "Value": "5"},
+				}},
+				{Rows: []map[string]interface{}{
+					{"Status": "read_docs", "Duration": "0.000200", "Switches": "5", "Percent": "40.00"},
+					{"Status": "total", "Duration": "0.000500", "Switches": "12", "Percent": "100.00"},
+				}},
+				{Rows: []map[string]interface{}{{"Variable": "transformed_tree", "Value": "AND(KEYWORD(laptop, querypos=1))"}}},
+				{Rows: []map[string]interface{}{{"Variable": "transformed_tree", "Value": "AND(KEYWORD(laptop, querypos=1))"}}},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	args := Args{
+		Table:  "products",
+		Query:  "laptop's",
+		Facets: []FacetOptions{{Field: "brand_id"}},
+	}
+	result, err := handler.Explain(context.Background(), args)
+	require.NoError(t, err)
+
+	// The explained query is exactly the one search builds
+	args.Limit, args.MatchMode = 10, "extended"
+	expected, err := handler.buildSQL(args)
+	require.NoError(t, err)
+	assert.Equal(t, expected, result.SQL)
+	assert.Equal(t, "SET profiling=1; "+expected+"; SHOW META; SHOW PROFILE; SHOW PLAN; EXPLAIN QUERY products 'laptop\\'s'", sql)
+
+	assert.Equal(t, 2, result.Hits)
+	assert.Equal(t, 2, result.Total)
+	assert.InDelta(t, 3.0, result.QueryTimeMs, 0.001)
+	assert.Equal(t, []KeywordStat{{Keyword: "laptop", Docs: 2, Hits: 5}}, result.Keywords)
+
+	require.Len(t, result.Profile, 2)
+	assert.Equal(t, "read_docs", result.Profile[0].Status)
+	assert.InDelta(t, 0.2, result.Profile[0].DurationMs, 1e-9)
+	assert.Equal(t, 5, result.Profile[0].Switches)
+	assert.InDelta(t, 40.0, result.Profile[0].Percent, 0)
+
+	require.NotNil(t, result.Plan)
+	require.NotNil(t, result.Plan.Root)
+	assert.Equal(t, "KEYWORD", result.Plan.Root.Children[0].Type)
+	require.NotNil(t, result.QueryTree)
+	assert.Equal(t, "AND(KEYWORD(laptop, querypos=1))", result.QueryTree.Raw)
+}
+
+func TestHandler_ExplainInvalid(t *testing.T) {
+	handler := NewHandler(&client.ManticoreClientMock{}, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+	knn := &KNNOptions{Field: "embedding", K: 10, Vector: []float64{0.1}}
+
+	for _, args := range []Args{
+		{Table: "docs", Query: "q", UseHTTP: true},
+		{Table: "docs", Query: "q", KNN: knn, Hybrid: &HybridOptions{}},
+	} {
+		_, err := handler.Explain(context.Background(), args)
+		require.ErrorIs(t, err, ErrInvalidExplain)
+	}
+}
@@ -0,0 +1,282 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/client"
+)
+
+// ErrInvalidFacet is returned for facet options that cannot be rendered
+var ErrInvalidFacet = errors.New("invalid facet")
+
+// Facet orders
+const (
+	FacetOrderCountDesc = "count_desc"
+	FacetOrderCountAsc  = "count_asc"
+	FacetOrderValueAsc  = "value_asc"
+	FacetOrderValueDesc = "value_desc"
+)
+
+// defaultFacetLimit matches the number of buckets Manticore returns per facet by default
+const defaultFacetLimit = 20
+
+// FacetOptions describes a facet computed over the documents matched by a search.
+// A facet groups by the attribute values, by range boundaries or by histogram buckets.
+type FacetOptions struct {
+	Field    string    `json:"field" jsonschema:"required" description:"Attribute to build buckets for"`
+	Name     string    `json:"name,omitempty" description:"Name of the facet in the response (default: field)"`
+	Limit    int       `json:"limit,omitempty" description:"Maximum number of buckets (default: 20)"`
+	Order    string    `json:"order,omitempty" jsonschema:"enum=count_desc,enum=count_asc,enum=value_asc,enum=value_desc" description:"Bucket order (default: count_desc)"`
+	Ranges   []float64 `json:"ranges,omitempty" description:"Ascending range boundaries, e.g. [100, 500] yields buckets below 100, 100 to 500 and from 500"`
+	Interval float64   `json:"interval,omitempty" description:"Histogram bucket width for numeric attributes"`
+}
+
+// Facet holds the buckets of one facet
+type Facet struct {
+	Name    string        `json:"name"`
+	Field   string        `json:"field"`
+	Buckets []FacetBucket `json:"buckets"`
+}
+
+// FacetBucket is a single facet value with the number of matching documents. Range buckets
+// carry their bounds, the lower bound is inclusive.
+type FacetBucket struct {
+	Value interface{} `json:"value"`
+	Count int         `json:"count"`
+	From  *float64    `json:"from,omitempty"`
+	To    *float64    `json:"to,omitempty"`
+}
+
+// facetName returns the response name of a facet
+func facetName(facet FacetOptions) string {
+	if facet.Name != "" {
+		return facet.Name
+	}
+	return facet.Field
+}
+
+// validateFacets checks facet fields, names, limits, orders and bucket definitions
+func validateFacets(facets []FacetOptions) error {
+	names := make(map[string]bool, len(facets))
+	for _, facet := range facets {
+		if err := ValidateName(facet.Field); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFacet, err)
+		}
+		name := facetName(facet)
+		if err := ValidateName(name); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFacet, err)
+		}
+		if names[name] {
+			return fmt.Errorf("%w: duplicate facet name %q", ErrInvalidFacet, name)
+		}
+		names[name] = true
+
+		if facet.Limit < 0 {
+			return fmt.Errorf("%w: limit must not be negative", ErrInvalidFacet)
+		}
+		switch facet.Order {
+		case "", FacetOrderCountDesc, FacetOrderCountAsc, FacetOrderValueAsc, FacetOrderValueDesc:
+		default:
+			return fmt.Errorf("%w: unsupported order %q", ErrInvalidFacet, facet.Order)
+		}
+
+		if len(facet.Ranges) > 0 && facet.Interval != 0 {
+			return fmt.Errorf("%w: ranges and interval are mutually exclusive", ErrInvalidFacet)
+		}
+		if facet.Interval < 0 || math.IsNaN(facet.Interval) || math.IsInf(facet.Interval, 0) {
+			return fmt.Errorf("%w: interval must be a positive number", ErrInvalidFacet)
+		}
+		for i, bound := range facet.Ranges {
+			if math.IsNaN(bound) || math.IsInf(bound, 0) {
+				return fmt.Errorf("%w: range boundary %v", ErrInvalidFacet, bound)
+			}
+			if i > 0 && bound <= facet.Ranges[i-1] {
+				return fmt.Errorf("%w: range boundaries must be ascending", ErrInvalidFacet)
+			}
+		}
+	}
+	return nil
+}
+
+// BuildFacetSQL renders the FACET clauses appended to a SQL search
+func BuildFacetSQL(facets []FacetOptions) (string, error) {
+	if err := validateFacets(facets); err != nil {
+		return "", err
+	}
+
+	clauses := make([]string, 0, len(facets))
+	for _, facet := range facets {
+		var sql strings.Builder
+		sql.WriteString("FACET ")
+
+		switch {
+		case len(facet.Ranges) > 0:
+			sql.WriteString("INTERVAL(")
+			sql.WriteString(facet.Field)
+			for _, bound := range facet.Ranges {
+				sql.WriteString(",")
+				sql.WriteString(formatNumber(bound))
+			}
+			sql.WriteString(")")
+		case facet.Interval > 0:
+			sql.WriteString("HISTOGRAM(")
+			sql.WriteString(facet.Field)
+			sql.WriteString(", {hist_interval=")
+			sql.WriteString(formatNumber(facet.Interval))
+			sql.WriteString("})")
+		default:
+			sql.WriteString(facet.Field)
+		}
+		sql.WriteString(" AS ")
+		sql.WriteString(facetName(facet))
+
+		switch facet.Order {
+		case FacetOrderCountAsc:
+			sql.WriteString(" ORDER BY COUNT(*) ASC")
+		case FacetOrderValueAsc:
+			sql.WriteString(" ORDER BY FACET() ASC")
+		case FacetOrderValueDesc:
+			sql.WriteString(" ORDER BY FACET() DESC")
+		default:
+			sql.WriteString(" ORDER BY COUNT(*) DESC")
+		}
+
+		sql.WriteString(" LIMIT ")
+		sql.WriteString(strconv.Itoa(facetLimit(facet)))
+
+		clauses = append(clauses, sql.String())
+	}
+	return strings.Join(clauses, " "), nil
+}
+
+// BuildFacetAggs renders facets as aggs of a JSON search
+func BuildFacetAggs(facets []FacetOptions) (map[string]interface{}, error) {
+	if err := validateFacets(facets); err != nil {
+		return nil, err
+	}
+
+	aggs := make(map[string]interface{}, len(facets))
+	for _, facet := range facets {
+		agg := make(map[string]interface{})
+
+		switch {
+		case len(facet.Ranges) > 0:
+			ranges := make([]map[string]interface{}, 0, len(facet.Ranges)+1)
+			for i := 0; i <= len(facet.Ranges); i++ {
+				from, to := rangeBounds(facet.Ranges, i)
+				bucket := make(map[string]interface{}, 2)
+				if from != nil {
+					bucket["from"] = *from
+				}
+				if to != nil {
+					bucket["to"] = *to
+				}
+				ranges = append(ranges, bucket)
+			}
+			agg["range"] = map[string]interface{}{"field": facet.Field, "ranges": ranges}
+		case facet.Interval > 0:
+			agg["histogram"] = map[string]interface{}{"field": facet.Field, "interval": facet.Interval}
+		default:
+			agg["terms"] = map[string]interface{}{"field": facet.Field, "size": facetLimit(facet)}
+			switch facet.Order {
+			case FacetOrderCountAsc:
+				agg["sort"] = []map[string]interface{}{{"count(*)": map[string]string{"order": "asc"}}}
+			case FacetOrderValueAsc:
+				agg["sort"] = []map[string]interface{}{{facet.Field: map[string]string{"order": "asc"}}}
+			case FacetOrderValueDesc:
+				agg["sort"] = []map[string]interface{}{{facet.Field: map[string]string{"order": "desc"}}}
+			}
+		}
+
+		aggs[facetName(facet)] = agg
+	}
+	return aggs, nil
+}
+
+// parseFacetSQL converts a FACET result set into buckets. The first column holds the
+// facet value and count(*) the number of documents.
+func parseFacetSQL(facet FacetOptions, result client.SQLResult) Facet {
+	parsed := Facet{
+		Name:    facetName(facet),
+		Field:   facet.Field,
+		Buckets: make([]FacetBucket, 0, len(result.Rows)),
+	}
+
+	valueColumn := facetName(facet)
+	if len(result.Columns) > 0 {
+		valueColumn = result.Columns[0].Name
+	}
+
+	for _, row := range result.Rows {
+		count, _ := toFloat(row["count(*)"])
+		bucket := FacetBucket{Value: row[valueColumn], Count: int(count)}
+
+		// INTERVAL() returns the index of the range a value falls into
+		if len(facet.Ranges) > 0 {
+			if index, ok := toFloat(bucket.Value); ok {
+				bucket.From, bucket.To = rangeBounds(facet.Ranges, int(index))
+			}
+		}
+		parsed.Buckets = append(parsed.Buckets, bucket)
+	}
+	return parsed
+}
+
+// parseFacetAggs converts JSON aggregation buckets into facets in the requested order
+func parseFacetAggs(facets []FacetOptions, aggregations map[string]client.SearchAggregation) []Facet {
+	parsed := make([]Facet, 0, len(facets))
+	for _, facet := range facets {
+		name := facetName(facet)
+		aggregation := aggregations[name]
+
+		result := Facet{
+			Name:    name,
+			Field:   facet.Field,
+			Buckets: make([]FacetBucket, 0, len(aggregation.Buckets)),
+		}
+		for _, raw := range aggregation.Buckets {
+			count, _ := toFloat(raw["doc_count"])
+			bucket := FacetBucket{Value: raw["key"], Count: int(count)}
+			if from, ok := toFloat(raw["from"]); ok {
+				bucket.From = &from
+			}
+			if to, ok := toFloat(raw["to"]); ok {
+				bucket.To = &to
+			}
+			result.Buckets = append(result.Buckets, bucket)
+		}
+		parsed = append(parsed, result)
+	}
+	return parsed
+}
+
+// rangeBounds returns the bounds of the i-th range defined by ascending boundaries
+func rangeBounds(boundaries []float64, i int) (*float64, *float64) {
+	var from, to *float64
+	if i > 0 && i <= len(boundaries) {
+		bound := boundaries[i-1]
+		from = &bound
+	}
+	if i >= 0 && i < len(boundaries) {
+		bound := boundaries[i]
+		to = &bound
+	}
+	return from, to
+}
+
+// facetLimit returns the number of buckets requested for a facet
+func facetLimit(facet FacetOptions) int {
+	if facet.Limit > 0 {
+		return facet.Limit
+	}
+	return defaultFacetLimit
+}
+
+// formatNumber renders a float without exponent or trailing zeros
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
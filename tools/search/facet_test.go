@@ -0,0 +1,190 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"os"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFacetSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		facets   []FacetOptions
+		expected string
+	}{
+		{
+			name:     "attribute values",
+			facets:   []FacetOptions{{Field: "brand_id"}},
+			expected: "FACET brand_id AS brand_id ORDER BY COUNT(*) DESC LIMIT 20",
+		},
+		{
+			name:     "named, ordered by value",
+			facets:   []FacetOptions{{Field: "brand_name", Name: "brands", Limit: 5, Order: "value_asc"}},
+			expected: "FACET brand_name AS brands ORDER BY FACET() ASC LIMIT 5",
+		},
+		{
+			name:     "price ranges",
+			facets:   []FacetOptions{{Field: "price", Name: "price_band", Ranges: []float64{100, 500.5}}},
+			expected: "FACET INTERVAL(price,100,500.5) AS price_band ORDER BY COUNT(*) DESC LIMIT 20",
+		},
+		{
+			name:     "histogram",
+			facets:   []FacetOptions{{Field: "price", Interval: 250, Order: "value_desc"}},
+			expected: "FACET HISTOGRAM(price, {hist_interval=250}) AS price ORDER BY FACET() DESC LIMIT 20",
+		},
+		{
+			name:     "several facets",
+			facets:   []FacetOptions{{Field: "brand_id", Limit: 3}, {Field: "year", Order: "count_asc", Limit: 2}},
+			expected: "FACET brand_id AS brand_id ORDER BY COUNT(*) DESC LIMIT 3 FACET year AS year ORDER BY COUNT(*) ASC LIMIT 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := BuildFacetSQL(tt.facets)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+		})
+	}
+}
+
+func TestBuildFacetSQL_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		facets []FacetOptions
+	}{
+		{name: "field injection", facets: []FacetOptions{{Field: "brand_id; DROP TABLE t"}}},
+		{name: "name injection", facets: []FacetOptions{{Field: "brand_id", Name: "b ORDER BY 1"}}},
+		{name: "duplicate name", facets: []FacetOptions{{Field: "brand_id"}, {Field: "brand_id"}}},
+		{name: "negative limit", facets: []FacetOptions{{Field: "brand_id", Limit: -1}}},
+		{name: "unknown order", facets: []FacetOptions{{Field: "brand_id", Order: "random"}}},
+		{name: "ranges and interval", facets: []FacetOptions{{Field: "price", Ranges: []float64{1}, Interval: 10}}},
+		{name: "descending ranges", facets: []FacetOptions{{Field: "price", Ranges: []float64{500, 100}}}},
+		{name: "infinite range", facets: []FacetOptions{{Field: "price", Ranges: []float64{math.Inf(1)}}}},
+		{name: "negative interval", facets: []FacetOptions{{Field: "price", Interval: -5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildFacetSQL(tt.facets)
+			require.ErrorIs(t, err, ErrInvalidFacet)
+		})
+	}
+}
+
+func TestBuildFacetAggs(t *testing.T) {
+	aggs, err := BuildFacetAggs([]FacetOptions{
+		{Field: "brand_id", Name: "brands", Limit: 5, Order: "value_desc"},
+		{Field: "price", Name: "price_band", Ranges: []float64{100, 500}},
+		{Field: "year", Interval: 10},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"terms": map[string]interface{}{"field": "brand_id", "size": 5},
+		"sort":  []map[string]interface{}{{"brand_id": map[string]string{"order": "desc"}}},
+	}, aggs["brands"])
+	assert.Equal(t, map[string]interface{}{
+		"range": map[string]interface{}{"field": "price", "ranges": []map[string]interface{}{
+			{"to": float64(100)},
+			{"from": float64(100), "to": float64(500)},
+			{"from": float64(500)},
+		}},
+	}, aggs["price_band"])
+	assert.Equal(t, map[string]interface{}{
+		"histogram": map[string]interface{}{"field": "year", "interval": float64(10)},
+	}, aggs["year"])
+}
+
+func TestHandler_ExecuteSQLFacets(t *testing.T) {
+	var sql string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			sql = query
+			return []client.SQLResult{
+				{Rows: []map[string]interface{}{{"id": float64(1)}}},
+				{
+					Columns: []client.SQLColumn{{Name: "brands", Type: "long"}, {Name: "count(*)", Type: "long long"}},
+					Rows: []map[string]interface{}{
+						{"brands": float64(3), "count(*)": float64(120)},
+						{"brands": float64(7), "count(*)": float64(80)},
+					},
+				},
+				{
+					Columns: []client.SQLColumn{{Name: "price_band", Type: "long"}, {Name: "count(*)", Type: "long long"}},
+					Rows: []map[string]interface{}{
+						{"price_band": float64(0), "count(*)": float64(150)},
+						{"price_band": float64(2), "count(*)": float64(50)},
+					},
+				},
+				{Rows: []map[string]interface{}{{"Variable_name": "total_found", "Value": "200"}}},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{
+		Table: "products",
+		Query: "laptop",
+		Limit: 1,
+		Facets: []FacetOptions{
+			{Field: "brand_id", Name: "brands"},
+			{Field: "price", Name: "price_band", Ranges: []float64{100, 500}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, " FACET brand_id AS brands ORDER BY COUNT(*) DESC LIMIT 20 FACET INTERVAL(price,100,500) AS price_band")
+	assert.Equal(t, 200, result.Total, "SHOW META follows the facet result sets")
+
+	require.Len(t, result.Facets, 2)
+	assert.Equal(t, "brands", result.Facets[0].Name)
+	assert.Equal(t, []FacetBucket{{Value: float64(3), Count: 120}, {Value: float64(7), Count: 80}}, result.Facets[0].Buckets)
+
+	priceBand := result.Facets[1].Buckets
+	require.Len(t, priceBand, 2)
+	assert.Nil(t, priceBand[0].From)
+	assert.InDelta(t, 100.0, *priceBand[0].To, 0)
+	assert.InDelta(t, 500.0, *priceBand[1].From, 0)
+	assert.Nil(t, priceBand[1].To)
+}
+
+func TestHandler_ExecuteHTTPFacets(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		SearchFunc: func(_ context.Context, query map[string]interface{}) (*client.SearchResponse, error) {
+			assert.Contains(t, query, "aggs")
+			return &client.SearchResponse{
+				Hits: client.SearchHits{Total: 2},
+				Aggregations: map[string]client.SearchAggregation{
+					"brands": {Buckets: []map[string]interface{}{
+						{"key": float64(3), "doc_count": float64(2)},
+					}},
+				},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{
+		Table:   "products",
+		Query:   "laptop",
+		UseHTTP: true,
+		Facets:  []FacetOptions{{Field: "brand_id", Name: "brands"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Facets, 1)
+	assert.Equal(t, []FacetBucket{{Value: float64(3), Count: 2}}, result.Facets[0].Buckets)
+}
@@ -0,0 +1,377 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidFilter     = errors.New("invalid filter")
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+	ErrInvalidLiteral    = errors.New("invalid literal")
+	ErrInvalidOption     = errors.New("invalid option")
+)
+
+// maxFilterDepth limits nesting of filter groups
+const maxFilterDepth = 16
+
+var (
+	// namePattern matches plain table, cluster and field names
+	namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// identifierPattern matches attribute names and JSON attribute paths such as meta.color or tags[0]
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*|\[[0-9]+\])*$`)
+	// functionPattern matches argument-less functions such as weight() or id()
+	functionPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\(\)$`)
+)
+
+// Filter represents a structured WHERE condition. A filter is either a comparison
+// (field, operator, value/values) or a group combining other filters with and/or/not.
+type Filter struct {
+	Field      string        `json:"field,omitempty" description:"Attribute name or JSON attribute path (e.g. price, meta.color, tags[0])"`
+	Operator   string        `json:"operator,omitempty" jsonschema:"enum=eq,enum=ne,enum=gt,enum=gte,enum=lt,enum=lte,enum=in,enum=not_in,enum=between,enum=is_null,enum=is_not_null" description:"Comparison operator"`
+	Value      interface{}   `json:"value,omitempty" description:"Value for eq, ne, gt, gte, lt and lte"`
+	Values     []interface{} `json:"values,omitempty" description:"Values for in and not_in, or [min, max] for between"`
+	Quantifier string        `json:"quantifier,omitempty" jsonschema:"enum=any,enum=all" description:"Apply the comparison to ANY() or ALL() values of a multi-value attribute"`
+	And        []Filter      `json:"and,omitempty" description:"Filters that must all match"`
+	Or         []Filter      `json:"or,omitempty" description:"Filters of which at least one must match"`
+	Not        *Filter       `json:"not,omitempty" description:"Filter that must not match"`
+}
+
+// rankers lists ranking functions accepted in the OPTION clause
+var rankers = map[string]bool{
+	"proximity_bm25": true,
+	"bm25":           true,
+	"none":           true,
+	"wordcount":      true,
+	"proximity":      true,
+	"matchany":       true,
+	"fieldmask":      true,
+	"sph04":          true,
+	"expr":           true,
+	"export":         true,
+}
+
+// layouts lists keyboard layouts accepted by fuzzy search
+var layouts = map[string]bool{
+	"be": true, "bg": true, "br": true, "ch": true, "de": true, "dk": true,
+	"es": true, "fr": true, "gr": true, "it": true, "no": true, "pt": true,
+	"ru": true, "se": true, "ua": true, "uk": true, "us": true,
+}
+
+// comparisonOperators maps filter operators to SQL operators
+var comparisonOperators = map[string]string{
+	"eq":  "=",
+	"ne":  "!=",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// BuildFilterSQL renders a filter as a SQL condition with validated identifiers and escaped literals
+func BuildFilterSQL(filter Filter) (string, error) {
+	return buildFilterSQL(filter, 0)
+}
+
+func buildFilterSQL(filter Filter, depth int) (string, error) {
+	if depth > maxFilterDepth {
+		return "", fmt.Errorf("%w: nesting deeper than %d levels", ErrInvalidFilter, maxFilterDepth)
+	}
+
+	if err := validateFilterShape(filter); err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(filter.And) > 0:
+		return buildFilterGroupSQL(filter.And, " AND ", depth)
+	case len(filter.Or) > 0:
+		return buildFilterGroupSQL(filter.Or, " OR ", depth)
+	case filter.Not != nil:
+		condition, err := buildFilterSQL(*filter.Not, depth+1)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + condition + ")", nil
+	}
+
+	return buildComparisonSQL(filter)
+}
+
+// buildFilterGroupSQL joins rendered filters with the given operator
+func buildFilterGroupSQL(filters []Filter, operator string, depth int) (string, error) {
+	conditions := make([]string, 0, len(filters))
+	for _, f := range filters {
+		condition, err := buildFilterSQL(f, depth+1)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, "("+condition+")")
+	}
+	return strings.Join(conditions, operator), nil
+}
+
+// buildComparisonSQL renders a single field comparison
+func buildComparisonSQL(filter Filter) (string, error) {
+	if err := ValidateIdentifier(filter.Field); err != nil {
+		return "", err
+	}
+
+	field := filter.Field
+	switch filter.Quantifier {
+	case "":
+	case "any", "all":
+		field = strings.ToUpper(filter.Quantifier) + "(" + field + ")"
+	default:
+		return "", fmt.Errorf("%w: unsupported quantifier %q", ErrInvalidFilter, filter.Quantifier)
+	}
+
+	if sqlOperator, ok := comparisonOperators[filter.Operator]; ok {
+		value, err := FormatLiteral(filter.Value)
+		if err != nil {
+			return "", err
+		}
+		return field + " " + sqlOperator + " " + value, nil
+	}
+
+	switch filter.Operator {
+	case "in", "not_in":
+		if len(filter.Values) == 0 {
+			return "", fmt.Errorf("%w: %s requires at least one value", ErrInvalidFilter, filter.Operator)
+		}
+		values, err := formatLiterals(filter.Values)
+		if err != nil {
+			return "", err
+		}
+		sqlOperator := " IN ("
+		if filter.Operator == "not_in" {
+			sqlOperator = " NOT IN ("
+		}
+		return field + sqlOperator + strings.Join(values, ", ") + ")", nil
+	case "between":
+		if len(filter.Values) != 2 {
+			return "", fmt.Errorf("%w: between requires exactly two values", ErrInvalidFilter)
+		}
+		values, err := formatLiterals(filter.Values)
+		if err != nil {
+			return "", err
+		}
+		return field + " BETWEEN " + values[0] + " AND " + values[1], nil
+	case "is_null":
+		return field + " IS NULL", nil
+	case "is_not_null":
+		return field + " IS NOT NULL", nil
+	}
+
+	return "", fmt.Errorf("%w: unsupported operator %q", ErrInvalidFilter, filter.Operator)
+}
+
+// validateFilterShape ensures a filter is exactly one of comparison, and, or, not
+func validateFilterShape(filter Filter) error {
+	kinds := 0
+	if filter.Field != "" || filter.Operator != "" {
+		kinds++
+	}
+	if len(filter.And) > 0 {
+		kinds++
+	}
+	if len(filter.Or) > 0 {
+		kinds++
+	}
+	if filter.Not != nil {
+		kinds++
+	}
+
+	if kinds != 1 {
+		return fmt.Errorf("%w: each filter must be exactly one of a field comparison, and, or, not", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// ValidateIdentifier checks that name is a plain attribute name or JSON attribute path
+func ValidateIdentifier(name string) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
+// ValidateName checks that name is a plain table, cluster or field name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
+// validateSelectExpr checks a select list entry: *, an attribute or an argument-less function
+func validateSelectExpr(expr string) error {
+	if expr == "*" || functionPattern.MatchString(expr) {
+		return nil
+	}
+	return ValidateIdentifier(expr)
+}
+
+// validateOrderExpr checks an ORDER BY entry: attribute or argument-less function with optional direction
+func validateOrderExpr(expr string) error {
+	parts := strings.Fields(expr)
+	if len(parts) == 0 || len(parts) > 2 {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, expr)
+	}
+	if len(parts) == 2 {
+		direction := strings.ToUpper(parts[1])
+		if direction != "ASC" && direction != "DESC" {
+			return fmt.Errorf("%w: invalid sort direction in %q", ErrInvalidIdentifier, expr)
+		}
+	}
+	return validateSelectExpr(parts[0])
+}
+
+// FormatLiteral renders a value as an escaped SQL literal
+func FormatLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return "'" + escapeString(v) + "'", nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("%w: %v", ErrInvalidLiteral, v)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", fmt.Errorf("%w: value is required", ErrInvalidLiteral)
+	default:
+		return "", fmt.Errorf("%w: unsupported value type %T", ErrInvalidLiteral, value)
+	}
+}
+
+// formatLiterals renders a list of values as escaped SQL literals
+func formatLiterals(values []interface{}) ([]string, error) {
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literal, err := FormatLiteral(value)
+		if err != nil {
+			return nil, err
+		}
+		literals = append(literals, literal)
+	}
+	return literals, nil
+}
+
+// escapeString escapes backslashes and single quotes for a SQL string literal
+func escapeString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `'`, `\'`)
+}
+
+// BuildFilterQuery converts a filter into an equivalent JSON query for the /search endpoint
+func BuildFilterQuery(filter Filter) (map[string]interface{}, error) {
+	return buildFilterQuery(filter, 0)
+}
+
+func buildFilterQuery(filter Filter, depth int) (map[string]interface{}, error) {
+	if depth > maxFilterDepth {
+		return nil, fmt.Errorf("%w: nesting deeper than %d levels", ErrInvalidFilter, maxFilterDepth)
+	}
+
+	if err := validateFilterShape(filter); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(filter.And) > 0:
+		return buildFilterGroupQuery(filter.And, "must", depth)
+	case len(filter.Or) > 0:
+		return buildFilterGroupQuery(filter.Or, "should", depth)
+	case filter.Not != nil:
+		return buildFilterGroupQuery([]Filter{*filter.Not}, "must_not", depth)
+	}
+
+	return buildComparisonQuery(filter)
+}
+
+// buildFilterGroupQuery wraps converted filters into a bool query occurrence
+func buildFilterGroupQuery(filters []Filter, occurrence string, depth int) (map[string]interface{}, error) {
+	clauses := make([]map[string]interface{}, 0, len(filters))
+	for _, f := range filters {
+		clause, err := buildFilterQuery(f, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			occurrence: clauses,
+		},
+	}, nil
+}
+
+// buildComparisonQuery converts a single field comparison into a JSON query clause
+func buildComparisonQuery(filter Filter) (map[string]interface{}, error) {
+	if err := ValidateIdentifier(filter.Field); err != nil {
+		return nil, err
+	}
+
+	// JSON queries match multi-value attributes when any value matches
+	if filter.Quantifier != "" && filter.Quantifier != "any" {
+		return nil, fmt.Errorf("%w: quantifier %q is only supported for SQL searches", ErrInvalidFilter, filter.Quantifier)
+	}
+
+	rangeClause := func(bounds map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"range": map[string]interface{}{filter.Field: bounds}}
+	}
+	negate := func(clause map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"bool": map[string]interface{}{"must_not": []map[string]interface{}{clause}}}
+	}
+
+	switch filter.Operator {
+	case "eq", "ne":
+		if filter.Value == nil {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidLiteral)
+		}
+		clause := map[string]interface{}{"equals": map[string]interface{}{filter.Field: filter.Value}}
+		if filter.Operator == "ne" {
+			return negate(clause), nil
+		}
+		return clause, nil
+	case "gt", "gte", "lt", "lte":
+		if filter.Value == nil {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidLiteral)
+		}
+		return rangeClause(map[string]interface{}{filter.Operator: filter.Value}), nil
+	case "between":
+		if len(filter.Values) != 2 {
+			return nil, fmt.Errorf("%w: between requires exactly two values", ErrInvalidFilter)
+		}
+		return rangeClause(map[string]interface{}{"gte": filter.Values[0], "lte": filter.Values[1]}), nil
+	case "in", "not_in":
+		if len(filter.Values) == 0 {
+			return nil, fmt.Errorf("%w: %s requires at least one value", ErrInvalidFilter, filter.Operator)
+		}
+		clause := map[string]interface{}{"in": map[string]interface{}{filter.Field: filter.Values}}
+		if filter.Operator == "not_in" {
+			return negate(clause), nil
+		}
+		return clause, nil
+	case "is_null", "is_not_null":
+		return nil, fmt.Errorf("%w: %s is only supported for SQL searches", ErrInvalidFilter, filter.Operator)
+	}
+
+	return nil, fmt.Errorf("%w: unsupported operator %q", ErrInvalidFilter, filter.Operator)
+}
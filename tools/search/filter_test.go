@@ -0,0 +1,294 @@
+package search
+
+import (
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFilterSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected string
+	}{
+		{
+			name:     "equals number",
+			filter:   Filter{Field: "category_id", Operator: "eq", Value: float64(1)},
+			expected: "category_id = 1",
+		},
+		{
+			name:     "greater than float",
+			filter:   Filter{Field: "price", Operator: "gt", Value: 99.5},
+			expected: "price > 99.5",
+		},
+		{
+			name:     "not equal string is escaped",
+			filter:   Filter{Field: "brand", Operator: "ne", Value: `O'Reilly\`},
+			expected: `brand != 'O\'Reilly\\'`,
+		},
+		{
+			name:     "boolean",
+			filter:   Filter{Field: "in_stock", Operator: "eq", Value: true},
+			expected: "in_stock = 1",
+		},
+		{
+			name:     "in",
+			filter:   Filter{Field: "category_id", Operator: "in", Values: []interface{}{float64(1), float64(2), float64(3)}},
+			expected: "category_id IN (1, 2, 3)",
+		},
+		{
+			name:     "not in strings",
+			filter:   Filter{Field: "color", Operator: "not_in", Values: []interface{}{"red", "blue"}},
+			expected: "color NOT IN ('red', 'blue')",
+		},
+		{
+			name:     "between",
+			filter:   Filter{Field: "price", Operator: "between", Values: []interface{}{float64(10), float64(20)}},
+			expected: "price BETWEEN 10 AND 20",
+		},
+		{
+			name:     "any over mva",
+			filter:   Filter{Field: "tags", Operator: "eq", Value: float64(5), Quantifier: "any"},
+			expected: "ANY(tags) = 5",
+		},
+		{
+			name:     "all over mva",
+			filter:   Filter{Field: "tags", Operator: "in", Values: []interface{}{float64(1), float64(2)}, Quantifier: "all"},
+			expected: "ALL(tags) IN (1, 2)",
+		},
+		{
+			name:     "json attribute path",
+			filter:   Filter{Field: "meta.sizes[0]", Operator: "gte", Value: float64(42)},
+			expected: "meta.sizes[0] >= 42",
+		},
+		{
+			name:     "is null",
+			filter:   Filter{Field: "meta.color", Operator: "is_null"},
+			expected: "meta.color IS NULL",
+		},
+		{
+			name: "nested groups",
+			filter: Filter{And: []Filter{
+				{Field: "price", Operator: "lt", Value: float64(100)},
+				{Or: []Filter{
+					{Field: "category_id", Operator: "eq", Value: float64(1)},
+					{Not: &Filter{Field: "brand", Operator: "eq", Value: "acme"}},
+				}},
+			}},
+			expected: "(price < 100) AND ((category_id = 1) OR (NOT (brand = 'acme')))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := BuildFilterSQL(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+		})
+	}
+}
+
+func TestBuildFilterSQL_Invalid(t *testing.T) {
+	deep := Filter{Field: "id", Operator: "eq", Value: float64(1)}
+	for i := 0; i <= maxFilterDepth+1; i++ {
+		deep = Filter{Not: &deep}
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected error
+	}{
+		{
+			name:     "injection in field",
+			filter:   Filter{Field: "id=1 OR 1", Operator: "eq", Value: float64(1)},
+			expected: ErrInvalidIdentifier,
+		},
+		{
+			name:     "function call in field",
+			filter:   Filter{Field: "sleep(10)", Operator: "eq", Value: float64(1)},
+			expected: ErrInvalidIdentifier,
+		},
+		{
+			name:     "unknown operator",
+			filter:   Filter{Field: "id", Operator: "like", Value: "x"},
+			expected: ErrInvalidFilter,
+		},
+		{
+			name:     "unknown quantifier",
+			filter:   Filter{Field: "tags", Operator: "eq", Value: float64(1), Quantifier: "some"},
+			expected: ErrInvalidFilter,
+		},
+		{
+			name:     "missing value",
+			filter:   Filter{Field: "id", Operator: "eq"},
+			expected: ErrInvalidLiteral,
+		},
+		{
+			name:     "object value",
+			filter:   Filter{Field: "id", Operator: "eq", Value: map[string]interface{}{"a": 1}},
+			expected: ErrInvalidLiteral,
+		},
+		{
+			name:     "between with one value",
+			filter:   Filter{Field: "price", Operator: "between", Values: []interface{}{float64(1)}},
+			expected: ErrInvalidFilter,
+		},
+		{
+			name:     "empty in",
+			filter:   Filter{Field: "id", Operator: "in"},
+			expected: ErrInvalidFilter,
+		},
+		{
+			name:     "comparison mixed with group",
+			filter:   Filter{Field: "id", Operator: "eq", Value: float64(1), Or: []Filter{{Field: "id", Operator: "eq", Value: float64(2)}}},
+			expected: ErrInvalidFilter,
+		},
+		{
+			name:     "empty filter",
+			filter:   Filter{},
+			expected: ErrInvalidFilter,
+		},
+		{
+			name:     "too deep",
+			filter:   deep,
+			expected: ErrInvalidFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildFilterSQL(tt.filter)
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestBuildFilterQuery(t *testing.T) {
+	filter := Filter{And: []Filter{
+		{Field: "price", Operator: "between", Values: []interface{}{float64(10), float64(20)}},
+		{Field: "category_id", Operator: "in", Values: []interface{}{float64(1), float64(2)}},
+		{Not: &Filter{Field: "brand", Operator: "eq", Value: "acme"}},
+	}}
+
+	query, err := BuildFilterQuery(filter)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []map[string]interface{}{
+				{"range": map[string]interface{}{"price": map[string]interface{}{"gte": float64(10), "lte": float64(20)}}},
+				{"in": map[string]interface{}{"category_id": []interface{}{float64(1), float64(2)}}},
+				{"bool": map[string]interface{}{
+					"must_not": []map[string]interface{}{
+						{"equals": map[string]interface{}{"brand": "acme"}},
+					},
+				}},
+			},
+		},
+	}
+	assert.Equal(t, expected, query)
+
+	_, err = BuildFilterQuery(Filter{Field: "tags", Operator: "eq", Value: float64(1), Quantifier: "all"})
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, err = BuildFilterQuery(Filter{Field: "meta.color", Operator: "is_null"})
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestHandler_buildSQLValidatesIdentifiers(t *testing.T) {
+	handler := &Handler{}
+
+	tests := []struct {
+		name    string
+		args    Args
+		wantErr bool
+	}{
+		{name: "plain fields and ordering", args: Args{Table: "t", Query: "q", Fields: []string{"id", "title", "weight()"}, OrderBy: []string{"weight() DESC", "price asc", "meta.rank"}}},
+		{name: "grouping", args: Args{Table: "t", Query: "q", GroupBy: []string{"category_id"}, GroupSort: "price DESC, id ASC"}},
+		{name: "field injection", args: Args{Table: "t", Query: "q", Fields: []string{"id FROM t; DROP TABLE t; --"}}, wantErr: true},
+		{name: "order by injection", args: Args{Table: "t", Query: "q", OrderBy: []string{"id; DROP TABLE t"}}, wantErr: true},
+		{name: "order by bad direction", args: Args{Table: "t", Query: "q", OrderBy: []string{"id SIDEWAYS"}}, wantErr: true},
+		{name: "group by expression", args: Args{Table: "t", Query: "q", GroupBy: []string{"1=1"}}, wantErr: true},
+		{name: "group sort injection", args: Args{Table: "t", Query: "q", GroupBy: []string{"id"}, GroupSort: "id) OR (1"}, wantErr: true},
+		{name: "field weight injection", args: Args{Table: "t", Query: "q", FieldWeights: map[string]int{"title)=1,ranker=(none": 1}}, wantErr: true},
+		{name: "table injection", args: Args{Table: "t; DROP TABLE users; SELECT * FROM t", Query: "q"}, wantErr: true},
+		{name: "cluster injection", args: Args{Table: "t", Cluster: "c:t; DROP TABLE users; --", Query: "q"}, wantErr: true},
+		{name: "highlight field injection", args: Args{Table: "t", Query: "q", Highlight: &HighlightOptions{Enabled: true, Fields: []string{"title','x"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.buildSQL(tt.args)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidIdentifier)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHandler_buildSQLValidatesOptions(t *testing.T) {
+	handler := &Handler{}
+
+	tests := []struct {
+		name string
+		args Args
+	}{
+		{name: "ranker injection", args: Args{Table: "t", Query: "q", Ranker: "bm25; DROP TABLE t"}},
+		{name: "morphology injection", args: Args{Table: "t", Query: "q", Morphology: "none; DROP TABLE t"}},
+		{name: "layout injection", args: Args{Table: "t", Query: "q", Fuzzy: &FuzzyOptions{Enabled: true, Layouts: []string{"us'); DROP TABLE t; --"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.buildSQL(tt.args)
+			require.ErrorIs(t, err, ErrInvalidOption)
+		})
+	}
+
+	sql, err := handler.buildSQL(Args{Table: "t", Query: "q", Ranker: "bm25", Morphology: "none", Fuzzy: &FuzzyOptions{Enabled: true, Layouts: []string{"us", "ru"}}})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "ranker=bm25")
+	assert.Contains(t, sql, "layouts='us,ru'")
+}
+
+func TestHandler_buildSQLEscapesLiterals(t *testing.T) {
+	handler := &Handler{}
+
+	sql, err := handler.buildSQL(Args{
+		Table:       "t",
+		Query:       `x\'); DROP TABLE t; SELECT ('`,
+		Comment:     `c\'`,
+		TokenFilter: `lib\':plugin`,
+		Highlight:   &HighlightOptions{Enabled: true, StartTag: `<b\'>`, EndTag: `</b\'>`},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, sql, `MATCH('x\\\'); DROP TABLE t; SELECT (\'')`)
+	assert.Contains(t, sql, `before_match='<b\\\'>'`)
+	assert.Contains(t, sql, `after_match='</b\\\'>'`)
+	assert.Contains(t, sql, `comment='c\\\''`)
+	assert.Contains(t, sql, `token_filter='lib\\\':plugin'`)
+	assert.Len(t, client.SplitStatements(sql), 1)
+}
+
+func TestHandler_buildSQLWithFilter(t *testing.T) {
+	handler := &Handler{}
+
+	sql, err := handler.buildSQL(Args{
+		Table: "products",
+		Query: "laptop",
+		Filter: &Filter{And: []Filter{
+			{Field: "price", Operator: "lte", Value: float64(1000)},
+			{Field: "brand", Operator: "eq", Value: "x') OR 1=1 --"},
+		}},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, sql, `WHERE MATCH('laptop') AND ((price <= 1000) AND (brand = 'x\') OR 1=1 --')) LIMIT 10`)
+}
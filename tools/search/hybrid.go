@@ -0,0 +1,296 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ErrInvalidHybrid is returned for hybrid search options that cannot be executed
+var ErrInvalidHybrid = errors.New("invalid hybrid search")
+
+// Fusion methods of a hybrid search
+const (
+	FusionRRF      = "rrf"
+	FusionWeighted = "weighted"
+)
+
+// defaultRankConstant is the k of reciprocal rank fusion, 60 as in the original paper
+const defaultRankConstant = 60
+
+// HybridOptions describes how the full-text and the vector result lists of a search are fused.
+// Both query and knn are required; filter and where apply to both lists.
+type HybridOptions struct {
+	Fusion       string  `json:"fusion,omitempty" jsonschema:"enum=rrf,enum=weighted" description:"Fusion method: rrf (reciprocal rank fusion) or weighted (normalized scores), default: rrf"`
+	RankConstant int     `json:"rank_constant,omitempty" description:"Constant k of reciprocal rank fusion (default: 60)"`
+	TextWeight   float64 `json:"text_weight,omitempty" description:"Weight of the full-text list (default: 1)"`
+	VectorWeight float64 `json:"vector_weight,omitempty" description:"Weight of the vector list (default: 1)"`
+	Window       int     `json:"window,omitempty" description:"Candidates fetched from each list before fusion (default: max of knn.k and offset+limit)"`
+}
+
+// hybridCandidate collects the component scores of one document found by either list
+type hybridCandidate struct {
+	row        map[string]interface{}
+	textScore  *float64
+	textRank   int
+	knnDist    *float64
+	vectorRank int
+	score      float64
+}
+
+// validateHybrid checks that a search can run as a hybrid search
+func validateHybrid(args Args) error {
+	hybrid := args.Hybrid
+
+	switch {
+	case args.Query == "":
+		return fmt.Errorf("%w: query is required", ErrInvalidHybrid)
+	case args.KNN == nil:
+		return fmt.Errorf("%w: knn is required", ErrInvalidHybrid)
+	case args.BoolQuery != nil || args.UseHTTP:
+		return fmt.Errorf("%w: only supported for SQL searches", ErrInvalidHybrid)
+	case len(args.GroupBy) > 0 || len(args.OrderBy) > 0:
+		return fmt.Errorf("%w: results are ordered by the fused score, group_by and order_by are not supported", ErrInvalidHybrid)
+	case len(args.Facets) > 0:
+		return fmt.Errorf("%w: facets are not supported", ErrInvalidHybrid)
+	}
+
+	switch hybrid.Fusion {
+	case "", FusionRRF, FusionWeighted:
+	default:
+		return fmt.Errorf("%w: unsupported fusion %q", ErrInvalidHybrid, hybrid.Fusion)
+	}
+	if hybrid.RankConstant < 0 || hybrid.Window < 0 {
+		return fmt.Errorf("%w: rank_constant and window must not be negative", ErrInvalidHybrid)
+	}
+	if hybrid.TextWeight < 0 || hybrid.VectorWeight < 0 {
+		return fmt.Errorf("%w: weights must not be negative", ErrInvalidHybrid)
+	}
+	return nil
+}
+
+// executeHybridQuery runs the full-text and the vector search separately and fuses both lists
+func (h *Handler) executeHybridQuery(ctx context.Context, args Args) (*Result, error) {
+	if err := validateHybrid(args); err != nil {
+		return nil, err
+	}
+
+	// Set defaults
+	if args.Limit <= 0 {
+		args.Limit = 10
+	}
+	options := *args.Hybrid
+	if options.Fusion == "" {
+		options.Fusion = FusionRRF
+	}
+	if options.RankConstant == 0 {
+		options.RankConstant = defaultRankConstant
+	}
+	if options.TextWeight == 0 && options.VectorWeight == 0 {
+		options.TextWeight, options.VectorWeight = 1, 1
+	}
+	if options.Window == 0 {
+		options.Window = max(args.KNN.K, args.Offset+args.Limit)
+	}
+
+	textResult, err := h.queryHybridList(ctx, args, options.Window, false)
+	if err != nil {
+		return nil, err
+	}
+	vectorResult, err := h.queryHybridList(ctx, args, options.Window, true)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := fuseHybrid(textResult.Hits, vectorResult.Hits, options)
+
+	result := &Result{
+		Total:       len(hits),
+		QueryTimeMs: textResult.QueryTimeMs + vectorResult.QueryTimeMs,
+		Keywords:    textResult.Keywords,
+	}
+
+	start := min(args.Offset, len(hits))
+	end := min(start+args.Limit, len(hits))
+	result.Hits = hits[start:end]
+
+	return result, nil
+}
+
+// queryHybridList fetches the candidates of one list, ordered by its own relevance
+func (h *Handler) queryHybridList(ctx context.Context, args Args, window int, vector bool) (*Result, error) {
+	listArgs := args
+	listArgs.Hybrid = nil
+	listArgs.Limit = window
+	listArgs.Offset = 0
+
+	// Documents of both lists are matched by id
+	fields := args.Fields
+	if len(fields) == 0 {
+		fields = []string{"*"}
+	} else if !slices.Contains(fields, "id") {
+		fields = append([]string{"id"}, fields...)
+	}
+
+	if vector {
+		listArgs.Query = ""
+		listArgs.Fields = fields
+	} else {
+		listArgs.KNN = nil
+		listArgs.Fields = append(slices.Clone(fields), "weight()")
+	}
+	// Manticore keeps only max_matches (default 1000) matches, fewer than a large window
+	if listArgs.MaxMatches < window {
+		listArgs.MaxMatches = window
+	}
+	if listArgs.MatchMode == "" {
+		listArgs.MatchMode = "extended"
+	}
+
+	sql, err := h.buildSQL(listArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return h.querySQL(ctx, sql, nil)
+}
+
+// fuseHybrid merges the full-text and vector hits into one list ordered by the fused score.
+// Each hit carries _score, _text_score, _text_rank, knn_dist and _vector_rank; the scores and
+// ranks of a list the document was not found in are omitted.
+func fuseHybrid(textHits, vectorHits []map[string]interface{}, options HybridOptions) []map[string]interface{} {
+	candidates := make(map[string]*hybridCandidate, len(textHits)+len(vectorHits))
+	order := make([]string, 0, len(textHits)+len(vectorHits))
+
+	candidate := func(row map[string]interface{}) *hybridCandidate {
+		key := fmt.Sprintf("%v", row["id"])
+		if c, ok := candidates[key]; ok {
+			return c
+		}
+		c := &hybridCandidate{row: make(map[string]interface{}, len(row)+5)}
+		candidates[key] = c
+		order = append(order, key)
+		return c
+	}
+
+	for i, row := range textHits {
+		c := candidate(row)
+		for field, value := range row {
+			if field != "weight()" {
+				c.row[field] = value
+			}
+		}
+		c.textRank = i + 1
+		if score, ok := toFloat(row["weight()"]); ok {
+			c.textScore = &score
+		}
+	}
+
+	for i, row := range vectorHits {
+		c := candidate(row)
+		for field, value := range row {
+			if _, exists := c.row[field]; !exists {
+				c.row[field] = value
+			}
+		}
+		c.vectorRank = i + 1
+		if dist, ok := toFloat(row["knn_dist"]); ok {
+			c.knnDist = &dist
+		}
+	}
+
+	if options.Fusion == FusionWeighted {
+		scoreWeighted(candidates, options)
+	} else {
+		scoreRRF(candidates, options)
+	}
+
+	hits := make([]map[string]interface{}, 0, len(order))
+	slices.SortStableFunc(order, func(a, b string) int {
+		// Higher scores first, the insertion order keeps ties deterministic
+		switch sa, sb := candidates[a].score, candidates[b].score; {
+		case sa > sb:
+			return -1
+		case sa < sb:
+			return 1
+		}
+		return 0
+	})
+	for _, key := range order {
+		c := candidates[key]
+		c.row["_score"] = c.score
+		if c.textScore != nil {
+			c.row["_text_score"] = *c.textScore
+			c.row["_text_rank"] = c.textRank
+		}
+		if c.knnDist != nil {
+			c.row["knn_dist"] = *c.knnDist
+			c.row["_vector_rank"] = c.vectorRank
+		}
+		hits = append(hits, c.row)
+	}
+	return hits
+}
+
+// scoreRRF sums the weighted reciprocal ranks of both lists
+func scoreRRF(candidates map[string]*hybridCandidate, options HybridOptions) {
+	k := float64(options.RankConstant)
+	for _, c := range candidates {
+		if c.textRank > 0 {
+			c.score += options.TextWeight / (k + float64(c.textRank))
+		}
+		if c.vectorRank > 0 {
+			c.score += options.VectorWeight / (k + float64(c.vectorRank))
+		}
+	}
+}
+
+// scoreWeighted sums the min-max normalized scores of both lists. Text scores grow with
+// relevance while knn distances shrink, so distances are inverted.
+func scoreWeighted(candidates map[string]*hybridCandidate, options HybridOptions) {
+	minText, maxText := math.Inf(1), math.Inf(-1)
+	minDist, maxDist := math.Inf(1), math.Inf(-1)
+	for _, c := range candidates {
+		if c.textScore != nil {
+			minText, maxText = math.Min(minText, *c.textScore), math.Max(maxText, *c.textScore)
+		}
+		if c.knnDist != nil {
+			minDist, maxDist = math.Min(minDist, *c.knnDist), math.Max(maxDist, *c.knnDist)
+		}
+	}
+
+	normalize := func(value, lo, hi float64) float64 {
+		if hi == lo {
+			return 1
+		}
+		return (value - lo) / (hi - lo)
+	}
+
+	for _, c := range candidates {
+		if c.textScore != nil {
+			c.score += options.TextWeight * normalize(*c.textScore, minText, maxText)
+		}
+		if c.knnDist != nil {
+			c.score += options.VectorWeight * (1 - normalize(*c.knnDist, minDist, maxDist))
+		}
+	}
+}
+
+// toFloat converts a numeric column value decoded from JSON into a float
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,180 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuseHybrid_RRF(t *testing.T) {
+	textHits := []map[string]interface{}{
+		{"id": float64(1), "title": "a", "weight()": float64(2500)},
+		{"id": float64(2), "title": "b", "weight()": float64(1500)},
+	}
+	vectorHits := []map[string]interface{}{
+		{"id": float64(2), "title": "b", "knn_dist": 0.1},
+		{"id": float64(3), "title": "c", "knn_dist": 0.4},
+	}
+
+	hits := fuseHybrid(textHits, vectorHits, HybridOptions{Fusion: FusionRRF, RankConstant: 60, TextWeight: 1, VectorWeight: 1})
+	require.Len(t, hits, 3)
+
+	// Found by both lists, so ranked first
+	assert.InDelta(t, float64(2), hits[0]["id"], 0)
+	assert.InDelta(t, 1.0/62+1.0/61, hits[0]["_score"], 1e-9)
+	assert.InDelta(t, 1500.0, hits[0]["_text_score"], 0)
+	assert.Equal(t, 2, hits[0]["_text_rank"])
+	assert.InDelta(t, 0.1, hits[0]["knn_dist"], 0)
+	assert.Equal(t, 1, hits[0]["_vector_rank"])
+	assert.NotContains(t, hits[0], "weight()")
+
+	assert.InDelta(t, float64(1), hits[1]["id"], 0)
+	assert.NotContains(t, hits[1], "knn_dist")
+	assert.NotContains(t, hits[1], "_vector_rank")
+
+	assert.InDelta(t, float64(3), hits[2]["id"], 0)
+	assert.NotContains(t, hits[2], "_text_score")
+}
+
+func TestFuseHybrid_Weighted(t *testing.T) {
+	textHits := []map[string]interface{}{
+		{"id": float64(1), "weight()": float64(3000)},
+		{"id": float64(2), "weight()": float64(1000)},
+	}
+	vectorHits := []map[string]interface{}{
+		{"id": float64(2), "knn_dist": 0.2},
+		{"id": float64(1), "knn_dist": 0.6},
+	}
+
+	// The vector list dominates
+	hits := fuseHybrid(textHits, vectorHits, HybridOptions{Fusion: FusionWeighted, TextWeight: 0.2, VectorWeight: 0.8})
+	require.Len(t, hits, 2)
+	assert.InDelta(t, float64(2), hits[0]["id"], 0)
+	assert.InDelta(t, 0.8, hits[0]["_score"], 1e-9)
+	assert.InDelta(t, float64(1), hits[1]["id"], 0)
+	assert.InDelta(t, 0.2, hits[1]["_score"], 1e-9)
+}
+
+func TestHandler_ExecuteHybrid(t *testing.T) {
+	var queries []string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			queries = append(queries, query)
+			meta := client.SQLResult{Rows: []map[string]interface{}{{"Variable_name": "time", "Value": "0.002"}}}
+			if strings.Contains(query, "knn(") {
+				return []client.SQLResult{{Rows: []map[string]interface{}{
+					{"id": float64(3), "knn_dist": 0.1},
+					{"id": float64(1), "knn_dist": 0.3},
+				}}, meta}, nil
+			}
+			return []client.SQLResult{{Rows: []map[string]interface{}{
+				{"id": float64(1), "weight()": float64(2000)},
+				{"id": float64(2), "weight()": float64(1000)},
+			}}, meta}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{
+		Table:  "docs",
+		Query:  "vector database",
+		KNN:    &KNNOptions{Field: "embedding", K: 10, Vector: []float64{0.1, 0.2}},
+		Filter: &Filter{Field: "lang", Operator: "eq", Value: "en"},
+		Hybrid: &HybridOptions{},
+		Limit:  2,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0], "SELECT *, weight() FROM docs WHERE MATCH('vector database') AND (lang = 'en') LIMIT 10")
+	assert.Contains(t, queries[1], "SELECT *, knn_dist() AS knn_dist FROM docs WHERE knn(embedding, 10, (0.1,0.2)) AND (lang = 'en') LIMIT 10")
+	assert.NotContains(t, queries[1], "MATCH(")
+
+	require.Len(t, result.Hits, 2)
+	assert.InDelta(t, float64(1), result.Hits[0]["id"], 0)
+	assert.Equal(t, 3, result.Total)
+	assert.True(t, result.HasMore)
+	assert.InDelta(t, 4.0, result.QueryTimeMs, 0.001)
+}
+
+func TestHandler_ExecuteHybridLargeWindow(t *testing.T) {
+	var queries []string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			queries = append(queries, query)
+			return []client.SQLResult{{Rows: []map[string]interface{}{}}, {}}, nil
+		},
+	}
+	handler := NewHandler(mockClient, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+
+	// The window of 1500 candidates exceeds the default max_matches of 1000
+	_, err := handler.Execute(context.Background(), Args{
+		Table:  "docs",
+		Query:  "vector database",
+		KNN:    &KNNOptions{Field: "embedding", K: 1500, Vector: []float64{0.1, 0.2}},
+		Hybrid: &HybridOptions{},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	for _, query := range queries {
+		assert.Contains(t, query, "LIMIT 1500")
+		assert.Contains(t, query, "max_matches=1500")
+	}
+
+	// A smaller max_matches is raised to the window as well
+	queries = nil
+	_, err = handler.Execute(context.Background(), Args{
+		Table:      "docs",
+		Query:      "vector database",
+		KNN:        &KNNOptions{Field: "embedding", K: 10, Vector: []float64{0.1, 0.2}},
+		Hybrid:     &HybridOptions{Window: 1500},
+		Limit:      10,
+		MaxMatches: 500,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	for _, query := range queries {
+		assert.Contains(t, query, "max_matches=1500")
+	}
+}
+
+func TestHandler_ExecuteHybridInvalid(t *testing.T) {
+	handler := NewHandler(&client.ManticoreClientMock{}, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+	knn := &KNNOptions{Field: "embedding", K: 10, Vector: []float64{0.1}}
+
+	tests := []struct {
+		name string
+		args Args
+	}{
+		{name: "missing query", args: Args{Table: "docs", KNN: knn, Hybrid: &HybridOptions{}}},
+		{name: "missing knn", args: Args{Table: "docs", Query: "q", Hybrid: &HybridOptions{}}},
+		{name: "json path", args: Args{Table: "docs", Query: "q", KNN: knn, UseHTTP: true, Hybrid: &HybridOptions{}}},
+		{name: "order by", args: Args{Table: "docs", Query: "q", KNN: knn, OrderBy: []string{"id ASC"}, Hybrid: &HybridOptions{}}},
+		{name: "unknown fusion", args: Args{Table: "docs", Query: "q", KNN: knn, Hybrid: &HybridOptions{Fusion: "max"}}},
+		{name: "negative weight", args: Args{Table: "docs", Query: "q", KNN: knn, Hybrid: &HybridOptions{TextWeight: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.Execute(context.Background(), tt.args)
+			require.ErrorIs(t, err, ErrInvalidHybrid)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidKNN is returned for vector search options that cannot be rendered
+var ErrInvalidKNN = errors.New("invalid knn search")
+
+// KNNOptions describes a k-nearest-neighbour search over a float_vector attribute.
+// Full-text query, filter, where and bool_query conditions act as prefilters.
+type KNNOptions struct {
+	Field  string    `json:"field" jsonschema:"required" description:"float_vector attribute to search"`
+	K      int       `json:"k" jsonschema:"required" description:"Number of nearest neighbours to return"`
+	Vector []float64 `json:"vector,omitempty" description:"Query vector, either vector or doc_id is required"`
+	DocID  uint64    `json:"doc_id,omitempty" description:"Search for documents similar to the vector of this document (more like this)"`
+	EF     int       `json:"ef,omitempty" description:"Size of the HNSW candidate list, higher is more accurate but slower"`
+}
+
+// validateKNN checks that a KNN search has a valid field, k and exactly one query source
+func validateKNN(knn KNNOptions) error {
+	if err := ValidateIdentifier(knn.Field); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidKNN, err)
+	}
+	if knn.K <= 0 {
+		return fmt.Errorf("%w: k must be positive", ErrInvalidKNN)
+	}
+	if knn.EF < 0 {
+		return fmt.Errorf("%w: ef must not be negative", ErrInvalidKNN)
+	}
+
+	switch {
+	case len(knn.Vector) > 0 && knn.DocID != 0:
+		return fmt.Errorf("%w: vector and doc_id are mutually exclusive", ErrInvalidKNN)
+	case len(knn.Vector) == 0 && knn.DocID == 0:
+		return fmt.Errorf("%w: vector or doc_id is required", ErrInvalidKNN)
+	}
+
+	for _, value := range knn.Vector {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("%w: vector contains %v", ErrInvalidKNN, value)
+		}
+	}
+	return nil
+}
+
+// BuildKNNSQL renders the knn() condition of a SQL search
+func BuildKNNSQL(knn KNNOptions) (string, error) {
+	if err := validateKNN(knn); err != nil {
+		return "", err
+	}
+
+	var sql strings.Builder
+	sql.WriteString("knn(")
+	sql.WriteString(knn.Field)
+	sql.WriteString(", ")
+	sql.WriteString(strconv.Itoa(knn.K))
+	sql.WriteString(", ")
+
+	if knn.DocID != 0 {
+		sql.WriteString(strconv.FormatUint(knn.DocID, 10))
+	} else {
+		values := make([]string, len(knn.Vector))
+		for i, value := range knn.Vector {
+			values[i] = strconv.FormatFloat(value, 'g', -1, 64)
+		}
+		sql.WriteString("(")
+		sql.WriteString(strings.Join(values, ","))
+		sql.WriteString(")")
+	}
+
+	if knn.EF > 0 {
+		sql.WriteString(", ")
+		sql.WriteString(strconv.Itoa(knn.EF))
+	}
+
+	sql.WriteString(")")
+	return sql.String(), nil
+}
+
+// BuildKNNQuery renders the knn object of a JSON search
+func BuildKNNQuery(knn KNNOptions) (map[string]interface{}, error) {
+	if err := validateKNN(knn); err != nil {
+		return nil, err
+	}
+
+	query := map[string]interface{}{
+		"field": knn.Field,
+		"k":     knn.K,
+	}
+	if knn.DocID != 0 {
+		query["doc_id"] = knn.DocID
+	} else {
+		query["query_vector"] = knn.Vector
+	}
+	if knn.EF > 0 {
+		query["ef"] = knn.EF
+	}
+	return query, nil
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"math"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildKNNSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		knn      KNNOptions
+		expected string
+	}{
+		{
+			name:     "query vector",
+			knn:      KNNOptions{Field: "image_vector", K: 5, Vector: []float64{0.286569, -0.031816, 1}},
+			expected: "knn(image_vector, 5, (0.286569,-0.031816,1))",
+		},
+		{
+			name:     "query vector with ef",
+			knn:      KNNOptions{Field: "image_vector", K: 5, Vector: []float64{0.5, 0.25}, EF: 2000},
+			expected: "knn(image_vector, 5, (0.5,0.25), 2000)",
+		},
+		{
+			name:     "more like document",
+			knn:      KNNOptions{Field: "image_vector", K: 10, DocID: 42},
+			expected: "knn(image_vector, 10, 42)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := BuildKNNSQL(tt.knn)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+		})
+	}
+}
+
+func TestBuildKNNSQL_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		knn  KNNOptions
+	}{
+		{name: "missing field", knn: KNNOptions{K: 5, Vector: []float64{1}}},
+		{name: "field injection", knn: KNNOptions{Field: "v, 1, (1)) OR (1", K: 5, Vector: []float64{1}}},
+		{name: "zero k", knn: KNNOptions{Field: "v", Vector: []float64{1}}},
+		{name: "negative ef", knn: KNNOptions{Field: "v", K: 5, Vector: []float64{1}, EF: -1}},
+		{name: "no query source", knn: KNNOptions{Field: "v", K: 5}},
+		{name: "vector and doc id", knn: KNNOptions{Field: "v", K: 5, Vector: []float64{1}, DocID: 1}},
+		{name: "nan in vector", knn: KNNOptions{Field: "v", K: 5, Vector: []float64{math.NaN()}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildKNNSQL(tt.knn)
+			require.ErrorIs(t, err, ErrInvalidKNN)
+		})
+	}
+}
+
+func TestHandler_buildSQLWithKNN(t *testing.T) {
+	handler := &Handler{}
+
+	sql, err := handler.buildSQL(Args{
+		Table:  "images",
+		KNN:    &KNNOptions{Field: "image_vector", K: 5, Vector: []float64{0.1, 0.2}},
+		Filter: &Filter{Field: "category_id", Operator: "eq", Value: float64(3)},
+		Limit:  5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT *, knn_dist() AS knn_dist FROM images WHERE knn(image_vector, 5, (0.1,0.2)) AND (category_id = 3) LIMIT 5 OPTION boolean_simplify=0", sql)
+
+	sql, err = handler.buildSQL(Args{
+		Table: "images",
+		Query: "cat",
+		KNN:   &KNNOptions{Field: "image_vector", K: 5, DocID: 7},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WHERE knn(image_vector, 5, 7) AND MATCH('cat')")
+}
+
+func TestQueryBuilder_BuildHTTPQueryWithKNN(t *testing.T) {
+	qb := NewQueryBuilder("", "images")
+
+	query, err := qb.BuildHTTPQuery(Args{
+		Table:  "images",
+		KNN:    &KNNOptions{Field: "image_vector", K: 5, Vector: []float64{0.1, 0.2}, EF: 100},
+		Filter: &Filter{Field: "category_id", Operator: "eq", Value: float64(3)},
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, query, "query")
+
+	knn := query["knn"].(map[string]interface{})
+	assert.Equal(t, "image_vector", knn["field"])
+	assert.Equal(t, 5, knn["k"])
+	assert.Equal(t, []float64{0.1, 0.2}, knn["query_vector"])
+	assert.Equal(t, 100, knn["ef"])
+	assert.Contains(t, knn, "filter", "filter conditions prefilter the neighbours")
+
+	query, err = qb.BuildHTTPQuery(Args{
+		Table: "images",
+		KNN:   &KNNOptions{Field: "image_vector", K: 5, DocID: 7},
+	})
+	require.NoError(t, err)
+	knn = query["knn"].(map[string]interface{})
+	assert.Equal(t, uint64(7), knn["doc_id"])
+	assert.NotContains(t, knn, "filter")
+}
+
+func TestQueryBuilder_BuildHTTPQueryRejectsWhere(t *testing.T) {
+	qb := NewQueryBuilder("", "images")
+
+	_, err := qb.BuildHTTPQuery(Args{
+		Table: "images",
+		KNN:   &KNNOptions{Field: "image_vector", K: 5, DocID: 7},
+		Where: []string{"category_id = 3"},
+	})
+	require.ErrorIs(t, err, ErrWhereNotSupported)
+}
+
+func TestHandler_convertSearchResponseKNNDist(t *testing.T) {
+	handler := &Handler{}
+	dist := 0.25
+
+	result := handler.convertSearchResponse(&client.SearchResponse{
+		Hits: client.SearchHits{
+			Total: 1,
+			Hits:  []client.SearchHit{{ID: 1, Source: map[string]interface{}{}, KNNDist: &dist}},
+		},
+	})
+	require.Len(t, result.Hits, 1)
+	assert.InDelta(t, 0.25, result.Hits[0]["knn_dist"], 0.0001)
+}
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -15,6 +16,7 @@ var (
 	ErrInvalidQueryStringClauseData = errors.New("invalid query_string clause data")
 	ErrInvalidBoolClauseData        = errors.New("invalid bool clause data")
 	ErrUnsupportedClauseType        = errors.New("unsupported query clause type")
+	ErrWhereNotSupported            = errors.New("raw where conditions are only supported for SQL searches, use filter instead")
 )
 
 // QueryBuilder helps construct complex search queries
@@ -33,53 +35,53 @@ func NewQueryBuilder(cluster, table string) *QueryBuilder {
 
 // BoolQuery represents a boolean query with must, should, must_not clauses
 type BoolQuery struct {
-	Must    []QueryClause `json:"must,omitempty"`
-	Should  []QueryClause `json:"should,omitempty"`
-	MustNot []QueryClause `json:"must_not,omitempty"`
+	Must    []QueryClause `json:"must,omitempty" description:"Clauses that must match (AND)"`
+	Should  []QueryClause `json:"should,omitempty" description:"Clauses of which at least one should match (OR)"`
+	MustNot []QueryClause `json:"must_not,omitempty" description:"Clauses that must not match (NOT)"`
 }
 
 // QueryClause represents different types of query clauses
 type QueryClause struct {
-	Type string `json:"type"`
-	Data any    `json:"data"`
+	Type string `json:"type" jsonschema:"required,enum=match,enum=range,enum=equals,enum=in,enum=geo_distance,enum=query_string,enum=match_all,enum=bool" description:"Clause type"`
+	Data any    `json:"data" description:"Clause parameters, shape depends on type"`
 }
 
 // MatchClause represents a match query
 type MatchClause struct {
-	Field    string `json:"field"`
-	Query    string `json:"query"`
-	Operator string `json:"operator,omitempty"` // "and" or "or"
+	Field    string `json:"field" jsonschema:"required" description:"Full-text field to match, or * for all fields"`
+	Query    string `json:"query" jsonschema:"required" description:"Text to match"`
+	Operator string `json:"operator,omitempty" jsonschema:"enum=and,enum=or" description:"How query terms are combined (default: or)"`
 }
 
 // RangeClause represents a range query
 type RangeClause struct {
-	Field  string                 `json:"field"`
-	Ranges map[string]interface{} `json:"ranges"` // gte, lte, gt, lt
+	Field  string                 `json:"field" jsonschema:"required" description:"Attribute to compare"`
+	Ranges map[string]interface{} `json:"ranges" jsonschema:"required" description:"Bounds keyed by gte, lte, gt, lt"`
 }
 
 // EqualsClause represents an equals query
 type EqualsClause struct {
-	Field string      `json:"field"`
-	Value interface{} `json:"value"`
+	Field string      `json:"field" jsonschema:"required" description:"Attribute to compare"`
+	Value interface{} `json:"value" jsonschema:"required" description:"Value the attribute must equal"`
 }
 
 // InClause represents an IN query
 type InClause struct {
-	Field  string        `json:"field"`
-	Values []interface{} `json:"values"`
+	Field  string        `json:"field" jsonschema:"required" description:"Attribute to compare"`
+	Values []interface{} `json:"values" jsonschema:"required" description:"Accepted attribute values"`
 }
 
 // GeoDistanceClause represents a geo distance query
 type GeoDistanceClause struct {
-	DistanceType   string             `json:"distance_type"`
-	LocationAnchor map[string]float64 `json:"location_anchor"`
-	LocationSource string             `json:"location_source"`
-	Distance       string             `json:"distance"`
+	DistanceType   string             `json:"distance_type" jsonschema:"enum=adaptive,enum=haversine" description:"Distance calculation algorithm"`
+	LocationAnchor map[string]float64 `json:"location_anchor" jsonschema:"required" description:"Anchor point with lat and lon"`
+	LocationSource string             `json:"location_source" jsonschema:"required" description:"Attributes holding lat and lon, e.g. lat,lon"`
+	Distance       string             `json:"distance" jsonschema:"required" description:"Maximum distance, e.g. 100 km"`
 }
 
 // QueryStringClause represents a query_string query
 type QueryStringClause struct {
-	Query string `json:"query"`
+	Query string `json:"query" jsonschema:"required" description:"Query in Manticore full-text syntax"`
 }
 
 // MatchAllClause represents a match_all query
@@ -87,6 +89,11 @@ type MatchAllClause struct{}
 
 // BuildHTTPQuery constructs HTTP JSON query from complex search arguments
 func (qb *QueryBuilder) BuildHTTPQuery(args Args) (map[string]interface{}, error) {
+	// Raw SQL fragments cannot be expressed in a JSON query and must not be silently dropped
+	if len(args.Where) > 0 {
+		return nil, ErrWhereNotSupported
+	}
+
 	query := make(map[string]interface{})
 	query["table"] = qb.buildTableName()
 
@@ -111,6 +118,32 @@ func (qb *QueryBuilder) BuildHTTPQuery(args Args) (map[string]interface{}, error
 		}
 	}
 
+	// Combine structured filter with the main query
+	if args.Filter != nil {
+		filterQuery, err := BuildFilterQuery(*args.Filter)
+		if err != nil {
+			return nil, err
+		}
+		query["query"] = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []interface{}{query["query"], filterQuery},
+			},
+		}
+	}
+
+	// Vector search replaces the main query, which is kept as a prefilter
+	if args.KNN != nil {
+		knn, err := BuildKNNQuery(*args.KNN)
+		if err != nil {
+			return nil, err
+		}
+		if args.BoolQuery != nil || args.Query != "" || args.Filter != nil {
+			knn["filter"] = query["query"]
+		}
+		delete(query, "query")
+		query["knn"] = knn
+	}
+
 	// Add pagination
 	if args.Limit > 0 {
 		query["limit"] = args.Limit
@@ -140,6 +173,15 @@ func (qb *QueryBuilder) BuildHTTPQuery(args Args) (map[string]interface{}, error
 		query["sort"] = sort
 	}
 
+	// Add facets
+	if len(args.Facets) > 0 {
+		aggs, err := BuildFacetAggs(args.Facets)
+		if err != nil {
+			return nil, err
+		}
+		query["aggs"] = aggs
+	}
+
 	// Add highlighting
 	if args.Highlight != nil && args.Highlight.Enabled {
 		highlight := qb.buildHighlightOptions(*args.Highlight)
@@ -228,7 +270,7 @@ func (qb *QueryBuilder) buildSingleClause(clause QueryClause) (map[string]interf
 
 // buildMatchClauseFromData constructs a match clause from interface data
 func (qb *QueryBuilder) buildMatchClauseFromData(data interface{}) (map[string]interface{}, error) {
-	matchData, ok := data.(MatchClause)
+	matchData, ok := decodeClauseData[MatchClause](data)
 	if !ok {
 		return nil, ErrInvalidMatchClauseData
 	}
@@ -237,7 +279,7 @@ func (qb *QueryBuilder) buildMatchClauseFromData(data interface{}) (map[string]i
 
 // buildRangeClauseFromData constructs a range clause from interface data
 func (qb *QueryBuilder) buildRangeClauseFromData(data interface{}) (map[string]interface{}, error) {
-	rangeData, ok := data.(RangeClause)
+	rangeData, ok := decodeClauseData[RangeClause](data)
 	if !ok {
 		return nil, ErrInvalidRangeClauseData
 	}
@@ -246,7 +288,7 @@ func (qb *QueryBuilder) buildRangeClauseFromData(data interface{}) (map[string]i
 
 // buildEqualsClauseFromData constructs an equals clause from interface data
 func (qb *QueryBuilder) buildEqualsClauseFromData(data interface{}) (map[string]interface{}, error) {
-	equalsData, ok := data.(EqualsClause)
+	equalsData, ok := decodeClauseData[EqualsClause](data)
 	if !ok {
 		return nil, ErrInvalidEqualsClauseData
 	}
@@ -255,7 +297,7 @@ func (qb *QueryBuilder) buildEqualsClauseFromData(data interface{}) (map[string]
 
 // buildInClauseFromData constructs an in clause from interface data
 func (qb *QueryBuilder) buildInClauseFromData(data interface{}) (map[string]interface{}, error) {
-	inData, ok := data.(InClause)
+	inData, ok := decodeClauseData[InClause](data)
 	if !ok {
 		return nil, ErrInvalidInClauseData
 	}
@@ -264,7 +306,7 @@ func (qb *QueryBuilder) buildInClauseFromData(data interface{}) (map[string]inte
 
 // buildGeoDistanceClauseFromData constructs a geo_distance clause from interface data
 func (qb *QueryBuilder) buildGeoDistanceClauseFromData(data interface{}) (map[string]interface{}, error) {
-	geoData, ok := data.(GeoDistanceClause)
+	geoData, ok := decodeClauseData[GeoDistanceClause](data)
 	if !ok {
 		return nil, ErrInvalidGeoDistanceClauseData
 	}
@@ -273,7 +315,7 @@ func (qb *QueryBuilder) buildGeoDistanceClauseFromData(data interface{}) (map[st
 
 // buildQueryStringClauseFromData constructs a query_string clause from interface data
 func (qb *QueryBuilder) buildQueryStringClauseFromData(data interface{}) (map[string]interface{}, error) {
-	queryStringData, ok := data.(QueryStringClause)
+	queryStringData, ok := decodeClauseData[QueryStringClause](data)
 	if !ok {
 		return nil, ErrInvalidQueryStringClauseData
 	}
@@ -289,13 +331,37 @@ func (qb *QueryBuilder) buildMatchAllClause() (map[string]interface{}, error) {
 
 // buildBoolClauseFromData constructs a bool clause from interface data
 func (qb *QueryBuilder) buildBoolClauseFromData(data interface{}) (map[string]interface{}, error) {
-	boolData, ok := data.(BoolQuery)
+	boolData, ok := decodeClauseData[BoolQuery](data)
 	if !ok {
 		return nil, ErrInvalidBoolClauseData
 	}
 	return qb.buildBoolQuery(boolData)
 }
 
+// decodeClauseData converts clause data to the expected clause type.
+// Data may be the typed clause itself or a map decoded from JSON tool arguments.
+func decodeClauseData[T any](data interface{}) (T, bool) {
+	if typed, ok := data.(T); ok {
+		return typed, true
+	}
+
+	var typed T
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return typed, false
+	}
+
+	raw, err := json.Marshal(dataMap)
+	if err != nil {
+		return typed, false
+	}
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return typed, false
+	}
+
+	return typed, true
+}
+
 // buildMatchClause constructs a match clause
 func (qb *QueryBuilder) buildMatchClause(match MatchClause) map[string]interface{} {
 	if match.Operator != "" {
@@ -309,6 +309,38 @@ func TestQueryBuilder_BuildQueryClauses(t *testing.T) {
 		assert.Len(t, must, 1)
 	})
 
+	t.Run("ClauseDataFromMap", func(t *testing.T) {
+		clauses := []QueryClause{
+			{
+				Type: "bool",
+				Data: map[string]interface{}{
+					"must_not": []interface{}{
+						map[string]interface{}{
+							"type": "equals",
+							"data": map[string]interface{}{"field": "status", "value": "disabled"},
+						},
+					},
+				},
+			},
+			{
+				Type: "match",
+				Data: map[string]interface{}{"field": "title", "query": "test"},
+			},
+		}
+
+		result, err := qb.buildQueryClauses(clauses)
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+
+		boolQuery := result[0]["bool"].(map[string]interface{})
+		mustNot := boolQuery["must_not"].([]map[string]interface{})
+		equals := mustNot[0]["equals"].(map[string]interface{})
+		assert.Equal(t, "disabled", equals["status"])
+
+		match := result[1]["match"].(map[string]interface{})
+		assert.Equal(t, "test", match["title"])
+	})
+
 	t.Run("InvalidClauseType", func(t *testing.T) {
 		clauses := []QueryClause{
 			{
@@ -2,22 +2,22 @@ package search
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"strings"
+	"time"
 
 	"manticore-mcp-server/client"
 )
 
-var (
-	ErrInvalidQueryFormat   = errors.New("invalid query format")
-	ErrInvalidMatchQuery    = errors.New("invalid match query format")
-	ErrInvalidBoolQuery     = errors.New("invalid bool query format")
-	ErrUnsupportedQueryType = errors.New("unsupported query type")
-	ErrQueryProcessed       = errors.New("query processed")
-)
+// ErrMultipleStatements is returned when a built search query would run more than one statement
+var ErrMultipleStatements = errors.New("search query must be a single statement")
+
+// killTimeout bounds the cleanup of a query abandoned by its caller
+const killTimeout = 5 * time.Second
 
 // Handler handles search-related operations
 type Handler struct {
@@ -37,12 +37,19 @@ func NewHandler(c client.ManticoreClient, logger *slog.Logger) *Handler {
 type Args struct {
 	// Query parameters
 	Query   string `json:"query,omitempty" description:"Simple search query text"`
-	Table   string `json:"table" description:"Table name to search in"`
+	Table   string `json:"table" jsonschema:"required" description:"Table name to search in"`
 	Cluster string `json:"cluster,omitempty" description:"Cluster name (optional)"`
 
 	// Complex boolean query
 	BoolQuery *BoolQuery `json:"bool_query,omitempty" description:"Complex boolean query with must/should/must_not clauses"`
 
+	// Vector search
+	KNN    *KNNOptions    `json:"knn,omitempty" description:"K-nearest-neighbour search over a float_vector attribute, hits carry knn_dist"`
+	Hybrid *HybridOptions `json:"hybrid,omitempty" description:"Fuse the full-text query and the knn search into a single ranked list"`
+
+	// Facets
+	Facets []FacetOptions `json:"facets,omitempty" description:"Facets computed over all matched documents, returned as one bucket list per facet"`
+
 	// Pagination
 	Limit  int `json:"limit,omitempty" description:"Maximum number of results (default: 10)"`
 	Offset int `json:"offset,omitempty" description:"Offset for pagination (default: 0)"`
@@ -51,8 +58,8 @@ type Args struct {
 	Fields []string `json:"fields,omitempty" description:"Fields to return in results (default: all)"`
 
 	// Search options
-	Ranker              string         `json:"ranker,omitempty" description:"Ranking function: proximity_bm25, bm25, none, wordcount, proximity, matchany, fieldmask, sph04, expr, export"`
-	MatchMode           string         `json:"match_mode,omitempty" description:"Match mode: all, any, phrase, boolean, extended (default: extended)"`
+	Ranker              string         `json:"ranker,omitempty" jsonschema:"enum=proximity_bm25,enum=bm25,enum=none,enum=wordcount,enum=proximity,enum=matchany,enum=fieldmask,enum=sph04,enum=expr,enum=export" description:"Ranking function: proximity_bm25, bm25, none, wordcount, proximity, matchany, fieldmask, sph04, expr, export"`
+	MatchMode           string         `json:"match_mode,omitempty" jsonschema:"enum=all,enum=any,enum=phrase,enum=boolean,enum=extended" description:"Match mode: all, any, phrase, boolean, extended (default: extended)"`
 	MaxMatches          int            `json:"max_matches,omitempty" description:"Maximum matches to retain in RAM (default: 1000)"`
 	Cutoff              int            `json:"cutoff,omitempty" description:"Maximum matches to process (0 = no limit)"`
 	MaxQueryTime        int            `json:"max_query_time,omitempty" description:"Maximum query time in milliseconds (0 = no limit)"`
@@ -68,6 +75,7 @@ type Args struct {
 	Morphology          string         `json:"morphology,omitempty" description:"Set to 'none' to disable stemming/lemmatizing"`
 	TokenFilter         string         `json:"token_filter,omitempty" description:"Query-time token filter (lib:plugin:settings)"`
 	MaxPredictedTime    int            `json:"max_predicted_time,omitempty" description:"Maximum predicted search time"`
+	TimeoutMs           int            `json:"timeout_ms,omitempty" description:"Abort the call after this many milliseconds, also applied as max_query_time (0 = server default)"`
 
 	// Ordering
 	OrderBy   []string `json:"order_by,omitempty" description:"Order by fields (e.g., ['weight() DESC', 'id ASC'])"`
@@ -81,7 +89,8 @@ type Args struct {
 	Fuzzy *FuzzyOptions `json:"fuzzy,omitempty" description:"Fuzzy search options"`
 
 	// Filtering
-	Where []string `json:"where,omitempty" description:"Additional WHERE conditions"`
+	Filter *Filter  `json:"filter,omitempty" description:"Structured filter conditions combined with the full-text query"`
+	Where  []string `json:"where,omitempty" description:"Raw SQL WHERE conditions (rejected unless the server allows raw where)"`
 
 	// Query mode
 	UseHTTP bool `json:"use_http,omitempty" description:"Use HTTP JSON API instead of SQL (supports complex boolean queries)"`
@@ -100,6 +109,24 @@ type HighlightOptions struct {
 	NumberFragments int      `json:"number_of_fragments,omitempty" description:"Number of fragments to return"`
 }
 
+// Result represents search hits together with totals reported by Manticore
+type Result struct {
+	Hits          []map[string]interface{} `json:"hits"`
+	Total         int                      `json:"total"`
+	TotalRelation string                   `json:"total_relation,omitempty"`
+	QueryTimeMs   float64                  `json:"query_time_ms"`
+	Keywords      []KeywordStat            `json:"keywords,omitempty"`
+	Facets        []Facet                  `json:"facets,omitempty"`
+	HasMore       bool                     `json:"has_more"`
+}
+
+// KeywordStat represents per-keyword statistics reported by SHOW META
+type KeywordStat struct {
+	Keyword string `json:"keyword"`
+	Docs    int    `json:"docs"`
+	Hits    int    `json:"hits"`
+}
+
 // FuzzyOptions represents fuzzy search configuration
 type FuzzyOptions struct {
 	Enabled  bool     `json:"enabled,omitempty" description:"Enable fuzzy search"`
@@ -109,23 +136,64 @@ type FuzzyOptions struct {
 }
 
 // Execute performs full-text search in Manticore index
-func (h *Handler) Execute(ctx context.Context, args Args) ([]map[string]interface{}, error) {
+func (h *Handler) Execute(ctx context.Context, args Args) (*Result, error) {
 	if args.Table == "" {
 		return nil, fmt.Errorf("table parameter is required")
 	}
 
+	// A per-call timeout also stops the query on the Manticore side
+	if args.TimeoutMs > 0 && (args.MaxQueryTime <= 0 || args.MaxQueryTime > args.TimeoutMs) {
+		args.MaxQueryTime = args.TimeoutMs
+	}
+
+	// Tag cancelable queries so they can be found and killed once abandoned
+	var marker string
+	if ctx.Done() != nil {
+		marker = "mcp-" + rand.Text()
+		args.Comment = strings.TrimSpace(marker + " " + args.Comment)
+	}
+
+	var (
+		result *Result
+		err    error
+	)
+
 	// Check if we need to use HTTP API for complex queries
-	if args.UseHTTP || args.BoolQuery != nil {
-		return h.executeHTTPQuery(ctx, args)
+	if args.Hybrid != nil {
+		result, err = h.executeHybridQuery(ctx, args)
+	} else if args.UseHTTP || args.BoolQuery != nil {
+		result, err = h.executeHTTPQuery(ctx, args)
+	} else {
+		// Use SQL for simple queries
+		result, err = h.executeSQLQuery(ctx, args)
+	}
+	if err != nil {
+		if marker != "" && ctx.Err() != nil {
+			h.killAbandoned(ctx, marker)
+		}
+		return nil, err
 	}
 
-	// Use SQL for simple queries
-	return h.executeSQLQuery(ctx, args)
+	result.HasMore = args.Offset+len(result.Hits) < result.Total
+	return result, nil
+}
+
+// killAbandoned stops a query that keeps running on Manticore after its caller gave up
+func (h *Handler) killAbandoned(ctx context.Context, marker string) {
+	killCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), killTimeout)
+	defer cancel()
+
+	killed, err := h.client.KillQuery(killCtx, marker)
+	if err != nil {
+		h.logger.Warn("Failed to kill abandoned query", "marker", marker, "error", err)
+		return
+	}
+	h.logger.Debug("Killed abandoned query", "marker", marker, "threads", killed)
 }
 
 // executeSQLQuery performs search using SQL interface
-func (h *Handler) executeSQLQuery(ctx context.Context, args Args) ([]map[string]interface{}, error) {
-	if args.Query == "" && len(args.Where) == 0 {
+func (h *Handler) executeSQLQuery(ctx context.Context, args Args) (*Result, error) {
+	if args.Query == "" && len(args.Where) == 0 && args.Filter == nil && args.KNN == nil {
 		return nil, fmt.Errorf("query parameter is required for SQL search when no WHERE conditions are provided")
 	}
 
@@ -143,208 +211,158 @@ func (h *Handler) executeSQLQuery(ctx context.Context, args Args) ([]map[string]
 		return nil, fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	h.logger.Debug("Executing SQL search query", "sql", sql)
-
-	result, err := h.client.ExecuteSQL(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("SQL search failed: %w", err)
-	}
-
-	return result, nil
+	return h.querySQL(ctx, sql, args.Facets)
 }
 
-// executeHTTPQuery performs search using HTTP JSON API
-func (h *Handler) executeHTTPQuery(ctx context.Context, args Args) ([]map[string]interface{}, error) {
-	// Set defaults
-	if args.Limit <= 0 {
-		args.Limit = 10
+// querySQL runs a built search query and fills the result from the result sets of its
+// facets and SHOW META
+func (h *Handler) querySQL(ctx context.Context, sql string, facets []FacetOptions) (*Result, error) {
+	// SHOW META is appended below, so the query itself must not stack further statements
+	if len(client.SplitStatements(sql)) != 1 {
+		return nil, ErrMultipleStatements
 	}
 
-	// Build HTTP query
-	qb := NewQueryBuilder(args.Cluster, args.Table)
-	httpQuery, err := qb.BuildHTTPQuery(args)
+	h.logger.Debug("Executing SQL search query", "sql", sql)
+
+	// SHOW META in the same request reports statistics of the search above
+	results, err := h.client.ExecuteSQLResults(ctx, sql+"; SHOW META")
 	if err != nil {
-		return nil, fmt.Errorf("failed to build HTTP query: %w", err)
+		return nil, fmt.Errorf("SQL search failed: %w", err)
 	}
 
-	h.logger.Debug("Executing HTTP search query", "query", httpQuery)
-
-	// Convert to JSON and execute via HTTP
-	// Note: This would require HTTP client implementation
-	// For now, we'll simulate by converting to SQL equivalent
-	return h.simulateHTTPQuery(ctx, httpQuery)
-}
-
-// simulateHTTPQuery simulates HTTP query execution by converting to SQL
-func (h *Handler) simulateHTTPQuery(ctx context.Context, httpQuery map[string]interface{}) ([]map[string]interface{}, error) {
-	// This is a simplified simulation - in practice, you'd need a proper HTTP client
-	// For complex boolean queries, you might need to use Manticore's HTTP endpoint directly
-
-	table, ok := httpQuery["table"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid table in HTTP query")
+	if len(results) == 0 {
+		return &Result{Hits: []map[string]interface{}{}}, nil
 	}
-
-	// Build basic SQL from HTTP query
-	var sql strings.Builder
-	sql.WriteString("SELECT * FROM ")
-	sql.WriteString(table)
-
-	// Add simple WHERE clause based on query
-	if query, exists := httpQuery["query"]; exists {
-		sql.WriteString(" WHERE ")
-		if err := h.appendQueryToSQL(&sql, query); err != nil {
-			return nil, err
-		}
+	if results[0].Error != "" {
+		return nil, fmt.Errorf("SQL search failed: %s", results[0].Error)
 	}
 
-	// Add LIMIT
-	if limit, exists := httpQuery["limit"]; exists {
-		if limitInt, ok := limit.(int); ok && limitInt > 0 {
-			sql.WriteString(" LIMIT ")
-			sql.WriteString(strconv.Itoa(limitInt))
-		}
+	result := &Result{
+		Hits:  results[0].Rows,
+		Total: len(results[0].Rows),
 	}
 
-	// Add OFFSET
-	if offset, exists := httpQuery["offset"]; exists {
-		if offsetInt, ok := offset.(int); ok && offsetInt > 0 {
-			sql.WriteString(" OFFSET ")
-			sql.WriteString(strconv.Itoa(offsetInt))
+	// Every FACET clause returns its own result set between the hits and SHOW META
+	for i, facet := range facets {
+		if i+1 >= len(results) {
+			break
+		}
+		if results[i+1].Error != "" {
+			return nil, fmt.Errorf("SQL search failed: facet %s: %s", facetName(facet), results[i+1].Error)
 		}
+		result.Facets = append(result.Facets, parseFacetSQL(facet, results[i+1]))
 	}
 
-	h.logger.Debug("Simulated HTTP query as SQL", "sql", sql.String())
-
-	result, err := h.client.ExecuteSQL(ctx, sql.String())
-	if err != nil {
-		return nil, fmt.Errorf("simulated HTTP search failed: %w", err)
+	if metaIndex := len(facets) + 1; len(results) > metaIndex {
+		h.applyMeta(result, ParseVariables(results[metaIndex].Rows))
 	}
 
 	return result, nil
 }
 
-// appendQueryToSQL converts query object to SQL WHERE clause
-func (h *Handler) appendQueryToSQL(sql *strings.Builder, query interface{}) error {
-	queryMap, ok := query.(map[string]interface{})
-	if !ok {
-		return ErrInvalidQueryFormat
-	}
-
-	if err := h.handleMatchQuery(sql, queryMap); err != nil {
-		if errors.Is(err, ErrQueryProcessed) {
-			return nil
-		}
-		return err
-	}
-	if h.handleMatchAllQuery(sql, queryMap) {
-		return nil
+// applyMeta fills result totals, timing and keyword statistics from SHOW META values
+func (h *Handler) applyMeta(result *Result, meta map[string]string) {
+	if totalFound, err := strconv.Atoi(meta["total_found"]); err == nil {
+		result.Total = totalFound
 	}
-	if h.handleQueryStringQuery(sql, queryMap) {
-		return nil
+	if relation := meta["total_relation"]; relation != "" {
+		result.TotalRelation = relation
 	}
-	if err := h.handleBoolQuery(sql, queryMap); err != nil {
-		if errors.Is(err, ErrQueryProcessed) {
-			return nil
-		}
-		return err
+	if seconds, err := strconv.ParseFloat(meta["time"], 64); err == nil {
+		result.QueryTimeMs = seconds * 1000
 	}
 
-	return ErrUnsupportedQueryType
-}
-
-// handleMatchQuery processes match queries
-func (h *Handler) handleMatchQuery(sql *strings.Builder, queryMap map[string]interface{}) error {
-	match, exists := queryMap["match"]
-	if !exists {
-		return nil
-	}
-
-	matchMap, ok := match.(map[string]interface{})
-	if !ok {
-		return ErrInvalidMatchQuery
-	}
-
-	for field, value := range matchMap {
-		if field == "*" {
-			sql.WriteString("MATCH('")
-			sql.WriteString(strings.ReplaceAll(fmt.Sprintf("%v", value), "'", "''"))
-			sql.WriteString("')")
-		} else {
-			sql.WriteString("MATCH('@")
-			sql.WriteString(field)
-			sql.WriteString(" ")
-			sql.WriteString(strings.ReplaceAll(fmt.Sprintf("%v", value), "'", "''"))
-			sql.WriteString("')")
+	for i := 0; ; i++ {
+		index := strconv.Itoa(i)
+		keyword, exists := meta["keyword["+index+"]"]
+		if !exists {
+			break
 		}
-		break // Take first match
+		docs, _ := strconv.Atoi(meta["docs["+index+"]"])
+		hits, _ := strconv.Atoi(meta["hits["+index+"]"])
+		result.Keywords = append(result.Keywords, KeywordStat{
+			Keyword: keyword,
+			Docs:    docs,
+			Hits:    hits,
+		})
 	}
-	return ErrQueryProcessed
 }
 
-// handleMatchAllQuery processes match_all queries
-func (h *Handler) handleMatchAllQuery(sql *strings.Builder, queryMap map[string]interface{}) bool {
-	if _, exists := queryMap["match_all"]; exists {
-		sql.WriteString("1=1") // Match all documents
-		return true
+// ParseVariables converts the Variable_name/Value rows of SHOW META, SHOW TABLE ... STATUS
+// and similar statements into a variable name to value map
+func ParseVariables(rows []map[string]interface{}) map[string]string {
+	meta := make(map[string]string, len(rows))
+	for _, row := range rows {
+		name, ok := row["Variable_name"].(string)
+		if !ok {
+			continue
+		}
+		switch value := row["Value"].(type) {
+		case string:
+			meta[name] = value
+		case float64:
+			meta[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			meta[name] = fmt.Sprintf("%v", value)
+		}
 	}
-	return false
+	return meta
 }
 
-// handleQueryStringQuery processes query_string queries
-func (h *Handler) handleQueryStringQuery(sql *strings.Builder, queryMap map[string]interface{}) bool {
-	if queryString, exists := queryMap["query_string"]; exists {
-		sql.WriteString("MATCH('")
-		sql.WriteString(strings.ReplaceAll(fmt.Sprintf("%v", queryString), "'", "''"))
-		sql.WriteString("')")
-		return true
+// executeHTTPQuery performs search using HTTP JSON API
+func (h *Handler) executeHTTPQuery(ctx context.Context, args Args) (*Result, error) {
+	// Set defaults
+	if args.Limit <= 0 {
+		args.Limit = 10
 	}
-	return false
-}
 
-// handleBoolQuery processes bool queries
-func (h *Handler) handleBoolQuery(sql *strings.Builder, queryMap map[string]interface{}) error {
-	boolQuery, exists := queryMap["bool"]
-	if !exists {
-		return nil
+	// Build HTTP query
+	qb := NewQueryBuilder(args.Cluster, args.Table)
+	httpQuery, err := qb.BuildHTTPQuery(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build HTTP query: %w", err)
 	}
 
-	boolMap, ok := boolQuery.(map[string]interface{})
-	if !ok {
-		return ErrInvalidBoolQuery
-	}
+	h.logger.Debug("Executing HTTP search query", "query", httpQuery)
 
-	conditions, err := h.processMustClauses(boolMap)
+	response, err := h.client.Search(ctx, httpQuery)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("HTTP search failed: %w", err)
 	}
 
-	if len(conditions) > 0 {
-		sql.WriteString(strings.Join(conditions, " AND "))
-	} else {
-		sql.WriteString("1=1")
+	result := h.convertSearchResponse(response)
+	if len(args.Facets) > 0 {
+		result.Facets = parseFacetAggs(args.Facets, response.Aggregations)
 	}
-	return ErrQueryProcessed
+
+	return result, nil
 }
 
-// processMustClauses processes must clauses in bool queries
-func (h *Handler) processMustClauses(boolMap map[string]interface{}) ([]string, error) {
-	var conditions []string
-
-	if must, exists := boolMap["must"]; exists {
-		mustSlice, ok := must.([]interface{})
-		if ok {
-			for _, mustClause := range mustSlice {
-				var subSQL strings.Builder
-				if err := h.appendQueryToSQL(&subSQL, mustClause); err != nil {
-					return nil, err
-				}
-				conditions = append(conditions, "("+subSQL.String()+")")
-			}
+// convertSearchResponse flattens JSON hits into rows shaped like SQL results
+func (h *Handler) convertSearchResponse(response *client.SearchResponse) *Result {
+	hits := make([]map[string]interface{}, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		row := make(map[string]interface{}, len(hit.Source)+3)
+		for field, value := range hit.Source {
+			row[field] = value
+		}
+		row["id"] = hit.ID
+		row["_score"] = hit.Score
+		if hit.KNNDist != nil {
+			row["knn_dist"] = *hit.KNNDist
 		}
+		if len(hit.Highlight) > 0 {
+			row["highlight"] = hit.Highlight
+		}
+		hits = append(hits, row)
 	}
 
-	return conditions, nil
+	return &Result{
+		Hits:          hits,
+		Total:         response.Hits.Total,
+		TotalRelation: response.Hits.TotalRelation,
+		QueryTimeMs:   float64(response.Took),
+	}
 }
 
 // buildSQL constructs the complete SQL query for simple searches
@@ -352,6 +370,13 @@ func (h *Handler) buildSQL(args Args) (string, error) {
 	var sql strings.Builder
 
 	// SELECT clause
+	if err := h.validateSQLIdentifiers(args); err != nil {
+		return "", err
+	}
+	if err := h.validateSQLOptions(args); err != nil {
+		return "", err
+	}
+
 	sql.WriteString("SELECT ")
 	if len(args.Fields) > 0 {
 		sql.WriteString(strings.Join(args.Fields, ", "))
@@ -368,18 +393,46 @@ func (h *Handler) buildSQL(args Args) (string, error) {
 		}
 	}
 
+	// Distance to the query vector
+	if args.KNN != nil {
+		sql.WriteString(", knn_dist() AS knn_dist")
+	}
+
 	// FROM clause with cluster support
 	sql.WriteString(" FROM ")
 	tableName := h.buildTableName(args.Cluster, args.Table)
 	sql.WriteString(tableName)
 
 	// WHERE clause
-	sql.WriteString(" WHERE MATCH('")
-	escapedQuery := strings.ReplaceAll(args.Query, "'", "''")
-	sql.WriteString(escapedQuery)
-	sql.WriteString("')")
+	sql.WriteString(" WHERE ")
+	if args.KNN != nil {
+		condition, err := BuildKNNSQL(*args.KNN)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(condition)
+
+		// Without a full-text query the neighbours are only prefiltered by attributes
+		if args.Query != "" {
+			sql.WriteString(" AND ")
+			sql.WriteString(h.buildMatch(args.Query))
+		}
+	} else {
+		sql.WriteString(h.buildMatch(args.Query))
+	}
+
+	// Structured filter conditions
+	if args.Filter != nil {
+		condition, err := BuildFilterSQL(*args.Filter)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(" AND (")
+		sql.WriteString(condition)
+		sql.WriteString(")")
+	}
 
-	// Additional WHERE conditions
+	// Raw WHERE conditions
 	for _, condition := range args.Where {
 		sql.WriteString(" AND (")
 		sql.WriteString(condition)
@@ -422,9 +475,90 @@ func (h *Handler) buildSQL(args Args) (string, error) {
 		sql.WriteString(options)
 	}
 
+	// FACET clauses
+	if len(args.Facets) > 0 {
+		facets, err := BuildFacetSQL(args.Facets)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(" ")
+		sql.WriteString(facets)
+	}
+
 	return sql.String(), nil
 }
 
+// buildMatch constructs the MATCH() full-text condition
+func (h *Handler) buildMatch(query string) string {
+	return "MATCH('" + escapeString(query) + "')"
+}
+
+// validateSQLIdentifiers rejects table names, select fields, grouping and ordering expressions
+// that are not plain identifiers
+func (h *Handler) validateSQLIdentifiers(args Args) error {
+	if err := ValidateName(args.Table); err != nil {
+		return fmt.Errorf("invalid table: %w", err)
+	}
+	if args.Cluster != "" {
+		if err := ValidateName(args.Cluster); err != nil {
+			return fmt.Errorf("invalid cluster: %w", err)
+		}
+	}
+	for _, field := range args.Fields {
+		if err := validateSelectExpr(field); err != nil {
+			return fmt.Errorf("invalid fields: %w", err)
+		}
+	}
+	for _, field := range args.GroupBy {
+		if err := ValidateIdentifier(field); err != nil {
+			return fmt.Errorf("invalid group_by: %w", err)
+		}
+	}
+	for _, expr := range args.OrderBy {
+		if err := validateOrderExpr(expr); err != nil {
+			return fmt.Errorf("invalid order_by: %w", err)
+		}
+	}
+	if args.GroupSort != "" {
+		for _, expr := range strings.Split(args.GroupSort, ",") {
+			if err := validateOrderExpr(expr); err != nil {
+				return fmt.Errorf("invalid group_sort: %w", err)
+			}
+		}
+	}
+	for field := range args.FieldWeights {
+		if err := ValidateIdentifier(field); err != nil {
+			return fmt.Errorf("invalid field_weights: %w", err)
+		}
+	}
+	if args.Highlight != nil {
+		for _, field := range args.Highlight.Fields {
+			if err := ValidateName(field); err != nil {
+				return fmt.Errorf("invalid highlight fields: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSQLOptions rejects option values that are rendered into the OPTION clause unquoted
+func (h *Handler) validateSQLOptions(args Args) error {
+	if args.Ranker != "" && !rankers[args.Ranker] {
+		return fmt.Errorf("%w: unsupported ranker %q", ErrInvalidOption, args.Ranker)
+	}
+	if args.Morphology != "" && args.Morphology != "none" {
+		return fmt.Errorf("%w: morphology only accepts none", ErrInvalidOption)
+	}
+	if args.Fuzzy != nil {
+		for _, layout := range args.Fuzzy.Layouts {
+			if !layouts[layout] {
+				return fmt.Errorf("%w: unsupported layout %q", ErrInvalidOption, layout)
+			}
+		}
+	}
+	return nil
+}
+
 // buildOptions constructs the OPTION clause
 func (h *Handler) buildOptions(args Args) string {
 	var options []string
@@ -459,7 +593,7 @@ func (h *Handler) addBasicSQLOptions(options *[]string, args Args) {
 		*options = append(*options, "field_weights=("+strings.Join(weights, ",")+")")
 	}
 	if args.Comment != "" {
-		*options = append(*options, "comment='"+strings.ReplaceAll(args.Comment, "'", "''")+"'")
+		*options = append(*options, "comment='"+escapeString(args.Comment)+"'")
 	}
 }
 
@@ -481,7 +615,7 @@ func (h *Handler) addAdvancedSQLOptions(options *[]string, args Args) {
 		*options = append(*options, "morphology="+args.Morphology)
 	}
 	if args.TokenFilter != "" {
-		*options = append(*options, "token_filter='"+strings.ReplaceAll(args.TokenFilter, "'", "''")+"'")
+		*options = append(*options, "token_filter='"+escapeString(args.TokenFilter)+"'")
 	}
 	if args.MaxPredictedTime > 0 {
 		*options = append(*options, "max_predicted_time="+strconv.Itoa(args.MaxPredictedTime))
@@ -547,11 +681,11 @@ func (h *Handler) buildHighlightFunction(highlight *HighlightOptions, query stri
 	}
 
 	if highlight.StartTag != "" {
-		highlightOpts = append(highlightOpts, "before_match='"+strings.ReplaceAll(highlight.StartTag, "'", "''")+"'")
+		highlightOpts = append(highlightOpts, "before_match='"+escapeString(highlight.StartTag)+"'")
 	}
 
 	if highlight.EndTag != "" {
-		highlightOpts = append(highlightOpts, "after_match='"+strings.ReplaceAll(highlight.EndTag, "'", "''")+"'")
+		highlightOpts = append(highlightOpts, "after_match='"+escapeString(highlight.EndTag)+"'")
 	}
 
 	// Add options map if any options specified
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"manticore-mcp-server/client"
@@ -99,14 +101,14 @@ func (s *SearchTestSuite) TestSimpleSearch() {
 
 	result, err := s.handler.Execute(ctx, args)
 	s.NoError(err)
-	s.NotEmpty(result)
+	s.NotEmpty(result.Hits)
 
 	// Should find at least 2 laptops
-	s.GreaterOrEqual(len(result), 2)
+	s.GreaterOrEqual(len(result.Hits), 2)
 
 	// Check that results contain laptop-related content
 	foundLaptop := false
-	for _, row := range result {
+	for _, row := range result.Hits {
 		if title, ok := row["title"].(string); ok {
 			if title == "laptop computer" || title == "gaming laptop" {
 				foundLaptop = true
@@ -133,7 +135,7 @@ func (s *SearchTestSuite) TestSearchWithFieldWeights() {
 
 	result, err := s.handler.Execute(ctx, args)
 	s.NoError(err)
-	s.NotEmpty(result)
+	s.NotEmpty(result.Hits)
 }
 
 func (s *SearchTestSuite) TestSearchWithHighlighting() {
@@ -155,11 +157,11 @@ func (s *SearchTestSuite) TestSearchWithHighlighting() {
 
 	result, err := s.handler.Execute(ctx, args)
 	s.NoError(err)
-	s.NotEmpty(result)
+	s.NotEmpty(result.Hits)
 
 	// Check for highlight in results
 	foundHighlight := false
-	for _, row := range result {
+	for _, row := range result.Hits {
 		if highlight, ok := row["highlight"].(string); ok && highlight != "" {
 			foundHighlight = true
 			s.Contains(highlight, "<mark>")
@@ -228,11 +230,11 @@ func (s *SearchTestSuite) TestSearchWithFuzzyPositive() {
 
 	result, err := s.handler.Execute(ctx, args)
 	s.NoError(err, "Fuzzy search should work with min_infix_len setting")
-	s.NotEmpty(result, "Should find fuzzy matches")
+	s.NotEmpty(result.Hits, "Should find fuzzy matches")
 
 	// Check that we found the computer-related document
 	foundComputer := false
-	for _, row := range result {
+	for _, row := range result.Hits {
 		if title, ok := row["title"].(string); ok {
 			if title == "computer laptop" {
 				foundComputer = true
@@ -258,13 +260,13 @@ func (s *SearchTestSuite) TestSearchWithOrdering() {
 
 	result, err := s.handler.Execute(ctx, args)
 	s.NoError(err)
-	s.NotEmpty(result)
+	s.NotEmpty(result.Hits)
 
 	// Check ordering by price DESC
-	if len(result) >= 2 {
-		for i := 0; i < len(result)-1; i++ {
-			price1, ok1 := result[i]["price"].(float64)
-			price2, ok2 := result[i+1]["price"].(float64)
+	if len(result.Hits) >= 2 {
+		for i := 0; i < len(result.Hits)-1; i++ {
+			price1, ok1 := result.Hits[i]["price"].(float64)
+			price2, ok2 := result.Hits[i+1]["price"].(float64)
 			if ok1 && ok2 {
 				s.GreaterOrEqual(price1, price2, "Results should be ordered by price DESC")
 			}
@@ -286,7 +288,7 @@ func (s *SearchTestSuite) TestSearchWithFilters() {
 	s.NoError(err)
 
 	// All results should meet the filter criteria
-	for _, row := range result {
+	for _, row := range result.Hits {
 		if price, ok := row["price"].(float64); ok {
 			s.Greater(price, 1000.0, "Price should be greater than 1000")
 		}
@@ -309,7 +311,7 @@ func (s *SearchTestSuite) TestSearchWithPagination() {
 
 	result1, err := s.handler.Execute(ctx, args1)
 	s.NoError(err)
-	s.LessOrEqual(len(result1), 2)
+	s.LessOrEqual(len(result1.Hits), 2)
 
 	// Second page
 	args2 := Args{
@@ -323,9 +325,9 @@ func (s *SearchTestSuite) TestSearchWithPagination() {
 	s.NoError(err)
 
 	// Results should be different between pages
-	if len(result1) > 0 && len(result2) > 0 {
-		id1, _ := result1[0]["id"].(float64)
-		id2, _ := result2[0]["id"].(float64)
+	if len(result1.Hits) > 0 && len(result2.Hits) > 0 {
+		id1, _ := result1.Hits[0]["id"].(float64)
+		id2, _ := result2.Hits[0]["id"].(float64)
 		s.NotEqual(id1, id2, "Different pages should have different results")
 	}
 }
@@ -342,7 +344,7 @@ func (s *SearchTestSuite) TestSearchWithMaxMatches() {
 
 	result, err := s.handler.Execute(ctx, args)
 	s.NoError(err)
-	s.LessOrEqual(len(result), 2, "Should respect max_matches limit")
+	s.LessOrEqual(len(result.Hits), 2, "Should respect max_matches limit")
 }
 
 func (s *SearchTestSuite) TestSearchWithComment() {
@@ -379,6 +381,37 @@ func (s *SearchTestSuite) TestSearchWithRanker() {
 	}
 }
 
+func (s *SearchTestSuite) TestBoolQueryMustNot() {
+	ctx := context.Background()
+
+	args := Args{
+		Table: "test_search_table",
+		BoolQuery: &BoolQuery{
+			Must: []QueryClause{
+				NewMatchClause("*", "laptop", ""),
+			},
+			MustNot: []QueryClause{
+				NewRangeClause("price", map[string]interface{}{"gte": 1600}),
+			},
+		},
+		Highlight: &HighlightOptions{
+			Enabled: true,
+		},
+		Limit: 10,
+	}
+
+	result, err := s.handler.Execute(ctx, args)
+	s.Require().NoError(err)
+	s.Require().Len(result.Hits, 1, "must_not should exclude the expensive gaming laptop")
+	s.Equal(1, result.Total)
+	s.Equal("eq", result.TotalRelation)
+
+	row := result.Hits[0]
+	s.Equal("laptop computer", row["title"])
+	s.Contains(row, "_score")
+	s.Contains(row, "highlight")
+}
+
 func (s *SearchTestSuite) TestSearchErrors() {
 	ctx := context.Background()
 
@@ -428,3 +461,128 @@ func (s *SearchTestSuite) TestSearchWithCluster() {
 func TestSearchSuite(t *testing.T) {
 	suite.Run(t, new(SearchTestSuite))
 }
+
+func TestHandler_ExecuteSQLTotalFound(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			assert.Contains(t, query, "; SHOW META")
+			return []client.SQLResult{
+				{Rows: []map[string]interface{}{{"id": float64(1)}, {"id": float64(2)}}},
+				{Rows: []map[string]interface{}{
+					{"Variable_name": "total", "Value": "2"},
+					{"Variable_name": "total_found", "Value": "50000"},
+					{"Variable_name": "total_relation", "Value": "eq"},
+					{"Variable_name": "time", "Value": "0.012"},
+					{"Variable_name": "keyword[0]", "Value": "laptop"},
+					{"Variable_name": "docs[0]", "Value": "50000"},
+					{"Variable_name": "hits[0]", "Value": "71234"},
+				}},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{Table: "products", Query: "laptop", Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, result.Hits, 2)
+	assert.Equal(t, 50000, result.Total)
+	assert.Equal(t, "eq", result.TotalRelation)
+	assert.InDelta(t, 12.0, result.QueryTimeMs, 0.001)
+	assert.Equal(t, []KeywordStat{{Keyword: "laptop", Docs: 50000, Hits: 71234}}, result.Keywords)
+	assert.True(t, result.HasMore)
+}
+
+func TestHandler_ExecuteRejectsStackedStatements(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	_, err := handler.Execute(context.Background(), Args{Table: "products", Query: "laptop", Where: []string{"1=1); DROP TABLE products; SELECT (1"}})
+	require.ErrorIs(t, err, ErrMultipleStatements)
+	assert.Empty(t, mockClient.ExecuteSQLResultsCalls())
+}
+
+func TestHandler_ExecuteHTTPHasMore(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		SearchFunc: func(_ context.Context, _ map[string]interface{}) (*client.SearchResponse, error) {
+			return &client.SearchResponse{
+				Took: 3,
+				Hits: client.SearchHits{
+					Total:         3,
+					TotalRelation: "eq",
+					Hits: []client.SearchHit{
+						{ID: 3, Score: 1, Source: map[string]interface{}{"title": "last"}},
+					},
+				},
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	result, err := handler.Execute(context.Background(), Args{Table: "products", Query: "laptop", UseHTTP: true, Limit: 1, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.InDelta(t, 3.0, result.QueryTimeMs, 0.001)
+	assert.False(t, result.HasMore, "last page should not report more results")
+}
+
+func TestHandler_ExecuteTimeoutSetsMaxQueryTime(t *testing.T) {
+	var sql string
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(_ context.Context, query string) ([]client.SQLResult, error) {
+			sql = query
+			return []client.SQLResult{{Rows: []map[string]interface{}{}}}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	_, err := handler.Execute(context.Background(), Args{Table: "products", Query: "laptop", TimeoutMs: 250})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "max_query_time=250")
+
+	_, err = handler.Execute(context.Background(), Args{Table: "products", Query: "laptop", TimeoutMs: 250, MaxQueryTime: 100})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "max_query_time=100", "a lower max_query_time is kept")
+}
+
+func TestHandler_ExecuteKillsAbandonedQuery(t *testing.T) {
+	var (
+		sql    string
+		killed string
+	)
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLResultsFunc: func(ctx context.Context, query string) ([]client.SQLResult, error) {
+			sql = query
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		KillQueryFunc: func(ctx context.Context, marker string) (int, error) {
+			require.NoError(t, ctx.Err(), "kill must not use the abandoned context")
+			killed = marker
+			return 1, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	handler := NewHandler(mockClient, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := handler.Execute(ctx, Args{Table: "products", Query: "laptop", Comment: "report"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotEmpty(t, killed)
+	assert.Contains(t, sql, "comment='"+killed+" report'")
+}
@@ -0,0 +1,124 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"manticore-mcp-server/tools/search"
+)
+
+// AlterTableArgs represents arguments for alter_table tool
+type AlterTableArgs struct {
+	Table                 string         `json:"table" jsonschema:"required" description:"Table name to alter"`
+	Cluster               string         `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	AddColumns            []Column       `json:"add_columns,omitempty" description:"Columns to add"`
+	DropColumns           []string       `json:"drop_columns,omitempty" description:"Names of columns to drop"`
+	Settings              *TableSettings `json:"settings,omitempty" description:"Table settings to change"`
+	RebuildSecondaryIndex bool           `json:"rebuild_secondary_index,omitempty" description:"Rebuild the secondary indexes of the table"`
+	Desired               *TableSchema   `json:"desired,omitempty" description:"Desired schema to diff against DESCRIBE instead of listing changes; columns missing from it are dropped"`
+	Preview               bool           `json:"preview,omitempty" description:"Only return the ALTER statements without running them"`
+}
+
+// AlterTableResult holds the ALTER statements that were run or, in preview mode, would run
+type AlterTableResult struct {
+	Statements []string `json:"statements"`
+	Preview    bool     `json:"preview,omitempty"`
+}
+
+// AlterTable adds and drops columns, changes settings and rebuilds secondary indexes of a table.
+// With a desired schema the changes are the minimal statements reaching it from the current
+// DESCRIBE and SHOW TABLE ... SETTINGS output.
+func (h *Handler) AlterTable(ctx context.Context, args AlterTableArgs) (*AlterTableResult, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("table parameter is required")
+	}
+	if err := search.ValidateName(args.Table); err != nil {
+		return nil, err
+	}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	tableName := h.buildTableName(args.Cluster, args.Table)
+
+	var (
+		statements []string
+		err        error
+	)
+	if args.Desired != nil {
+		if len(args.AddColumns) > 0 || len(args.DropColumns) > 0 || args.Settings != nil {
+			return nil, fmt.Errorf("desired cannot be combined with add_columns, drop_columns or settings")
+		}
+
+		statements, err = h.DiffTable(ctx, args.Table, args.Cluster, *args.Desired)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		statements, err = alterStatements(tableName, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(statements) == 0 && !args.RebuildSecondaryIndex {
+			return nil, fmt.Errorf("either add_columns, drop_columns, settings, rebuild_secondary_index or desired parameter is required")
+		}
+	}
+
+	if args.RebuildSecondaryIndex {
+		statements = append(statements, "ALTER TABLE "+tableName+" REBUILD SECONDARYINDEX")
+	}
+
+	result := &AlterTableResult{Statements: make([]string, 0, len(statements)), Preview: args.Preview}
+	if args.Preview {
+		result.Statements = append(result.Statements, statements...)
+		return result, nil
+	}
+
+	for _, statement := range statements {
+		h.logger.Debug("Executing alter table query", "sql", statement)
+
+		if _, err := h.client.ExecuteSQL(ctx, statement); err != nil {
+			return result, fmt.Errorf("alter table failed after %d statements: %w", len(result.Statements), err)
+		}
+		result.Statements = append(result.Statements, statement)
+	}
+
+	return result, nil
+}
+
+// alterStatements renders the explicitly listed column and setting changes
+func alterStatements(tableName string, args AlterTableArgs) ([]string, error) {
+	var statements []string
+	for _, column := range args.AddColumns {
+		definition, err := ColumnDefinition(column)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, "ALTER TABLE "+tableName+" ADD COLUMN "+definition)
+	}
+
+	for _, name := range args.DropColumns {
+		if err := search.ValidateName(name); err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(name, "id") {
+			return nil, fmt.Errorf("%w: id cannot be dropped", ErrInvalidSchema)
+		}
+		statements = append(statements, "ALTER TABLE "+tableName+" DROP COLUMN "+name)
+	}
+
+	if args.Settings != nil {
+		options, err := SettingsOptions(*args.Settings)
+		if err != nil {
+			return nil, err
+		}
+		if len(options) > 0 {
+			statements = append(statements, "ALTER TABLE "+tableName+" "+strings.Join(options, " "))
+		}
+	}
+
+	return statements, nil
+}
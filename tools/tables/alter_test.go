@@ -0,0 +1,176 @@
+package tables
+
+import (
+	"context"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var describeRows = []map[string]interface{}{
+	{"Field": "id", "Type": "bigint", "Properties": ""},
+	{"Field": "title", "Type": "text", "Properties": "indexed stored"},
+	{"Field": "body", "Type": "text", "Properties": "indexed"},
+	{"Field": "stock", "Type": "uint", "Properties": ""},
+	{"Field": "tags", "Type": "mva", "Properties": ""},
+	{"Field": "legacy", "Type": "string", "Properties": ""},
+}
+
+var settingsRows = []map[string]interface{}{
+	{"Variable_name": "settings", "Value": "morphology = stem_en\nmin_infix_len = 3\nrt_mem_limit = 268435456"},
+}
+
+func newSchemaClient() *client.ManticoreClientMock {
+	return &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			switch query {
+			case "DESCRIBE products":
+				return describeRows, nil
+			case "SHOW TABLE products SETTINGS":
+				return settingsRows, nil
+			}
+			return []map[string]interface{}{}, nil
+		},
+	}
+}
+
+func TestParseDescribe(t *testing.T) {
+	columns := ParseDescribe(describeRows)
+
+	assert.Equal(t, []Column{
+		{Name: "title", Type: "text", Indexed: boolPtr(true), Stored: boolPtr(true)},
+		{Name: "body", Type: "text", Indexed: boolPtr(true), Stored: boolPtr(false)},
+		{Name: "stock", Type: "integer"},
+		{Name: "tags", Type: "multi"},
+		{Name: "legacy", Type: "string"},
+	}, columns)
+}
+
+func TestDiffSchema(t *testing.T) {
+	current := ParseDescribe(describeRows)
+	settings := ParseSettings(settingsRows)
+
+	statements, err := DiffSchema("shop:products", current, settings, TableSchema{
+		Columns: []Column{
+			{Name: "title", Type: "text"},
+			{Name: "body", Type: "text", Stored: boolPtr(false)},
+			{Name: "stock", Type: "integer"},
+			{Name: "tags", Type: "multi"},
+			{Name: "price", Type: "float"},
+		},
+		Settings: &TableSettings{Morphology: "stem_en", MinInfixLen: intPtr(2), RTMemLimit: "256M", Engine: "rowwise"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"ALTER TABLE shop:products ADD COLUMN price float",
+		"ALTER TABLE shop:products DROP COLUMN legacy",
+		"ALTER TABLE shop:products min_infix_len='2'",
+	}, statements)
+
+	// An unchanged schema needs no statements
+	statements, err = DiffSchema("products", current, settings, TableSchema{Columns: current})
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+}
+
+func TestDiffSchemaIncompatible(t *testing.T) {
+	current := ParseDescribe(describeRows)
+
+	tests := []struct {
+		name    string
+		columns []Column
+	}{
+		{name: "type change", columns: []Column{{Name: "stock", Type: "bigint"}}},
+		{name: "text flags", columns: []Column{{Name: "body", Type: "text"}}},
+		{name: "duplicate", columns: []Column{{Name: "price", Type: "float"}, {Name: "price", Type: "float"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DiffSchema("products", current, nil, TableSchema{Columns: tt.columns})
+			require.ErrorIs(t, err, ErrInvalidSchema)
+		})
+	}
+}
+
+func TestHandler_AlterTable(t *testing.T) {
+	mockClient := newSchemaClient()
+	handler := newMockHandler(mockClient)
+
+	result, err := handler.AlterTable(context.Background(), AlterTableArgs{
+		Table:                 "products",
+		AddColumns:            []Column{{Name: "embedding", Type: "float_vector", KNNType: "hnsw", KNNDims: 2, HNSWSimilarity: "L2"}},
+		DropColumns:           []string{"legacy"},
+		Settings:              &TableSettings{Stopwords: "en"},
+		RebuildSecondaryIndex: true,
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		"ALTER TABLE products ADD COLUMN embedding float_vector knn_type='hnsw' knn_dims='2' hnsw_similarity='L2'",
+		"ALTER TABLE products DROP COLUMN legacy",
+		"ALTER TABLE products stopwords='en'",
+		"ALTER TABLE products REBUILD SECONDARYINDEX",
+	}
+	assert.Equal(t, expected, result.Statements)
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, len(expected))
+	for i, call := range calls {
+		assert.Equal(t, expected[i], call.Query)
+	}
+}
+
+func TestHandler_AlterTableDesired(t *testing.T) {
+	mockClient := newSchemaClient()
+	handler := newMockHandler(mockClient)
+
+	result, err := handler.AlterTable(context.Background(), AlterTableArgs{
+		Table: "products",
+		Desired: &TableSchema{Columns: []Column{
+			{Name: "title", Type: "text"},
+			{Name: "body", Type: "text", Stored: boolPtr(false)},
+			{Name: "stock", Type: "integer"},
+			{Name: "tags", Type: "multi"},
+			{Name: "legacy", Type: "string"},
+			{Name: "rating", Type: "float"},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ALTER TABLE products ADD COLUMN rating float"}, result.Statements)
+
+	var queries []string
+	for _, call := range mockClient.ExecuteSQLCalls() {
+		queries = append(queries, call.Query)
+	}
+	assert.Equal(t, []string{"DESCRIBE products", "SHOW TABLE products SETTINGS", "ALTER TABLE products ADD COLUMN rating float"}, queries)
+}
+
+func TestHandler_AlterTableInvalid(t *testing.T) {
+	mockClient := newSchemaClient()
+	handler := newMockHandler(mockClient)
+
+	tests := []struct {
+		name string
+		args AlterTableArgs
+	}{
+		{name: "no table", args: AlterTableArgs{DropColumns: []string{"a"}}},
+		{name: "no changes", args: AlterTableArgs{Table: "products"}},
+		{name: "drop id", args: AlterTableArgs{Table: "products", DropColumns: []string{"id"}}},
+		{name: "drop injection", args: AlterTableArgs{Table: "products", DropColumns: []string{"a; DROP TABLE products"}}},
+		{name: "invalid column", args: AlterTableArgs{Table: "products", AddColumns: []Column{{Name: "a", Type: "varchar"}}}},
+		{name: "desired with changes", args: AlterTableArgs{Table: "products", DropColumns: []string{"a"}, Desired: &TableSchema{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.AlterTable(context.Background(), tt.args)
+			require.Error(t, err)
+		})
+	}
+	assert.Empty(t, mockClient.ExecuteSQLCalls())
+}
@@ -0,0 +1,329 @@
+package tables
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/tools/search"
+)
+
+// ErrInvalidSchema is returned when a column or setting of a table definition is invalid
+var ErrInvalidSchema = errors.New("invalid table schema")
+
+// Column types accepted in table definitions
+const (
+	TypeText        = "text"
+	TypeString      = "string"
+	TypeInteger     = "integer"
+	TypeBigint      = "bigint"
+	TypeFloat       = "float"
+	TypeBool        = "bool"
+	TypeTimestamp   = "timestamp"
+	TypeJSON        = "json"
+	TypeMulti       = "multi"
+	TypeMulti64     = "multi64"
+	TypeFloatVector = "float_vector"
+)
+
+var columnTypes = map[string]bool{
+	TypeText: true, TypeString: true, TypeInteger: true, TypeBigint: true, TypeFloat: true, TypeBool: true,
+	TypeTimestamp: true, TypeJSON: true, TypeMulti: true, TypeMulti64: true, TypeFloatVector: true,
+}
+
+var hnswSimilarities = map[string]bool{"L2": true, "IP": true, "COSINE": true}
+
+var (
+	// morphologyPattern matches a comma-separated list of morphology processors such as stem_en,lemmatize_ru_all
+	morphologyPattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\s*,\s*[A-Za-z0-9_]+)*$`)
+	// memLimitPattern matches a size with an optional K, M or G suffix
+	memLimitPattern = regexp.MustCompile(`^[0-9]+[KkMmGg]?$`)
+)
+
+// Column defines a table column
+type Column struct {
+	Name               string `json:"name" jsonschema:"required" description:"Column name"`
+	Type               string `json:"type" jsonschema:"required,enum=text,enum=string,enum=integer,enum=bigint,enum=float,enum=bool,enum=timestamp,enum=json,enum=multi,enum=multi64,enum=float_vector" description:"Column type"`
+	Indexed            *bool  `json:"indexed,omitempty" description:"Full-text index a text column (default: true)"`
+	Stored             *bool  `json:"stored,omitempty" description:"Store the original value of a text column (default: true)"`
+	KNNType            string `json:"knn_type,omitempty" jsonschema:"enum=hnsw" description:"KNN index of a float_vector column"`
+	KNNDims            int    `json:"knn_dims,omitempty" description:"Vector dimensions of a KNN indexed float_vector column"`
+	HNSWSimilarity     string `json:"hnsw_similarity,omitempty" jsonschema:"enum=L2,enum=IP,enum=COSINE" description:"Distance function of a KNN indexed float_vector column"`
+	HNSWM              int    `json:"hnsw_m,omitempty" description:"Maximum connections per HNSW graph node (optional)"`
+	HNSWEfConstruction int    `json:"hnsw_ef_construction,omitempty" description:"HNSW construction time/accuracy trade-off (optional)"`
+}
+
+// TableSettings holds table settings. Empty values leave the server default.
+type TableSettings struct {
+	Morphology   string `json:"morphology,omitempty" description:"Comma-separated morphology processors such as stem_en or lemmatize_en_all"`
+	MinInfixLen  *int   `json:"min_infix_len,omitempty" description:"Minimum infix length to index for wildcard searches (0 disables, otherwise at least 2)"`
+	CharsetTable string `json:"charset_table,omitempty" description:"Accepted characters and case folding rules, such as non_cont or cjk"`
+	Stopwords    string `json:"stopwords,omitempty" description:"Stopword languages such as en or stopword file paths"`
+	Engine       string `json:"engine,omitempty" jsonschema:"enum=columnar,enum=rowwise" description:"Attribute storage"`
+	RTMemLimit   string `json:"rt_mem_limit,omitempty" description:"RAM chunk size limit such as 256M"`
+}
+
+// CreateTableArgs represents arguments for create_table tool
+type CreateTableArgs struct {
+	Table       string         `json:"table" jsonschema:"required" description:"Table name to create"`
+	Columns     []Column       `json:"columns" jsonschema:"required" description:"Columns besides the implicit id"`
+	Settings    *TableSettings `json:"settings,omitempty" description:"Table settings"`
+	IfNotExists bool           `json:"if_not_exists,omitempty" description:"Do nothing if the table already exists"`
+	Cluster     string         `json:"cluster,omitempty" description:"Add the table to this replication cluster (optional)"`
+}
+
+// CreateTableResult holds the statements run to create the table
+type CreateTableResult struct {
+	Statements []string `json:"statements"`
+}
+
+// CreateTable creates a real-time table from a typed column list and settings and optionally
+// adds it to a replication cluster
+func (h *Handler) CreateTable(ctx context.Context, args CreateTableArgs) (*CreateTableResult, error) {
+	statements, err := CreateStatements(args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CreateTableResult{Statements: make([]string, 0, len(statements))}
+	for _, statement := range statements {
+		h.logger.Debug("Executing create table query", "sql", statement)
+
+		if _, err := h.client.ExecuteSQL(ctx, statement); err != nil {
+			return result, fmt.Errorf("create table failed: %w", err)
+		}
+		result.Statements = append(result.Statements, statement)
+	}
+
+	return result, nil
+}
+
+// CreateStatements renders the CREATE TABLE statement of a table definition followed by the
+// ALTER CLUSTER statement adding it to its cluster
+func CreateStatements(args CreateTableArgs) ([]string, error) {
+	sql, err := BuildCreateTable(args)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{sql}
+	if args.Cluster != "" {
+		if err := search.ValidateName(args.Cluster); err != nil {
+			return nil, err
+		}
+		statements = append(statements, "ALTER CLUSTER "+args.Cluster+" ADD "+args.Table)
+	}
+	return statements, nil
+}
+
+// BuildCreateTable validates a table definition and renders its CREATE TABLE statement
+func BuildCreateTable(args CreateTableArgs) (string, error) {
+	if args.Table == "" {
+		return "", fmt.Errorf("table parameter is required")
+	}
+	if err := search.ValidateName(args.Table); err != nil {
+		return "", err
+	}
+	if len(args.Columns) == 0 {
+		return "", fmt.Errorf("columns parameter is required and cannot be empty")
+	}
+
+	seen := make(map[string]bool, len(args.Columns))
+	definitions := make([]string, 0, len(args.Columns))
+	for _, column := range args.Columns {
+		name := strings.ToLower(column.Name)
+		if seen[name] {
+			return "", fmt.Errorf("%w: duplicate column %q", ErrInvalidSchema, column.Name)
+		}
+		seen[name] = true
+
+		definition, err := ColumnDefinition(column)
+		if err != nil {
+			return "", err
+		}
+		definitions = append(definitions, definition)
+	}
+
+	var sql strings.Builder
+	sql.WriteString("CREATE TABLE ")
+	if args.IfNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString(args.Table)
+	sql.WriteString(" (")
+	sql.WriteString(strings.Join(definitions, ", "))
+	sql.WriteString(")")
+
+	if args.Settings != nil {
+		settings, err := SettingsOptions(*args.Settings)
+		if err != nil {
+			return "", err
+		}
+		for _, setting := range settings {
+			sql.WriteString(" ")
+			sql.WriteString(setting)
+		}
+	}
+
+	return sql.String(), nil
+}
+
+// ColumnDefinition validates a column and renders it as used in CREATE TABLE and ALTER TABLE ADD COLUMN
+func ColumnDefinition(column Column) (string, error) {
+	if err := search.ValidateName(column.Name); err != nil {
+		return "", err
+	}
+	if strings.EqualFold(column.Name, "id") {
+		return "", fmt.Errorf("%w: id is an implicit column", ErrInvalidSchema)
+	}
+
+	columnType := strings.ToLower(column.Type)
+	if !columnTypes[columnType] {
+		return "", fmt.Errorf("%w: column %s has unsupported type %q", ErrInvalidSchema, column.Name, column.Type)
+	}
+	if columnType != TypeText && (column.Indexed != nil || column.Stored != nil) {
+		return "", fmt.Errorf("%w: indexed and stored only apply to text column %s", ErrInvalidSchema, column.Name)
+	}
+	if columnType != TypeFloatVector && (column.KNNType != "" || column.KNNDims != 0 || column.HNSWSimilarity != "" ||
+		column.HNSWM != 0 || column.HNSWEfConstruction != 0) {
+		return "", fmt.Errorf("%w: knn options only apply to float_vector column %s", ErrInvalidSchema, column.Name)
+	}
+
+	definition := column.Name + " " + columnType
+	switch columnType {
+	case TypeText:
+		indexed := column.Indexed == nil || *column.Indexed
+		stored := column.Stored == nil || *column.Stored
+		switch {
+		case !indexed && !stored:
+			return "", fmt.Errorf("%w: text column %s must be indexed or stored", ErrInvalidSchema, column.Name)
+		case !stored:
+			definition += " indexed"
+		case !indexed:
+			definition += " stored"
+		}
+	case TypeFloatVector:
+		options, err := knnOptions(column)
+		if err != nil {
+			return "", err
+		}
+		if options != "" {
+			definition += " " + options
+		}
+	}
+
+	return definition, nil
+}
+
+// knnOptions renders the KNN index options of a float_vector column
+func knnOptions(column Column) (string, error) {
+	if column.KNNType == "" {
+		if column.KNNDims != 0 || column.HNSWSimilarity != "" || column.HNSWM != 0 || column.HNSWEfConstruction != 0 {
+			return "", fmt.Errorf("%w: knn options of column %s require knn_type", ErrInvalidSchema, column.Name)
+		}
+		return "", nil
+	}
+
+	if !strings.EqualFold(column.KNNType, "hnsw") {
+		return "", fmt.Errorf("%w: column %s has unsupported knn_type %q", ErrInvalidSchema, column.Name, column.KNNType)
+	}
+	if column.KNNDims <= 0 {
+		return "", fmt.Errorf("%w: column %s requires positive knn_dims", ErrInvalidSchema, column.Name)
+	}
+	similarity := strings.ToUpper(column.HNSWSimilarity)
+	if !hnswSimilarities[similarity] {
+		return "", fmt.Errorf("%w: column %s requires hnsw_similarity L2, IP or COSINE", ErrInvalidSchema, column.Name)
+	}
+	if column.HNSWM < 0 || column.HNSWEfConstruction < 0 {
+		return "", fmt.Errorf("%w: hnsw parameters of column %s must not be negative", ErrInvalidSchema, column.Name)
+	}
+
+	options := []string{
+		"knn_type='hnsw'",
+		"knn_dims='" + strconv.Itoa(column.KNNDims) + "'",
+		"hnsw_similarity='" + similarity + "'",
+	}
+	if column.HNSWM > 0 {
+		options = append(options, "hnsw_m='"+strconv.Itoa(column.HNSWM)+"'")
+	}
+	if column.HNSWEfConstruction > 0 {
+		options = append(options, "hnsw_ef_construction='"+strconv.Itoa(column.HNSWEfConstruction)+"'")
+	}
+	return strings.Join(options, " "), nil
+}
+
+// setting is a validated table setting
+type setting struct {
+	name  string
+	value string
+}
+
+// SettingsOptions validates table settings and renders them as name='value' options in a
+// fixed order
+func SettingsOptions(settings TableSettings) ([]string, error) {
+	values, err := settingValues(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]string, 0, len(values))
+	for _, value := range values {
+		option, err := value.option()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// option renders the setting as name='value'
+func (s setting) option() (string, error) {
+	literal, err := search.FormatLiteral(s.value)
+	if err != nil {
+		return "", err
+	}
+	return s.name + "=" + literal, nil
+}
+
+// settingValues validates the non-empty table settings
+func settingValues(settings TableSettings) ([]setting, error) {
+	var values []setting
+
+	if settings.Morphology != "" {
+		if !morphologyPattern.MatchString(settings.Morphology) {
+			return nil, fmt.Errorf("%w: invalid morphology %q", ErrInvalidSchema, settings.Morphology)
+		}
+		values = append(values, setting{"morphology", settings.Morphology})
+	}
+	if settings.MinInfixLen != nil {
+		if *settings.MinInfixLen < 0 || *settings.MinInfixLen == 1 {
+			return nil, fmt.Errorf("%w: min_infix_len must be 0 or at least 2", ErrInvalidSchema)
+		}
+		values = append(values, setting{"min_infix_len", strconv.Itoa(*settings.MinInfixLen)})
+	}
+	if settings.CharsetTable != "" {
+		values = append(values, setting{"charset_table", settings.CharsetTable})
+	}
+	if settings.Stopwords != "" {
+		values = append(values, setting{"stopwords", settings.Stopwords})
+	}
+	if settings.Engine != "" {
+		engine := strings.ToLower(settings.Engine)
+		if engine != "columnar" && engine != "rowwise" {
+			return nil, fmt.Errorf("%w: engine must be columnar or rowwise", ErrInvalidSchema)
+		}
+		values = append(values, setting{"engine", engine})
+	}
+	if settings.RTMemLimit != "" {
+		if !memLimitPattern.MatchString(settings.RTMemLimit) {
+			return nil, fmt.Errorf("%w: invalid rt_mem_limit %q", ErrInvalidSchema, settings.RTMemLimit)
+		}
+		values = append(values, setting{"rt_mem_limit", settings.RTMemLimit})
+	}
+
+	return values, nil
+}
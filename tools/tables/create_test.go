@@ -0,0 +1,137 @@
+package tables
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockHandler(mockClient *client.ManticoreClientMock) *Handler {
+	return NewHandler(mockClient, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestBuildCreateTable(t *testing.T) {
+	sql, err := BuildCreateTable(CreateTableArgs{
+		Table: "products",
+		Columns: []Column{
+			{Name: "title", Type: "text"},
+			{Name: "body", Type: "text", Stored: boolPtr(false)},
+			{Name: "raw", Type: "text", Indexed: boolPtr(false)},
+			{Name: "brand", Type: "string"},
+			{Name: "stock", Type: "integer"},
+			{Name: "views", Type: "bigint"},
+			{Name: "price", Type: "float"},
+			{Name: "in_stock", Type: "bool"},
+			{Name: "created_at", Type: "timestamp"},
+			{Name: "meta", Type: "json"},
+			{Name: "tags", Type: "multi"},
+			{Name: "tags64", Type: "MULTI64"},
+			{Name: "raw_vector", Type: "float_vector"},
+			{Name: "embedding", Type: "float_vector", KNNType: "hnsw", KNNDims: 4, HNSWSimilarity: "cosine", HNSWM: 16, HNSWEfConstruction: 200},
+		},
+		Settings: &TableSettings{
+			Morphology:   "stem_en, lemmatize_ru_all",
+			MinInfixLen:  intPtr(3),
+			CharsetTable: "non_cont",
+			Stopwords:    "en",
+			Engine:       "Columnar",
+			RTMemLimit:   "256M",
+		},
+		IfNotExists: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS products ("+
+		"title text, body text indexed, raw text stored, brand string, stock integer, views bigint, price float, "+
+		"in_stock bool, created_at timestamp, meta json, tags multi, tags64 multi64, raw_vector float_vector, "+
+		"embedding float_vector knn_type='hnsw' knn_dims='4' hnsw_similarity='COSINE' hnsw_m='16' hnsw_ef_construction='200')"+
+		" morphology='stem_en, lemmatize_ru_all' min_infix_len='3' charset_table='non_cont' stopwords='en' engine='columnar' rt_mem_limit='256M'", sql)
+}
+
+func TestBuildCreateTableInvalid(t *testing.T) {
+	text := []Column{{Name: "title", Type: "text"}}
+
+	tests := []struct {
+		name string
+		args CreateTableArgs
+	}{
+		{name: "no table", args: CreateTableArgs{Columns: text}},
+		{name: "invalid table", args: CreateTableArgs{Table: "a b", Columns: text}},
+		{name: "no columns", args: CreateTableArgs{Table: "t"}},
+		{name: "id column", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "id", Type: "bigint"}}}},
+		{name: "duplicate column", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "text"}, {Name: "A", Type: "string"}}}},
+		{name: "invalid column name", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a) engine='x", Type: "text"}}}},
+		{name: "unknown type", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "varchar"}}}},
+		{name: "text neither indexed nor stored", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "text", Indexed: boolPtr(false), Stored: boolPtr(false)}}}},
+		{name: "stored on string", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "string", Stored: boolPtr(true)}}}},
+		{name: "knn on float", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "float", KNNType: "hnsw"}}}},
+		{name: "knn dims without type", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "float_vector", KNNDims: 4}}}},
+		{name: "knn without dims", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "float_vector", KNNType: "hnsw", HNSWSimilarity: "L2"}}}},
+		{name: "knn without similarity", args: CreateTableArgs{Table: "t", Columns: []Column{{Name: "a", Type: "float_vector", KNNType: "hnsw", KNNDims: 4}}}},
+		{name: "invalid morphology", args: CreateTableArgs{Table: "t", Columns: text, Settings: &TableSettings{Morphology: "stem_en' engine='x"}}},
+		{name: "min_infix_len 1", args: CreateTableArgs{Table: "t", Columns: text, Settings: &TableSettings{MinInfixLen: intPtr(1)}}},
+		{name: "invalid engine", args: CreateTableArgs{Table: "t", Columns: text, Settings: &TableSettings{Engine: "memory"}}},
+		{name: "invalid rt_mem_limit", args: CreateTableArgs{Table: "t", Columns: text, Settings: &TableSettings{RTMemLimit: "lots"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildCreateTable(tt.args)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHandler_CreateTable(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, _ string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{}, nil
+		},
+	}
+	handler := newMockHandler(mockClient)
+
+	result, err := handler.CreateTable(context.Background(), CreateTableArgs{
+		Table:    "products",
+		Columns:  []Column{{Name: "title", Type: "text"}, {Name: "price", Type: "float"}},
+		Settings: &TableSettings{CharsetTable: "it's"},
+		Cluster:  "shop",
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		`CREATE TABLE products (title text, price float) charset_table='it\'s'`,
+		"ALTER CLUSTER shop ADD products",
+	}
+	assert.Equal(t, expected, result.Statements)
+
+	calls := mockClient.ExecuteSQLCalls()
+	require.Len(t, calls, 2)
+	for i, call := range calls {
+		assert.Equal(t, expected[i], call.Query)
+	}
+
+	// An invalid cluster is rejected before the table is created
+	_, err = handler.CreateTable(context.Background(), CreateTableArgs{
+		Table:   "products",
+		Columns: []Column{{Name: "title", Type: "text"}},
+		Cluster: "shop; DROP",
+	})
+	require.Error(t, err)
+	assert.Len(t, mockClient.ExecuteSQLCalls(), 2)
+}
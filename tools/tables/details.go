@@ -0,0 +1,251 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"manticore-mcp-server/tools/search"
+)
+
+// TableDetails holds the structure, settings and status of a table
+type TableDetails struct {
+	Table       string            `json:"table"`
+	Columns     []ColumnDetails   `json:"columns"`
+	Settings    map[string]string `json:"settings"`
+	Status      TableStatus       `json:"status"`
+	CreateTable string            `json:"create_table"`
+}
+
+// ColumnDetails describes a column with the flags and options of its definition
+type ColumnDetails struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Properties []string          `json:"properties,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+// TableStatus holds the size counters of SHOW TABLE ... STATUS. Raw keeps every reported value.
+type TableStatus struct {
+	Type             string            `json:"type,omitempty"`
+	Documents        int64             `json:"documents"`
+	KilledDocuments  int64             `json:"killed_documents"`
+	DiskBytes        int64             `json:"disk_bytes"`
+	RAMBytes         int64             `json:"ram_bytes"`
+	RAMChunkBytes    int64             `json:"ram_chunk_bytes"`
+	RAMChunkSegments int64             `json:"ram_chunk_segments"`
+	DiskChunks       int64             `json:"disk_chunks"`
+	Raw              map[string]string `json:"raw"`
+}
+
+// DescribeTableDetails combines DESCRIBE with SHOW CREATE TABLE, SHOW TABLE ... SETTINGS and
+// SHOW TABLE ... STATUS into one structured description
+func (h *Handler) DescribeTableDetails(ctx context.Context, args DescribeTableArgs) (*TableDetails, error) {
+	if err := search.ValidateName(args.Table); err != nil {
+		return nil, err
+	}
+
+	rows, err := h.DescribeTable(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []string{
+		"SHOW CREATE TABLE " + args.Table,
+		"SHOW TABLE " + args.Table + " SETTINGS",
+		"SHOW TABLE " + args.Table + " STATUS",
+	}
+	results := make([][]map[string]interface{}, 0, len(queries))
+	for _, sql := range queries {
+		h.logger.Debug("Executing describe table query", "sql", sql)
+
+		result, err := h.client.ExecuteSQL(ctx, sql)
+		if err != nil {
+			return nil, fmt.Errorf("describe table failed: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	createTable := rowValue(results[0], "Create Table")
+	definitions, createSettings := ParseCreateTable(createTable)
+
+	settings := ParseSettings(results[1])
+	for name, value := range createSettings {
+		if _, ok := settings[name]; !ok {
+			settings[name] = value
+		}
+	}
+
+	details := &TableDetails{
+		Table:       args.Table,
+		Columns:     make([]ColumnDetails, 0, len(rows)),
+		Settings:    settings,
+		Status:      parseStatus(results[2]),
+		CreateTable: createTable,
+	}
+
+	for _, row := range rows {
+		name, _ := row["Field"].(string)
+		columnType, _ := row["Type"].(string)
+		properties, _ := row["Properties"].(string)
+
+		column := ColumnDetails{Name: name, Type: columnType, Properties: strings.Fields(properties)}
+		if definition, ok := definitions[strings.ToLower(name)]; ok {
+			column.Options = definition.Options
+			for _, flag := range definition.Properties {
+				if !slices.Contains(column.Properties, flag) {
+					column.Properties = append(column.Properties, flag)
+				}
+			}
+		}
+		details.Columns = append(details.Columns, column)
+	}
+
+	return details, nil
+}
+
+// ParseCreateTable splits a SHOW CREATE TABLE statement into its column definitions, keyed by
+// lower-cased column name, and the name='value' table settings following the column list
+func ParseCreateTable(sql string) (map[string]ColumnDetails, map[string]string) {
+	columns := make(map[string]ColumnDetails)
+	settings := make(map[string]string)
+
+	start := strings.Index(sql, "(")
+	if start < 0 {
+		return columns, settings
+	}
+
+	// Find the parenthesis closing the column list, skipping quoted values
+	end, depth, quoted := -1, 0, false
+	for i := start; i < len(sql) && end < 0; i++ {
+		switch {
+		case sql[i] == '\\' && quoted:
+			i++
+		case sql[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		case sql[i] == '(':
+			depth++
+		case sql[i] == ')':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return columns, settings
+	}
+
+	for _, definition := range splitQuoted(sql[start+1:end], ',') {
+		words := splitQuoted(definition, ' ', '\n', '\t')
+		if len(words) < 2 {
+			continue
+		}
+
+		name := strings.Trim(words[0], "`")
+		column := ColumnDetails{Name: name, Type: strings.ToLower(words[1])}
+		for _, word := range words[2:] {
+			if key, value, ok := strings.Cut(word, "="); ok {
+				if column.Options == nil {
+					column.Options = make(map[string]string)
+				}
+				column.Options[strings.ToLower(key)] = unquote(value)
+				continue
+			}
+			column.Properties = append(column.Properties, strings.ToLower(word))
+		}
+		columns[strings.ToLower(name)] = column
+	}
+
+	for _, word := range splitQuoted(sql[end+1:], ' ', '\n', '\t') {
+		if key, value, ok := strings.Cut(word, "="); ok {
+			settings[strings.ToLower(key)] = unquote(value)
+		}
+	}
+
+	return columns, settings
+}
+
+// splitQuoted splits text at any of the separators outside single-quoted values and drops
+// empty parts
+func splitQuoted(text string, separators ...byte) []string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+
+	flush := func() {
+		if part := strings.TrimSpace(current.String()); part != "" {
+			parts = append(parts, part)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\' && quoted && i+1 < len(text):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(text[i])
+			continue
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && strings.IndexByte(string(separators), c) >= 0:
+			flush()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	flush()
+
+	return parts
+}
+
+// unquote removes the quotes and escapes of a single-quoted value
+func unquote(value string) string {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return value
+	}
+	value = value[1 : len(value)-1]
+	value = strings.ReplaceAll(value, "\\'", "'")
+	return strings.ReplaceAll(value, "\\\\", "\\")
+}
+
+// parseStatus reads the Variable_name/Value rows of SHOW TABLE ... STATUS
+func parseStatus(rows []map[string]interface{}) TableStatus {
+	status := TableStatus{Raw: search.ParseVariables(rows)}
+	for name, value := range status.Raw {
+		number, _ := strconv.ParseInt(value, 10, 64)
+		switch name {
+		case "index_type", "table_type":
+			status.Type = value
+		case "indexed_documents":
+			status.Documents = number
+		case "killed_documents":
+			status.KilledDocuments = number
+		case "disk_bytes":
+			status.DiskBytes = number
+		case "ram_bytes":
+			status.RAMBytes = number
+		case "ram_chunk":
+			status.RAMChunkBytes = number
+		case "ram_chunk_segments_count":
+			status.RAMChunkSegments = number
+		case "disk_chunks":
+			status.DiskChunks = number
+		}
+	}
+	return status
+}
+
+// rowValue returns a string column of the first row
+func rowValue(rows []map[string]interface{}, column string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	value, _ := rows[0][column].(string)
+	return value
+}
@@ -0,0 +1,104 @@
+package tables
+
+import (
+	"context"
+	"testing"
+
+	"manticore-mcp-server/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const showCreateTable = "CREATE TABLE products (\n" +
+	"id bigint,\n" +
+	"title text indexed stored,\n" +
+	"brand string attribute indexed,\n" +
+	"price float engine='columnar',\n" +
+	"embedding float_vector knn_type='hnsw' knn_dims='4' hnsw_similarity='COSINE'\n" +
+	") charset_table='non_cont, U+00E9->e' min_infix_len='3' morphology='stem_en' stopwords='it\\'s'"
+
+func TestParseCreateTable(t *testing.T) {
+	columns, settings := ParseCreateTable(showCreateTable)
+
+	assert.Equal(t, ColumnDetails{Name: "title", Type: "text", Properties: []string{"indexed", "stored"}}, columns["title"])
+	assert.Equal(t, ColumnDetails{Name: "brand", Type: "string", Properties: []string{"attribute", "indexed"}}, columns["brand"])
+	assert.Equal(t, map[string]string{"engine": "columnar"}, columns["price"].Options)
+	assert.Equal(t, map[string]string{"knn_type": "hnsw", "knn_dims": "4", "hnsw_similarity": "COSINE"}, columns["embedding"].Options)
+	assert.Len(t, columns, 5)
+
+	assert.Equal(t, map[string]string{
+		"charset_table": "non_cont, U+00E9->e",
+		"min_infix_len": "3",
+		"morphology":    "stem_en",
+		"stopwords":     "it's",
+	}, settings)
+
+	columns, settings = ParseCreateTable("")
+	assert.Empty(t, columns)
+	assert.Empty(t, settings)
+}
+
+func TestHandler_DescribeTableDetails(t *testing.T) {
+	mockClient := &client.ManticoreClientMock{
+		ExecuteSQLFunc: func(_ context.Context, query string) ([]map[string]interface{}, error) {
+			switch query {
+			case "DESCRIBE products":
+				return []map[string]interface{}{
+					{"Field": "id", "Type": "bigint", "Properties": ""},
+					{"Field": "title", "Type": "text", "Properties": "indexed stored"},
+					{"Field": "price", "Type": "float", "Properties": "columnar"},
+					{"Field": "embedding", "Type": "float_vector", "Properties": ""},
+				}, nil
+			case "SHOW CREATE TABLE products":
+				return []map[string]interface{}{{"Table": "products", "Create Table": showCreateTable}}, nil
+			case "SHOW TABLE products SETTINGS":
+				return []map[string]interface{}{{"Variable_name": "settings", "Value": "min_infix_len = 3\nmorphology = stem_en\nrt_mem_limit = 268435456"}}, nil
+			case "SHOW TABLE products STATUS":
+				return []map[string]interface{}{
+					{"Variable_name": "table_type", "Value": "rt"},
+					{"Variable_name": "indexed_documents", "Value": "1500"},
+					{"Variable_name": "killed_documents", "Value": "20"},
+					{"Variable_name": "ram_bytes", "Value": float64(4096)},
+					{"Variable_name": "disk_bytes", "Value": "1048576"},
+					{"Variable_name": "ram_chunk", "Value": "2048"},
+					{"Variable_name": "ram_chunk_segments_count", "Value": "3"},
+					{"Variable_name": "disk_chunks", "Value": "2"},
+					{"Variable_name": "query_time_1min", "Value": `{"queries":0}`},
+				}, nil
+			}
+			return []map[string]interface{}{}, nil
+		},
+	}
+	handler := newMockHandler(mockClient)
+
+	details, err := handler.DescribeTableDetails(context.Background(), DescribeTableArgs{Table: "products", Details: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "products", details.Table)
+	assert.Equal(t, showCreateTable, details.CreateTable)
+	require.Len(t, details.Columns, 4)
+	assert.Equal(t, "id", details.Columns[0].Name)
+	assert.Empty(t, details.Columns[0].Properties)
+	assert.Equal(t, ColumnDetails{Name: "price", Type: "float", Properties: []string{"columnar"}, Options: map[string]string{"engine": "columnar"}}, details.Columns[2])
+	assert.Equal(t, "4", details.Columns[3].Options["knn_dims"])
+
+	// SHOW TABLE SETTINGS wins, SHOW CREATE TABLE fills the rest
+	assert.Equal(t, "268435456", details.Settings["rt_mem_limit"])
+	assert.Equal(t, "non_cont, U+00E9->e", details.Settings["charset_table"])
+	assert.Equal(t, "stem_en", details.Settings["morphology"])
+
+	assert.Equal(t, "rt", details.Status.Type)
+	assert.Equal(t, int64(1500), details.Status.Documents)
+	assert.Equal(t, int64(20), details.Status.KilledDocuments)
+	assert.Equal(t, int64(4096), details.Status.RAMBytes)
+	assert.Equal(t, int64(1048576), details.Status.DiskBytes)
+	assert.Equal(t, int64(2048), details.Status.RAMChunkBytes)
+	assert.Equal(t, int64(3), details.Status.RAMChunkSegments)
+	assert.Equal(t, int64(2), details.Status.DiskChunks)
+	assert.Equal(t, `{"queries":0}`, details.Status.Raw["query_time_1min"])
+
+	_, err = handler.DescribeTableDetails(context.Background(), DescribeTableArgs{Table: "products STATUS; DROP"})
+	require.Error(t, err)
+	assert.Len(t, mockClient.ExecuteSQLCalls(), 4)
+}
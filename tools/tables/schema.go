@@ -0,0 +1,231 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TableSchema describes the desired columns and settings of a table
+type TableSchema struct {
+	Columns  []Column       `json:"columns" jsonschema:"required" description:"Desired columns besides the implicit id"`
+	Settings *TableSettings `json:"settings,omitempty" description:"Desired table settings; settings left empty are not compared"`
+}
+
+// describeTypes maps column types reported by DESCRIBE to the types used in table definitions
+var describeTypes = map[string]string{
+	"uint":   TypeInteger,
+	"int":    TypeInteger,
+	"mva":    TypeMulti,
+	"mva64":  TypeMulti64,
+	"multi":  TypeMulti,
+	"string": TypeString,
+}
+
+// ParseDescribe converts DESCRIBE rows into columns, leaving out the implicit id. Text columns
+// carry their indexed and stored flags, KNN options are not reported by DESCRIBE.
+func ParseDescribe(rows []map[string]interface{}) []Column {
+	columns := make([]Column, 0, len(rows))
+	for _, row := range rows {
+		name, _ := row["Field"].(string)
+		columnType, _ := row["Type"].(string)
+		if name == "" || name == "id" {
+			continue
+		}
+
+		columnType = strings.ToLower(columnType)
+		if mapped, ok := describeTypes[columnType]; ok {
+			columnType = mapped
+		}
+		column := Column{Name: name, Type: columnType}
+
+		if columnType == TypeText {
+			properties, _ := row["Properties"].(string)
+			flags := strings.Fields(properties)
+			indexed, stored := false, false
+			for _, flag := range flags {
+				switch flag {
+				case "indexed":
+					indexed = true
+				case "stored":
+					stored = true
+				}
+			}
+			column.Indexed = &indexed
+			column.Stored = &stored
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// ParseSettings parses the "name = value" lines reported by SHOW TABLE ... SETTINGS
+func ParseSettings(rows []map[string]interface{}) map[string]string {
+	settings := make(map[string]string)
+	for _, row := range rows {
+		text, _ := row["Value"].(string)
+		for _, line := range strings.Split(text, "\n") {
+			name, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			settings[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return settings
+}
+
+// DiffSchema returns the ALTER TABLE statements that turn a table with the current columns and
+// settings into the desired schema: columns missing from the table are added, columns missing
+// from the desired schema are dropped and differing settings are changed. Column type and text
+// flag changes cannot be applied in place and are rejected.
+func DiffSchema(tableName string, current []Column, settings map[string]string, desired TableSchema) ([]string, error) {
+	existing := make(map[string]Column, len(current))
+	for _, column := range current {
+		existing[strings.ToLower(column.Name)] = column
+	}
+
+	wanted := make(map[string]bool, len(desired.Columns))
+	var statements []string
+	for _, column := range desired.Columns {
+		definition, err := ColumnDefinition(column)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(column.Name)
+		if wanted[name] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidSchema, column.Name)
+		}
+		wanted[name] = true
+
+		have, ok := existing[name]
+		if !ok {
+			statements = append(statements, "ALTER TABLE "+tableName+" ADD COLUMN "+definition)
+			continue
+		}
+		if err := compareColumn(have, column); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, column := range current {
+		if !wanted[strings.ToLower(column.Name)] {
+			statements = append(statements, "ALTER TABLE "+tableName+" DROP COLUMN "+column.Name)
+		}
+	}
+
+	if desired.Settings != nil {
+		changed, err := changedSettings(settings, *desired.Settings)
+		if err != nil {
+			return nil, err
+		}
+		if len(changed) > 0 {
+			statements = append(statements, "ALTER TABLE "+tableName+" "+strings.Join(changed, " "))
+		}
+	}
+
+	return statements, nil
+}
+
+// compareColumn rejects differences between an existing and a desired column that ALTER TABLE
+// cannot apply
+func compareColumn(have, want Column) error {
+	wantType := strings.ToLower(want.Type)
+	if have.Type != wantType {
+		return fmt.Errorf("%w: column %s is %s, changing it to %s requires dropping it", ErrInvalidSchema, want.Name, have.Type, wantType)
+	}
+	if wantType != TypeText {
+		return nil
+	}
+
+	indexed := want.Indexed == nil || *want.Indexed
+	stored := want.Stored == nil || *want.Stored
+	if (have.Indexed != nil && *have.Indexed != indexed) || (have.Stored != nil && *have.Stored != stored) {
+		return fmt.Errorf("%w: indexed and stored flags of text column %s cannot be changed", ErrInvalidSchema, want.Name)
+	}
+	return nil
+}
+
+// settingDefaults holds the values of settings the server does not report when left at default
+var settingDefaults = map[string]string{
+	"min_infix_len": "0",
+	"engine":        "rowwise",
+}
+
+// changedSettings renders the desired settings whose values differ from the current ones
+func changedSettings(current map[string]string, desired TableSettings) ([]string, error) {
+	values, err := settingValues(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, value := range values {
+		have, ok := current[value.name]
+		if !ok {
+			have = settingDefaults[value.name]
+		}
+		if normalizeSetting(value.name, have) == normalizeSetting(value.name, value.value) {
+			continue
+		}
+		option, err := value.option()
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, option)
+	}
+	return changed, nil
+}
+
+// normalizeSetting makes equal setting values compare equal regardless of spacing, case and size units
+func normalizeSetting(name, value string) string {
+	value = strings.ToLower(strings.Join(strings.Fields(value), ""))
+	if name == "rt_mem_limit" && value != "" {
+		multiplier := int64(1)
+		switch value[len(value)-1] {
+		case 'k':
+			multiplier = 1 << 10
+		case 'm':
+			multiplier = 1 << 20
+		case 'g':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			value = value[:len(value)-1]
+		}
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return strconv.FormatInt(size*multiplier, 10)
+		}
+	}
+	return value
+}
+
+// DiffTable returns the ALTER TABLE statements that turn the live schema of a table into the
+// desired one
+func (h *Handler) DiffTable(ctx context.Context, table, cluster string, desired TableSchema) ([]string, error) {
+	columns, settings, err := h.currentSchema(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s has no columns", table)
+	}
+
+	return DiffSchema(h.buildTableName(cluster, table), columns, settings, desired)
+}
+
+// currentSchema reads the columns and settings of a table
+func (h *Handler) currentSchema(ctx context.Context, table string) ([]Column, map[string]string, error) {
+	rows, err := h.client.ExecuteSQL(ctx, "DESCRIBE "+table)
+	if err != nil {
+		return nil, nil, fmt.Errorf("describe table failed: %w", err)
+	}
+
+	settingRows, err := h.client.ExecuteSQL(ctx, "SHOW TABLE "+table+" SETTINGS")
+	if err != nil {
+		return nil, nil, fmt.Errorf("show table settings failed: %w", err)
+	}
+
+	return ParseDescribe(rows), ParseSettings(settingRows), nil
+}
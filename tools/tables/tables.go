@@ -31,8 +31,9 @@ type ShowTablesArgs struct {
 
 // DescribeTableArgs represents arguments for describe_table tool
 type DescribeTableArgs struct {
-	Table   string `json:"table" description:"Table name to describe"`
+	Table   string `json:"table" jsonschema:"required" description:"Table name to describe"`
 	Cluster string `json:"cluster,omitempty" description:"Cluster name (optional)"`
+	Details bool   `json:"details,omitempty" description:"Also return column options, tokenizer/morphology settings, document count and sizes from SHOW CREATE TABLE, SHOW TABLE SETTINGS and SHOW TABLE STATUS"`
 }
 
 // ShowTables lists all tables in Manticore
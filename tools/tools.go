@@ -4,28 +4,38 @@ import (
 	"log/slog"
 
 	"manticore-mcp-server/client"
+	"manticore-mcp-server/tools/aggregate"
 	"manticore-mcp-server/tools/clusters"
 	"manticore-mcp-server/tools/documents"
+	"manticore-mcp-server/tools/migrations"
+	"manticore-mcp-server/tools/rawsql"
 	"manticore-mcp-server/tools/search"
 	"manticore-mcp-server/tools/tables"
 )
 
 // Handler aggregates all tool handlers
 type Handler struct {
-	Search    *search.Handler
-	Tables    *tables.Handler
-	Documents *documents.Handler
-	Clusters  *clusters.Handler
-	logger    *slog.Logger
+	Search     *search.Handler
+	Aggregate  *aggregate.Handler
+	Tables     *tables.Handler
+	Documents  *documents.Handler
+	Clusters   *clusters.Handler
+	RawSQL     *rawsql.Handler
+	Migrations *migrations.Handler
+	logger     *slog.Logger
 }
 
 // NewHandler creates a new aggregated tool handler
 func NewHandler(c client.ManticoreClient, logger *slog.Logger) *Handler {
+	tablesHandler := tables.NewHandler(c, logger)
 	return &Handler{
-		Search:    search.NewHandler(c, logger),
-		Tables:    tables.NewHandler(c, logger),
-		Documents: documents.NewHandler(c, logger),
-		Clusters:  clusters.NewHandler(c, logger),
-		logger:    logger,
+		Search:     search.NewHandler(c, logger),
+		Aggregate:  aggregate.NewHandler(c, logger),
+		Tables:     tablesHandler,
+		Documents:  documents.NewHandler(c, logger),
+		Clusters:   clusters.NewHandler(c, logger),
+		RawSQL:     rawsql.NewHandler(c, logger),
+		Migrations: migrations.NewHandler(c, tablesHandler, logger),
+		logger:     logger,
 	}
 }